@@ -2,22 +2,44 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 	"wyy/route"
 
 	"wyy/internal/config"
 	"wyy/internal/handler"
+	handler3 "wyy/internal/handler/catalog"
+	handler4 "wyy/internal/handler/comment"
 	handler2 "wyy/internal/handler/discover"
+	handler5 "wyy/internal/handler/media"
 	"wyy/internal/repo"
+	repo3 "wyy/internal/repo/catalog"
+	repo9 "wyy/internal/repo/cloud"
+	repo7 "wyy/internal/repo/comment"
 	repo2 "wyy/internal/repo/discover"
+	repo6 "wyy/internal/repo/library"
+	repo5 "wyy/internal/repo/playlist"
+	repo4 "wyy/internal/repo/search"
+	repo8 "wyy/internal/repo/upload"
 	"wyy/internal/service"
+	service3 "wyy/internal/service/catalog"
+	service10 "wyy/internal/service/cloud"
+	service7 "wyy/internal/service/comment"
 	service2 "wyy/internal/service/discover"
+	service6 "wyy/internal/service/library"
+	service8 "wyy/internal/service/media"
+	service5 "wyy/internal/service/playlist"
+	service4 "wyy/internal/service/search"
+	service9 "wyy/internal/service/upload"
+	"wyy/internal/storage"
+	"wyy/internal/urlsign"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -28,6 +50,7 @@ type App struct {
 	cfg    *config.Config
 	db     *gorm.DB
 	router *gin.Engine
+	jobs   []func(ctx context.Context) // 随服务启动的后台任务
 }
 
 func NewApp(cfgPath string) (*App, error) {
@@ -43,6 +66,31 @@ func NewApp(cfgPath string) (*App, error) {
 		return nil, fmt.Errorf("init db: %w", err)
 	}
 
+	if err := repo3.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+	if err := repo2.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+	if err := repo4.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+	if err := repo5.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+	if err := repo6.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+	if err := repo7.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+	if err := repo8.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+	if err := repo9.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("migrate db: %w", err)
+	}
+
 	// 3. 设置 Gin 模式
 	gin.SetMode(cfg.Server.Mode)
 
@@ -75,21 +123,237 @@ func NewApp(cfgPath string) (*App, error) {
 	userService := service.NewUserService(userRepo)
 	userHandler := handler.NewUserHandler(userService)
 
+	urlSigner, err := urlsign.NewSigner(cfg.Storage.SignSecret, time.Duration(cfg.Storage.URLExpireMinutes)*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("init url signer: %w", err)
+	}
+	if cfg.Storage.SignSecret == "" {
+		log.Println("storage.sign_secret is empty, using a random key; media urls expire on restart")
+	}
+	// 响应中的本站图片地址统一签名，图片接口只接受签名链接
+	imageLinker := service8.NewImageLinker(urlSigner)
+
 	recommendRepo := repo2.NewRecommendRepo(db)
 	recommendService := service2.NewRecommendService(recommendRepo)
-	recommendHandler := handler2.NewRecommendHandler(recommendService)
+	songRepo := repo2.NewSongRepo(db)
+	feedbackService := service2.NewFeedbackService(repo2.NewFeedbackRepo(db), repo2.NewUserProfileRepo(db), songRepo)
+	cacheRepo := repo2.NewCacheRepo(db)
+	// 已听过滤在推荐和各个用户行为入口之间共享，新的播放、喜欢立即生效
+	listenedFilter := service2.NewListenedFilter(repo2.NewUserActionRepo(db))
+	recommendationService, err := newRecommendationService(cfg.Recommend, db, cacheRepo, listenedFilter)
+	if err != nil {
+		return nil, fmt.Errorf("init recommendation: %w", err)
+	}
+	dailyService, err := service2.NewDailyService(recommendationService, repo2.NewDailyRepo(db), songRepo, service2.DailyOptions{
+		Size:         cfg.Recommend.DailySize,
+		RunAt:        cfg.Recommend.DailyRunAt,
+		Location:     loadLocation(cfg.Recommend.Timezone),
+		ActiveWindow: time.Duration(cfg.Recommend.DailyActiveDays) * 24 * time.Hour,
+		Workers:      cfg.Recommend.DailyWorkers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init daily recommend: %w", err)
+	}
+	fmService, err := newFMService(db, cacheRepo, feedbackService)
+	if err != nil {
+		return nil, fmt.Errorf("init fm: %w", err)
+	}
+	fmService.SetListenedFilter(listenedFilter)
+	bannerService := service2.NewBannerService(repo2.NewBannerRepo(db))
+	playlistRecommendService := newPlaylistRecommendService(db, songRepo)
+	recommendHandler := handler2.NewRecommendHandler(recommendService, feedbackService, dailyService, fmService, bannerService, playlistRecommendService, imageLinker)
+	bannerHandler := handler2.NewBannerHandler(bannerService, imageLinker, cfg.Server.AdminToken)
+	chartService := service2.NewChartService(repo2.NewChartRepo(db), songRepo, service2.ChartOptions{
+		RefreshInterval: time.Duration(cfg.Chart.RefreshMinutes) * time.Minute,
+		Size:            cfg.Chart.Size,
+		Location:        loadLocation(cfg.Recommend.Timezone),
+		OriginalTag:     cfg.Chart.OriginalTag,
+		Tags:            cfg.Chart.Tags,
+	})
+	toplistHandler := handler2.NewToplistHandler(chartService, imageLinker)
+	releaseHandler := handler2.NewReleaseHandler(service2.NewReleaseService(repo2.NewReleaseRepo(db), repo2.NewUserSettingsRepo(db), service2.ReleaseOptions{
+		Location: loadLocation(cfg.Recommend.Timezone),
+	}), imageLinker)
+	historyService := service2.NewHistoryService(repo2.NewHistoryRepo(db), songRepo, service2.HistoryOptions{
+		Location: loadLocation(cfg.Recommend.Timezone),
+	})
+	historyService.SetListenedFilter(listenedFilter)
+	historyHandler := handler2.NewHistoryHandler(historyService, imageLinker)
+
+	catalogSongRepo := repo3.NewSongRepo(db)
+	artistRepo := repo3.NewArtistRepo(db)
+	albumRepo := repo3.NewAlbumRepo(db)
+	songHandler := handler3.NewSongHandler(service3.NewSongService(catalogSongRepo), imageLinker)
+	lyricService := service3.NewLyricService(repo3.NewLyricRepo(db), catalogSongRepo)
+	lyricHandler := handler3.NewLyricHandler(lyricService)
+	lyricAdminHandler := handler3.NewLyricAdminHandler(lyricService, cfg.Server.AdminToken)
+	artistHandler := handler3.NewArtistHandler(service3.NewArtistService(artistRepo, albumRepo, catalogSongRepo), imageLinker)
+	albumHandler := handler3.NewAlbumHandler(service3.NewAlbumService(albumRepo, artistRepo, catalogSongRepo), imageLinker)
+	mediaStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("init storage: %w", err)
+	}
+	songFileRepo := repo3.NewSongFileRepo(db)
+	cloudRepo := repo9.NewCloudRepo(db)
+	streamHandler := handler3.NewStreamHandler(service3.NewStreamService(catalogSongRepo, songFileRepo, cloudRepo, mediaStorage), urlSigner)
+	imageService := service8.NewImageService(mediaStorage)
+	imageHandler := handler5.NewImageHandler(imageService, urlSigner)
+	imageAdminHandler := handler5.NewImageAdminHandler(imageService, cfg.Server.AdminToken)
+	uploadService := service9.NewUploadService(repo8.NewUploadRepo(db), mediaStorage, service9.UploadOptions{
+		MaxSize:    int64(cfg.Upload.MaxSizeMB) << 20,
+		ChunkSize:  int64(cfg.Upload.ChunkSizeMB) << 20,
+		SessionTTL: time.Duration(cfg.Upload.SessionTTLHours) * time.Hour,
+	})
+	importService := service9.NewImportService(catalogSongRepo, artistRepo, albumRepo, songFileRepo, imageService, mediaStorage)
+	uploadAdminHandler := handler3.NewUploadAdminHandler(uploadService, importService, imageLinker, cfg.Server.AdminToken)
+	cloudService := service10.NewCloudService(cloudRepo, catalogSongRepo, imageService, mediaStorage, int64(cfg.Cloud.QuotaMB)<<20)
+	cloudHandler := handler3.NewCloudHandler(cloudService, uploadService, imageLinker)
+	playlistRepo := repo5.NewPlaylistRepo(db)
+	playlistHandler := handler3.NewPlaylistHandler(service5.NewPlaylistService(playlistRepo, catalogSongRepo, imageService), imageLinker)
+	commentService := service7.NewCommentService(repo7.NewCommentRepo(db), catalogSongRepo, albumRepo, playlistRepo)
+	commentHandler := handler4.NewCommentHandler(commentService)
+	commentAdminHandler := handler4.NewCommentAdminHandler(commentService, cfg.Server.AdminToken)
+	libraryService := service6.NewLibraryService(repo6.NewLibraryRepo(db), catalogSongRepo, albumRepo, artistRepo, playlistRepo)
+	libraryService.OnSongLiked(func(userID, songID int64) {
+		listenedFilter.MarkPlayed(strconv.FormatInt(userID, 10), strconv.FormatInt(songID, 10))
+	})
+	libraryHandler := handler3.NewLibraryHandler(libraryService, imageLinker)
+	fmService.SetSongLiker(newFMSongLiker(libraryService))
+	searchService := service4.NewSearchService(repo4.NewSearchRepo(db), catalogSongRepo, artistRepo, albumRepo, playlistRepo, service4.SearchOptions{
+		SyncInterval:    time.Duration(cfg.Search.SyncIntervalSeconds) * time.Second,
+		RebuildInterval: time.Duration(cfg.Search.RebuildIntervalMinutes) * time.Minute,
+	})
+	hotSearchService := service4.NewHotSearchService(repo4.NewHotSearchRepo(db), service4.HotSearchOptions{
+		Window:   time.Duration(cfg.Search.HotWindowHours) * time.Hour,
+		HalfLife: time.Duration(cfg.Search.HotHalfLifeHours) * time.Hour,
+		Size:     cfg.Search.HotSize,
+	})
+	searchHandler := handler3.NewSearchHandler(searchService, hotSearchService, imageLinker)
+	searchAdminHandler := handler3.NewSearchAdminHandler(hotSearchService, cfg.Server.AdminToken)
 
 	// 6. 注册路由
-	route.RegisterRoutes(engine, userHandler, recommendHandler) // 确认函数签名匹配
+	route.RegisterRoutes(engine, userHandler, recommendHandler, bannerHandler, songHandler, artistHandler, albumHandler, searchHandler, searchAdminHandler, playlistHandler, libraryHandler, historyHandler, commentHandler, commentAdminHandler, streamHandler, imageHandler, imageAdminHandler, uploadAdminHandler, cloudHandler, lyricHandler, lyricAdminHandler, toplistHandler, releaseHandler) // 确认函数签名匹配
 
 	// 返回 App 实例
 	return &App{
 		cfg:    cfg,
 		db:     db,
 		router: engine,
+		jobs:   []func(ctx context.Context){dailyService.Start, searchService.Start, uploadService.Start, chartService.Start},
 	}, nil
 }
 
+// newRecommendationService 组装推荐流水线：多路召回 -> 排序 -> 过滤 -> 混排
+func newRecommendationService(cfg config.RecommendConfig, db *gorm.DB, cacheRepo repo2.CacheRepo, listened *service2.ListenedFilter) (*service2.RecommendationService, error) {
+	userActionRepo := repo2.NewUserActionRepo(db)
+	songRepo := repo2.NewSongRepo(db)
+	impressionRepo := repo2.NewImpressionRepo(db)
+	settingsRepo := repo2.NewUserSettingsRepo(db)
+
+	profileRepo := repo2.NewUserProfileRepo(db)
+
+	recallers := []service2.Recommender{
+		service2.NewUserBasedCFRecommender(userActionRepo, cacheRepo, songRepo, 20, 0.1),
+		service2.NewSimilarSongRecommender(userActionRepo, songRepo, 3),
+		service2.NewArtistNewRecommender(profileRepo, songRepo, 90*24*time.Hour, 5),
+	}
+
+	// 模型文件不存在或与当前特征不匹配时按召回得分排序
+	var model service2.RankModel
+	if cfg.RankModelPath != "" {
+		lr, err := service2.LoadLogisticRegression(cfg.RankModelPath)
+		if err != nil {
+			log.Printf("rank model not loaded, fallback to recall score: %v", err)
+		} else {
+			model = lr
+		}
+	}
+	extractor := service2.NewFeatureExtractor(profileRepo, songRepo, repo2.NewSongStatsRepo(db))
+	ranker := service2.NewLTRRanker(extractor, model)
+
+	filters, err := service2.BuildFilterChain(cfg.Filters, service2.FilterDeps{
+		UserActionRepo:        userActionRepo,
+		SongRepo:              songRepo,
+		ImpressionRepo:        impressionRepo,
+		FeedbackRepo:          repo2.NewFeedbackRepo(db),
+		SettingsRepo:          settingsRepo,
+		Listened:              listened,
+		FatigueWindow:         time.Duration(cfg.FatigueWindowHours) * time.Hour,
+		FatigueMaxImpressions: cfg.FatigueMaxImpressions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	svc := service2.NewRecommendationService(recallers, ranker, filters, service2.NewSimpleMixer(), songRepo)
+	svc.SetImpressionRepo(impressionRepo)
+	return svc, nil
+}
+
+// newPlaylistRecommendService 推荐歌单按偏好标签和喜欢的歌曲召回，热门歌单兜底
+func newPlaylistRecommendService(db *gorm.DB, songRepo repo2.SongRepo) *service2.PlaylistRecommendService {
+	playlistRepo := repo2.NewPlaylistRepo(db)
+	recallers := []service2.PlaylistRecaller{
+		service2.NewTagPlaylistRecaller(repo2.NewUserProfileRepo(db), playlistRepo, 5),
+		service2.NewLikedSongPlaylistRecaller(playlistRepo, songRepo, 50),
+	}
+	return service2.NewPlaylistRecommendService(recallers, service2.NewPopularPlaylistRecaller(playlistRepo), playlistRepo)
+}
+
+// newFMSongLiker 私人 FM 的喜欢经由曲库写入，与歌曲详情页的喜欢保持一致
+func newFMSongLiker(libraryService *service6.LibraryService) service2.SongLiker {
+	return func(ctx context.Context, userID, songID string) error {
+		uid, err := strconv.ParseInt(userID, 10, 64)
+		if err != nil {
+			return err
+		}
+		sid, err := strconv.ParseInt(songID, 10, 64)
+		if err != nil {
+			return service2.ErrFeedbackTarget
+		}
+		err = libraryService.LikeSong(ctx, uid, sid)
+		if errors.Is(err, service6.ErrTargetNotFound) {
+			return service2.ErrFeedbackTarget
+		}
+		return err
+	}
+}
+
+// newFMService 私人 FM 允许重复播放听过的歌，只做版权、不感兴趣和内容过滤
+func newFMService(db *gorm.DB, cacheRepo repo2.CacheRepo, feedbackService *service2.FeedbackService) (*service2.FMService, error) {
+	userActionRepo := repo2.NewUserActionRepo(db)
+	songRepo := repo2.NewSongRepo(db)
+
+	recallers := []service2.Recommender{
+		service2.NewUserBasedCFRecommender(userActionRepo, cacheRepo, songRepo, 20, 0.1),
+		service2.NewHotRecommender(cacheRepo),
+	}
+	filters, err := service2.BuildFilterChain(
+		[]string{service2.FilterAvailability, service2.FilterBlocklist, service2.FilterExplicit},
+		service2.FilterDeps{
+			SongRepo:     songRepo,
+			FeedbackRepo: repo2.NewFeedbackRepo(db),
+			SettingsRepo: repo2.NewUserSettingsRepo(db),
+		})
+	if err != nil {
+		return nil, err
+	}
+	return service2.NewFMService(recallers, filters, songRepo, userActionRepo, feedbackService), nil
+}
+
+// loadLocation 加载时区，失败时使用本地时区
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("load timezone %q: %v, fallback to local", name, err)
+		return time.Local
+	}
+	return loc
+}
+
 func (a *App) Close() error {
 	sqlDB, err := a.db.DB()
 	if err != nil {
@@ -105,6 +369,12 @@ func (a *App) Run() error {
 		Handler: a.router,
 	}
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	for _, job := range a.jobs {
+		go job(jobCtx)
+	}
+
 	go func() {
 		log.Printf("Server starting on %s", addr)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -116,6 +386,7 @@ func (a *App) Run() error {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -7,6 +7,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata" // 容器内可能没有时区数据库
 )
 
 func main() {
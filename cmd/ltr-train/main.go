@@ -0,0 +1,74 @@
+// ltr-train 离线训练推荐排序模型
+//
+// 读取最近若干天的曝光日志，结合用户后续行为打标签，训练逻辑回归模型并写入文件：
+//
+//	go run ./cmd/ltr-train -config configs/config.yaml -days 7 -out models/rank_lr.json
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"wyy/internal/config"
+	"wyy/internal/repo"
+	repo2 "wyy/internal/repo/discover"
+	service2 "wyy/internal/service/discover"
+)
+
+func main() {
+	cfgPath := flag.String("config", "configs/config.yaml", "配置文件路径")
+	days := flag.Int("days", 7, "使用最近多少天的曝光日志")
+	limit := flag.Int("limit", 1000000, "最多读取的曝光条数")
+	out := flag.String("out", "", "模型输出路径，默认使用配置中的 recommend.rank_model_path")
+	epochs := flag.Int("epochs", 20, "训练轮数")
+	lr := flag.Float64("lr", 0.05, "学习率")
+	l2 := flag.Float64("l2", 1e-4, "L2 正则系数")
+	flag.Parse()
+
+	cfg, err := config.Load(*cfgPath)
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	db, err := repo.NewDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("init db: %v", err)
+	}
+
+	// 曝光发生后需要等待标签窗口结束，训练数据截止到一天前
+	until := time.Now().Add(-24 * time.Hour)
+	since := until.AddDate(0, 0, -*days)
+
+	trainer := service2.NewRankTrainer(repo2.NewImpressionRepo(db), repo2.NewUserActionRepo(db))
+	samples, err := trainer.BuildSamples(context.Background(), since.Unix(), until.Unix(), *limit)
+	if err != nil {
+		log.Fatalf("build samples: %v", err)
+	}
+	log.Printf("built %d samples from %s to %s", len(samples), since.Format(time.DateOnly), until.Format(time.DateOnly))
+
+	model, err := service2.TrainLogisticRegression(samples, service2.TrainOptions{
+		Epochs:       *epochs,
+		LearningRate: *lr,
+		L2:           *l2,
+		Seed:         time.Now().UnixNano(),
+	})
+	if err != nil {
+		log.Fatalf("train: %v", err)
+	}
+	log.Printf("train logloss: %.4f", service2.LogLoss(model, samples))
+
+	path := *out
+	if path == "" {
+		path = cfg.Recommend.RankModelPath
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Fatalf("create model dir: %v", err)
+	}
+	if err := model.Save(path); err != nil {
+		log.Fatalf("save model: %v", err)
+	}
+	log.Printf("model saved to %s", path)
+}
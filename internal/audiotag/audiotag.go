@@ -0,0 +1,150 @@
+// Package audiotag 纯 Go 解析音频文件的标签和时长，支持 MP3（ID3v1/ID3v2）、FLAC、
+// Ogg Vorbis/Opus（Vorbis Comment）和 MP4/M4A（iTunes 元数据）
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported audio format")
+	ErrMalformed   = errors.New("malformed audio file")
+)
+
+// 音频格式
+const (
+	FormatMP3  = "mp3"
+	FormatFLAC = "flac"
+	FormatOgg  = "ogg"
+	FormatOpus = "opus"
+	FormatM4A  = "m4a"
+)
+
+// maxBlockSize 单个标签块读入内存的上限，超过视为文件损坏
+const maxBlockSize = 32 << 20
+
+// Picture 内嵌的封面图片
+type Picture struct {
+	MIMEType string
+	Data     []byte
+}
+
+// Metadata 音频文件的标签和流信息，缺失的字段为零值
+type Metadata struct {
+	Format      string
+	Title       string
+	Artist      string // 多位歌手以 "/" 分隔
+	Album       string
+	AlbumArtist string
+	Genre       string
+	Year        int
+	TrackNo     int
+	DiscNo      int
+	Duration    time.Duration
+	Bitrate     int // kbps，可变码率时为平均值
+	SampleRate  int
+	Lossless    bool
+	Picture     *Picture
+
+	frontCover bool // Picture 是否为封面（而非其他类型的图片）
+}
+
+// Read 按文件头识别格式并解析，无法识别时返回 ErrUnsupported
+func Read(r io.ReadSeeker) (*Metadata, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, 12)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, ErrUnsupported
+	}
+
+	m := &Metadata{}
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")), mpegSync(head):
+		err = readMP3(r, size, m)
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		err = readFLAC(r, 0, m)
+	case bytes.HasPrefix(head, []byte("OggS")):
+		err = readOgg(r, size, m)
+	case string(head[4:8]) == "ftyp":
+		err = readMP4(r, size, m)
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ms := m.Duration.Milliseconds(); m.Bitrate == 0 && ms > 0 {
+		m.Bitrate = int(size * 8 / ms)
+	}
+	return m, nil
+}
+
+// setPicture 记录图片，封面优先于其他类型的图片
+func (m *Metadata) setPicture(p *Picture, front bool) {
+	if p == nil || len(p.Data) == 0 {
+		return
+	}
+	if m.Picture == nil || (front && !m.frontCover) {
+		m.Picture = p
+		m.frontCover = front
+	}
+}
+
+// setText 只填充尚未设置的字段，先解析到的标签优先
+func setText(dst *string, value string) {
+	value = strings.TrimSpace(value)
+	if *dst == "" && value != "" {
+		*dst = value
+	}
+}
+
+// setNumber 解析 "3" 或 "3/12" 形式的序号，以及 "2019-05-01" 形式日期中的年份
+func setNumber(dst *int, value string) {
+	if *dst != 0 {
+		return
+	}
+	value = strings.TrimSpace(value)
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	if n, err := strconv.Atoi(value[:end]); err == nil && n > 0 {
+		*dst = n
+	}
+}
+
+// readAt 从 offset 处读取 n 字节
+func readAt(r io.ReadSeeker, offset int64, n int) ([]byte, error) {
+	if n < 0 || n > maxBlockSize {
+		return nil, fmt.Errorf("%w: block too large", ErrMalformed)
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return buf, nil
+}
+
+func be24(b []byte) int {
+	return int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+}
+
+func be32(b []byte) int {
+	return int(binary.BigEndian.Uint32(b))
+}
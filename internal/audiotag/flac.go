@@ -0,0 +1,159 @@
+package audiotag
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// FLAC 元数据块类型
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+	flacPicture       = 6
+)
+
+// readFLAC 解析从 offset 开始的 FLAC 流（以 fLaC 开头）
+func readFLAC(r io.ReadSeeker, offset int64, m *Metadata) error {
+	pos := offset + 4
+	var sampleRate int
+	var totalSamples uint64
+	for {
+		header, err := readAt(r, pos, 4)
+		if err != nil {
+			return err
+		}
+		last, blockType, length := header[0]&0x80 != 0, header[0]&0x7f, be24(header[1:])
+		pos += 4
+		switch blockType {
+		case flacStreamInfo, flacVorbisComment, flacPicture:
+			data, err := readAt(r, pos, length)
+			if err != nil {
+				return err
+			}
+			switch blockType {
+			case flacStreamInfo:
+				if len(data) < 18 {
+					return fmt.Errorf("%w: short streaminfo", ErrMalformed)
+				}
+				sampleRate = int(data[10])<<12 | int(data[11])<<4 | int(data[12])>>4
+				totalSamples = uint64(data[13]&0x0f)<<32 | uint64(binary.BigEndian.Uint32(data[14:18]))
+			case flacVorbisComment:
+				parseVorbisComment(data, m)
+			case flacPicture:
+				pic, picType := parsePictureBlock(data)
+				m.setPicture(pic, picType == 3)
+			}
+		}
+		pos += int64(length)
+		if last {
+			break
+		}
+	}
+	if sampleRate == 0 {
+		return fmt.Errorf("%w: missing streaminfo", ErrMalformed)
+	}
+	m.Format = FormatFLAC
+	m.Lossless = true
+	m.SampleRate = sampleRate
+	m.Duration = time.Duration(totalSamples * uint64(time.Second) / uint64(sampleRate))
+	return nil
+}
+
+// parseVorbisComment 解析 Vorbis Comment（小端长度 + "KEY=value"），FLAC 与 Ogg 共用
+func parseVorbisComment(b []byte, m *Metadata) {
+	next := func() ([]byte, bool) {
+		if len(b) < 4 {
+			return nil, false
+		}
+		n := int(binary.LittleEndian.Uint32(b))
+		if n < 0 || 4+n > len(b) {
+			return nil, false
+		}
+		v := b[4 : 4+n]
+		b = b[4+n:]
+		return v, true
+	}
+	if _, ok := next(); !ok { // vendor
+		return
+	}
+	if len(b) < 4 {
+		return
+	}
+	count := int(binary.LittleEndian.Uint32(b))
+	b = b[4:]
+
+	var artists []string
+	for i := 0; i < count; i++ {
+		entry, ok := next()
+		if !ok {
+			break
+		}
+		key, value, ok := strings.Cut(string(entry), "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(key) {
+		case "TITLE":
+			setText(&m.Title, value)
+		case "ARTIST":
+			if value = strings.TrimSpace(value); value != "" {
+				artists = append(artists, value)
+			}
+		case "ALBUM":
+			setText(&m.Album, value)
+		case "ALBUMARTIST", "ALBUM ARTIST":
+			setText(&m.AlbumArtist, value)
+		case "GENRE":
+			setText(&m.Genre, value)
+		case "DATE", "YEAR":
+			setNumber(&m.Year, value)
+		case "TRACKNUMBER":
+			setNumber(&m.TrackNo, value)
+		case "DISCNUMBER":
+			setNumber(&m.DiscNo, value)
+		case "METADATA_BLOCK_PICTURE":
+			if data, err := base64.StdEncoding.DecodeString(value); err == nil {
+				pic, picType := parsePictureBlock(data)
+				m.setPicture(pic, picType == 3)
+			}
+		}
+	}
+	// 多个 ARTIST 字段表示多位歌手
+	setText(&m.Artist, strings.Join(artists, "/"))
+}
+
+// parsePictureBlock 解析 FLAC PICTURE 块，返回图片和图片类型（3 为封面）
+func parsePictureBlock(b []byte) (*Picture, int) {
+	read32 := func() (int, bool) {
+		if len(b) < 4 {
+			return 0, false
+		}
+		v := be32(b)
+		b = b[4:]
+		return v, true
+	}
+	picType, ok := read32()
+	if !ok {
+		return nil, 0
+	}
+	mimeLen, ok := read32()
+	if !ok || mimeLen < 0 || mimeLen > len(b) {
+		return nil, 0
+	}
+	mime := string(b[:mimeLen])
+	b = b[mimeLen:]
+	descLen, ok := read32()
+	if !ok || descLen < 0 || descLen+16 > len(b) {
+		return nil, 0
+	}
+	b = b[descLen+16:] // 描述、宽、高、色深、索引色数
+	dataLen, ok := read32()
+	if !ok || dataLen < 0 || dataLen > len(b) {
+		return nil, 0
+	}
+	return &Picture{MIMEType: mime, Data: b[:dataLen]}, picType
+}
@@ -0,0 +1,286 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// ID3v2.2 的三字符帧名对应的 v2.3/v2.4 帧名
+var id3v22Frames = map[string]string{
+	"TT2": "TIT2",
+	"TP1": "TPE1",
+	"TP2": "TPE2",
+	"TAL": "TALB",
+	"TCO": "TCON",
+	"TRK": "TRCK",
+	"TPA": "TPOS",
+	"TYE": "TYER",
+	"TLE": "TLEN",
+	"PIC": "APIC",
+}
+
+// readID3v2 读取文件开头的 ID3v2 标签，返回标签占用的字节数，没有标签时返回 0
+func readID3v2(r io.ReadSeeker, m *Metadata) (int64, error) {
+	header, err := readAt(r, 0, 10)
+	if err != nil || string(header[:3]) != "ID3" {
+		return 0, nil
+	}
+	major, flags := header[3], header[5]
+	size := syncsafe(header[6:10])
+	total := int64(10 + size)
+	if flags&0x10 != 0 {
+		total += 10 // footer
+	}
+	if major < 2 || major > 4 {
+		return total, nil
+	}
+	body, err := readAt(r, 10, size)
+	if err != nil {
+		return 0, err
+	}
+	if flags&0x80 != 0 && major < 4 {
+		body = removeUnsync(body)
+	}
+
+	pos := 0
+	if flags&0x40 != 0 && major >= 3 && len(body) >= 4 {
+		if major == 3 {
+			pos = 4 + be32(body)
+		} else {
+			pos = syncsafe(body)
+		}
+	}
+	idLen, headerLen := 4, 10
+	if major == 2 {
+		idLen, headerLen = 3, 6
+	}
+	for pos+headerLen <= len(body) {
+		id := string(body[pos : pos+idLen])
+		if id[0] == 0 {
+			break // 填充区
+		}
+		var frameSize int
+		var frameFlags uint16
+		switch major {
+		case 2:
+			frameSize = be24(body[pos+3:])
+		case 3:
+			frameSize = be32(body[pos+4:])
+			frameFlags = binary.BigEndian.Uint16(body[pos+8:])
+		case 4:
+			frameSize = syncsafe(body[pos+4:])
+			frameFlags = binary.BigEndian.Uint16(body[pos+8:])
+		}
+		pos += headerLen
+		if frameSize < 0 || pos+frameSize > len(body) {
+			break
+		}
+		data := body[pos : pos+frameSize]
+		pos += frameSize
+
+		if major == 2 {
+			id = id3v22Frames[id]
+		}
+		if major == 3 && frameFlags&0x00c0 != 0 {
+			continue // 压缩或加密的帧
+		}
+		if major == 4 {
+			if frameFlags&0x000c != 0 {
+				continue
+			}
+			if frameFlags&0x0002 != 0 {
+				data = removeUnsync(data)
+			}
+			if frameFlags&0x0001 != 0 && len(data) >= 4 {
+				data = data[4:] // 数据长度指示
+			}
+		}
+		applyID3Frame(m, id, data, major)
+	}
+	return total, nil
+}
+
+func applyID3Frame(m *Metadata, id string, data []byte, major byte) {
+	if len(data) == 0 {
+		return
+	}
+	switch id {
+	case "TIT2":
+		setText(&m.Title, id3Text(data))
+	case "TPE1":
+		setText(&m.Artist, id3Text(data))
+	case "TPE2":
+		setText(&m.AlbumArtist, id3Text(data))
+	case "TALB":
+		setText(&m.Album, id3Text(data))
+	case "TCON":
+		setText(&m.Genre, id3Genre(id3Text(data)))
+	case "TRCK":
+		setNumber(&m.TrackNo, id3Text(data))
+	case "TPOS":
+		setNumber(&m.DiscNo, id3Text(data))
+	case "TYER", "TDRC":
+		setNumber(&m.Year, id3Text(data))
+	case "TLEN":
+		if ms, err := strconv.Atoi(strings.TrimSpace(id3Text(data))); err == nil && m.Duration == 0 {
+			m.Duration = time.Duration(ms) * time.Millisecond
+		}
+	case "APIC":
+		id3Picture(m, data, major)
+	}
+}
+
+// id3Picture 解析 APIC（v2.2 为 PIC，图片格式为三字符）
+func id3Picture(m *Metadata, data []byte, major byte) {
+	enc := data[0]
+	rest := data[1:]
+	var mime string
+	if major == 2 {
+		if len(rest) < 3 {
+			return
+		}
+		switch strings.ToUpper(string(rest[:3])) {
+		case "PNG":
+			mime = "image/png"
+		default:
+			mime = "image/jpeg"
+		}
+		rest = rest[3:]
+	} else {
+		i := bytes.IndexByte(rest, 0)
+		if i < 0 {
+			return
+		}
+		mime = string(rest[:i])
+		rest = rest[i+1:]
+	}
+	if len(rest) < 1 {
+		return
+	}
+	picType := rest[0]
+	_, rest = splitTerminated(enc, rest[1:])
+	if !strings.Contains(mime, "/") {
+		mime = "image/" + strings.ToLower(mime)
+	}
+	m.setPicture(&Picture{MIMEType: mime, Data: rest}, picType == 3)
+}
+
+// id3Text 解码文本帧，v2.4 中以 \x00 分隔的多个值以 "/" 连接
+func id3Text(data []byte) string {
+	text := decodeID3String(data[0], data[1:])
+	values := strings.FieldsFunc(text, func(r rune) bool { return r == 0 })
+	return strings.Join(values, "/")
+}
+
+// id3Genre 去掉 "(17)" 形式的 ID3v1 流派编号引用
+func id3Genre(genre string) string {
+	for strings.HasPrefix(genre, "(") {
+		end := strings.IndexByte(genre, ')')
+		if end < 0 {
+			break
+		}
+		genre = genre[end+1:]
+	}
+	if _, err := strconv.Atoi(genre); err == nil {
+		return ""
+	}
+	return genre
+}
+
+// splitTerminated 按编码切出以 \x00（UTF-16 为 \x00\x00）结尾的字符串，返回字符串和剩余字节
+func splitTerminated(enc byte, b []byte) (string, []byte) {
+	if enc == 1 || enc == 2 {
+		for i := 0; i+1 < len(b); i += 2 {
+			if b[i] == 0 && b[i+1] == 0 {
+				return decodeID3String(enc, b[:i]), b[i+2:]
+			}
+		}
+		return decodeID3String(enc, b), nil
+	}
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return decodeID3String(enc, b), nil
+	}
+	return decodeID3String(enc, b[:i]), b[i+1:]
+}
+
+// decodeID3String 0: ISO-8859-1，1: 带 BOM 的 UTF-16，2: UTF-16BE，3: UTF-8
+func decodeID3String(enc byte, b []byte) string {
+	switch enc {
+	case 1, 2:
+		bigEndian := enc == 2
+		if len(b) >= 2 && b[0] == 0xff && b[1] == 0xfe {
+			bigEndian, b = false, b[2:]
+		} else if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+			bigEndian, b = true, b[2:]
+		}
+		units := make([]uint16, 0, len(b)/2)
+		for i := 0; i+1 < len(b); i += 2 {
+			if bigEndian {
+				units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+			} else {
+				units = append(units, uint16(b[i+1])<<8|uint16(b[i]))
+			}
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	case 3:
+		return strings.TrimRight(string(b), "\x00")
+	default:
+		return strings.TrimRight(latin1(b), "\x00")
+	}
+}
+
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// syncsafe 每字节只用低 7 位的整数
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// removeUnsync 还原反同步：去掉 0xFF 之后插入的 0x00
+func removeUnsync(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		out = append(out, b[i])
+		if b[i] == 0xff && i+1 < len(b) && b[i+1] == 0 {
+			i++
+		}
+	}
+	return out
+}
+
+// readID3v1 读取文件末尾 128 字节的 ID3v1 标签，返回是否存在
+func readID3v1(r io.ReadSeeker, size int64, m *Metadata) bool {
+	if size < 128 {
+		return false
+	}
+	tag, err := readAt(r, size-128, 128)
+	if err != nil || string(tag[:3]) != "TAG" {
+		return false
+	}
+	field := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		return latin1(b)
+	}
+	setText(&m.Title, field(tag[3:33]))
+	setText(&m.Artist, field(tag[33:63]))
+	setText(&m.Album, field(tag[63:93]))
+	setNumber(&m.Year, field(tag[93:97]))
+	if tag[125] == 0 && tag[126] != 0 {
+		setNumber(&m.TrackNo, strconv.Itoa(int(tag[126])))
+	}
+	return true
+}
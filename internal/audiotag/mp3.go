@@ -0,0 +1,176 @@
+package audiotag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// 比特率表（kbps），按 [MPEG1/MPEG2][Layer I/II/III] 索引
+var mpegBitrates = [2][3][15]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+// 采样率表，按 MPEG 版本字段（0: 2.5，2: 2，3: 1）索引
+var mpegSampleRates = map[byte][3]int{
+	0: {11025, 12000, 8000},
+	2: {22050, 24000, 16000},
+	3: {44100, 48000, 32000},
+}
+
+// mpegScanLimit 标签之后查找第一个音频帧的最大范围
+const mpegScanLimit = 64 << 10
+
+// mpegFrame 解析后的 MPEG 音频帧头
+type mpegFrame struct {
+	version    byte // 0: MPEG2.5，2: MPEG2，3: MPEG1
+	layer      int  // 1、2、3
+	bitrate    int  // kbps
+	sampleRate int
+	mono       bool
+	padding    int
+}
+
+// parseMPEGHeader 解析 4 字节帧头，无效时返回 false
+func parseMPEGHeader(b []byte) (mpegFrame, bool) {
+	if len(b) < 4 || b[0] != 0xff || b[1]&0xe0 != 0xe0 {
+		return mpegFrame{}, false
+	}
+	version := b[1] >> 3 & 0x03
+	layerBits := b[1] >> 1 & 0x03
+	bitrateIdx := b[2] >> 4
+	rateIdx := b[2] >> 2 & 0x03
+	if version == 1 || layerBits == 0 || bitrateIdx == 0 || bitrateIdx == 15 || rateIdx == 3 {
+		return mpegFrame{}, false
+	}
+	f := mpegFrame{
+		version:    version,
+		layer:      4 - int(layerBits),
+		sampleRate: mpegSampleRates[version][rateIdx],
+		mono:       b[3]>>6 == 3,
+		padding:    int(b[2] >> 1 & 0x01),
+	}
+	table := 0
+	if version != 3 {
+		table = 1
+	}
+	f.bitrate = mpegBitrates[table][f.layer-1][bitrateIdx]
+	return f, true
+}
+
+// samples 每帧采样数
+func (f mpegFrame) samples() int {
+	switch {
+	case f.layer == 1:
+		return 384
+	case f.layer == 3 && f.version != 3:
+		return 576
+	default:
+		return 1152
+	}
+}
+
+// length 帧长度（字节）
+func (f mpegFrame) length() int {
+	if f.layer == 1 {
+		return (12*f.bitrate*1000/f.sampleRate + f.padding) * 4
+	}
+	return f.samples()/8*f.bitrate*1000/f.sampleRate + f.padding
+}
+
+func mpegSync(b []byte) bool {
+	_, ok := parseMPEGHeader(b)
+	return ok
+}
+
+func readMP3(r io.ReadSeeker, size int64, m *Metadata) error {
+	tagSize, err := readID3v2(r, m)
+	if err != nil {
+		return err
+	}
+	// 部分 FLAC 文件前面带有 ID3v2 标签
+	if magic, err := readAt(r, tagSize, 4); err == nil && string(magic) == "fLaC" {
+		return readFLAC(r, tagSize, m)
+	}
+	audioEnd := size
+	if readID3v1(r, size, m) {
+		audioEnd -= 128
+	}
+
+	buf, err := readAt(r, tagSize, int(min(mpegScanLimit, max(audioEnd-tagSize, 0))))
+	if err != nil {
+		return err
+	}
+	start, frame := -1, mpegFrame{}
+	for i := 0; i+4 <= len(buf); i++ {
+		f, ok := parseMPEGHeader(buf[i:])
+		if !ok {
+			continue
+		}
+		// 下一帧也有效才认为找到了同步点，避免数据中偶然出现的 0xFFE
+		next := i + f.length()
+		if next+4 <= len(buf) {
+			if _, ok := parseMPEGHeader(buf[next:]); !ok {
+				continue
+			}
+		}
+		start, frame = i, f
+		break
+	}
+	if start < 0 {
+		return fmt.Errorf("%w: no mpeg audio frame", ErrMalformed)
+	}
+
+	m.Format = FormatMP3
+	m.SampleRate = frame.sampleRate
+	audioBytes := audioEnd - tagSize - int64(start)
+	if frames := vbrFrames(buf[start:], frame); frames > 0 {
+		m.Duration = time.Duration(int64(frames) * int64(frame.samples()) * int64(time.Second) / int64(frame.sampleRate))
+		if ms := m.Duration.Milliseconds(); ms > 0 {
+			m.Bitrate = int(audioBytes * 8 / ms)
+		}
+		return nil
+	}
+	// 固定码率：按音频数据长度估算时长
+	m.Bitrate = frame.bitrate
+	if m.Duration == 0 {
+		m.Duration = time.Duration(audioBytes * 8 * int64(time.Millisecond) / int64(frame.bitrate))
+	}
+	return nil
+}
+
+// vbrFrames 读取第一帧中 Xing/Info 或 VBRI 头记录的总帧数，没有时返回 0
+func vbrFrames(b []byte, f mpegFrame) int {
+	sideInfo := 32
+	switch {
+	case f.version == 3 && f.mono:
+		sideInfo = 17
+	case f.version != 3 && f.mono:
+		sideInfo = 9
+	case f.version != 3:
+		sideInfo = 17
+	}
+	if off := 4 + sideInfo; off+12 <= len(b) {
+		tag := b[off : off+4]
+		if bytes.Equal(tag, []byte("Xing")) || bytes.Equal(tag, []byte("Info")) {
+			if be32(b[off+4:])&0x01 != 0 {
+				return be32(b[off+8:])
+			}
+			return 0
+		}
+	}
+	if off := 4 + 32; off+18 <= len(b) && bytes.Equal(b[off:off+4], []byte("VBRI")) {
+		return be32(b[off+14:])
+	}
+	return 0
+}
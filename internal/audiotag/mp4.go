@@ -0,0 +1,174 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// 需要进入的容器 atom
+var mp4Containers = map[string]bool{
+	"moov": true,
+	"trak": true,
+	"mdia": true,
+	"udta": true,
+	"ilst": true,
+	"meta": true,
+}
+
+// mp4 解析状态
+type mp4Parser struct {
+	m        *Metadata
+	hasAudio bool
+}
+
+// readMP4 只把 moov 读入内存解析，mdat 直接跳过
+func readMP4(r io.ReadSeeker, size int64, m *Metadata) error {
+	var moov []byte
+	for pos := int64(0); pos+8 <= size; {
+		header, err := readAt(r, pos, 8)
+		if err != nil {
+			return err
+		}
+		atomSize, name := int64(binary.BigEndian.Uint32(header)), string(header[4:8])
+		headerLen := int64(8)
+		switch atomSize {
+		case 0:
+			atomSize = size - pos
+		case 1:
+			ext, err := readAt(r, pos+8, 8)
+			if err != nil {
+				return err
+			}
+			atomSize, headerLen = int64(binary.BigEndian.Uint64(ext)), 16
+		}
+		if atomSize < headerLen {
+			return fmt.Errorf("%w: bad atom size", ErrMalformed)
+		}
+		if name == "moov" {
+			if moov, err = readAt(r, pos+headerLen, int(atomSize-headerLen)); err != nil {
+				return err
+			}
+			break
+		}
+		pos += atomSize
+	}
+	if moov == nil {
+		return fmt.Errorf("%w: missing moov atom", ErrMalformed)
+	}
+
+	p := &mp4Parser{m: m}
+	p.walk(moov, "moov")
+	if !p.hasAudio {
+		return fmt.Errorf("%w: no audio track", ErrUnsupported)
+	}
+	m.Format = FormatM4A
+	return nil
+}
+
+// walk 遍历 parent 容器中的子 atom
+func (p *mp4Parser) walk(b []byte, parent string) {
+	for len(b) >= 8 {
+		size := int(binary.BigEndian.Uint32(b))
+		name := string(b[4:8])
+		headerLen := 8
+		if size == 1 && len(b) >= 16 {
+			size, headerLen = int(binary.BigEndian.Uint64(b[8:])), 16
+		} else if size == 0 {
+			size = len(b)
+		}
+		if size < headerLen || size > len(b) {
+			return
+		}
+		body := b[headerLen:size]
+		b = b[size:]
+
+		switch {
+		case name == "meta":
+			// meta 通常是 full box（4 字节版本和标志），QuickTime 风格的没有
+			if len(body) >= 8 && string(body[4:8]) != "hdlr" {
+				body = body[4:]
+			}
+			p.walk(body, name)
+		case mp4Containers[name]:
+			p.walk(body, name)
+		case name == "mvhd":
+			p.movieHeader(body)
+		case name == "hdlr" && parent == "mdia":
+			if len(body) >= 12 && string(body[8:12]) == "soun" {
+				p.hasAudio = true
+			}
+		case name == "minf" || name == "stbl":
+			// 不需要采样表
+		case parent == "ilst":
+			p.item(name, body)
+		}
+	}
+}
+
+// movieHeader 从 mvhd 读取时长
+func (p *mp4Parser) movieHeader(b []byte) {
+	if len(b) < 20 {
+		return
+	}
+	var timescale, duration uint64
+	if b[0] == 1 {
+		if len(b) < 32 {
+			return
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[20:]))
+		duration = binary.BigEndian.Uint64(b[24:])
+	} else {
+		timescale = uint64(binary.BigEndian.Uint32(b[12:]))
+		duration = uint64(binary.BigEndian.Uint32(b[16:]))
+	}
+	if timescale > 0 {
+		p.m.Duration = time.Duration(duration * uint64(time.Second) / timescale)
+	}
+}
+
+// item 解析 ilst 中的一项，值在子 atom data 中：4 字节类型、4 字节 locale，然后是数据
+func (p *mp4Parser) item(name string, b []byte) {
+	if len(b) < 16 || string(b[4:8]) != "data" {
+		return
+	}
+	size := int(binary.BigEndian.Uint32(b))
+	if size < 16 || size > len(b) {
+		return
+	}
+	dataType := binary.BigEndian.Uint32(b[8:]) & 0x00ffffff
+	value := b[16:size]
+	m := p.m
+	switch name {
+	case "\xa9nam":
+		setText(&m.Title, string(value))
+	case "\xa9ART":
+		setText(&m.Artist, string(value))
+	case "aART":
+		setText(&m.AlbumArtist, string(value))
+	case "\xa9alb":
+		setText(&m.Album, string(value))
+	case "\xa9gen":
+		setText(&m.Genre, string(value))
+	case "\xa9day":
+		setNumber(&m.Year, string(value))
+	case "trkn", "disk":
+		// 2 字节保留，2 字节序号，2 字节总数
+		if len(value) >= 4 {
+			n := int(binary.BigEndian.Uint16(value[2:]))
+			if name == "trkn" && m.TrackNo == 0 {
+				m.TrackNo = n
+			} else if name == "disk" && m.DiscNo == 0 {
+				m.DiscNo = n
+			}
+		}
+	case "covr":
+		mime := "image/jpeg"
+		if dataType == 14 || bytes.HasPrefix(value, []byte("\x89PNG")) {
+			mime = "image/png"
+		}
+		m.setPicture(&Picture{MIMEType: mime, Data: value}, true)
+	}
+}
@@ -0,0 +1,124 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// oggTailSize 查找最后一页时读取的文件末尾长度
+const oggTailSize = 64 << 10
+
+// readOgg 解析 Ogg Vorbis/Opus：前两个包为标识头和注释头，时长取最后一页的 granule position
+func readOgg(r io.ReadSeeker, size int64, m *Metadata) error {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	packets, serial, err := readOggPackets(r, 2)
+	if err != nil {
+		return err
+	}
+	id, comment := packets[0], packets[1]
+
+	var sampleRate, preSkip int
+	switch {
+	case bytes.HasPrefix(id, []byte("\x01vorbis")) && len(id) >= 24:
+		m.Format = FormatOgg
+		sampleRate = int(binary.LittleEndian.Uint32(id[12:]))
+		if nominal := int32(binary.LittleEndian.Uint32(id[20:])); nominal > 0 {
+			m.Bitrate = int(nominal / 1000)
+		}
+		if bytes.HasPrefix(comment, []byte("\x03vorbis")) {
+			parseVorbisComment(comment[7:], m)
+		}
+	case bytes.HasPrefix(id, []byte("OpusHead")) && len(id) >= 12:
+		m.Format = FormatOpus
+		sampleRate = 48000 // Opus 的 granule position 固定以 48kHz 计
+		preSkip = int(binary.LittleEndian.Uint16(id[10:]))
+		if bytes.HasPrefix(comment, []byte("OpusTags")) {
+			parseVorbisComment(comment[8:], m)
+		}
+	default:
+		return fmt.Errorf("%w: ogg stream is not vorbis or opus", ErrUnsupported)
+	}
+	if sampleRate == 0 {
+		return fmt.Errorf("%w: zero sample rate", ErrMalformed)
+	}
+	m.SampleRate = sampleRate
+
+	tailStart := max(size-oggTailSize, 0)
+	tail, err := readAt(r, tailStart, int(size-tailStart))
+	if err != nil {
+		return err
+	}
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+27 > len(tail) || binary.LittleEndian.Uint32(tail[i+14:]) != serial {
+			continue
+		}
+		granule := int64(binary.LittleEndian.Uint64(tail[i+6:]))
+		if samples := granule - int64(preSkip); samples > 0 {
+			m.Duration = time.Duration(samples * int64(time.Second) / int64(sampleRate))
+		}
+		break
+	}
+	if m.Format == FormatOpus {
+		m.Bitrate = 0 // 由文件大小和时长计算
+	}
+	return nil
+}
+
+// readOggPackets 从当前位置读取第一个逻辑流的前 n 个包
+func readOggPackets(r io.Reader, n int) ([][]byte, uint32, error) {
+	var packets [][]byte
+	var current []byte
+	var serial uint32
+	total := 0
+	for first := true; len(packets) < n; first = false {
+		header := make([]byte, 27)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if string(header[:4]) != "OggS" {
+			return nil, 0, fmt.Errorf("%w: bad ogg page", ErrMalformed)
+		}
+		pageSerial := binary.LittleEndian.Uint32(header[14:])
+		if first {
+			serial = pageSerial
+		}
+		segments := make([]byte, header[26])
+		if _, err := io.ReadFull(r, segments); err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		bodyLen := 0
+		for _, s := range segments {
+			bodyLen += int(s)
+		}
+		total += bodyLen
+		if total > maxBlockSize {
+			return nil, 0, fmt.Errorf("%w: ogg header too large", ErrMalformed)
+		}
+		body := make([]byte, bodyLen)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if pageSerial != serial {
+			continue // 其他逻辑流
+		}
+		pos := 0
+		for _, s := range segments {
+			current = append(current, body[pos:pos+int(s)]...)
+			pos += int(s)
+			// 长度小于 255 的段表示包结束
+			if s < 255 {
+				packets = append(packets, current)
+				current = nil
+				if len(packets) == n {
+					break
+				}
+			}
+		}
+	}
+	return packets, serial, nil
+}
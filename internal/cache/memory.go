@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Memory 进程内带过期时间的缓存，适合单实例部署或作为 Redis 前的一级缓存
+type Memory[V any] struct {
+	mu      sync.RWMutex
+	items   map[string]entry[V]
+	ttl     time.Duration
+	maxSize int
+}
+
+type entry[V any] struct {
+	value    V
+	expireAt time.Time
+}
+
+// NewMemory 创建缓存，ttl 为默认过期时间，maxSize<=0 表示不限制条目数
+func NewMemory[V any](ttl time.Duration, maxSize int) *Memory[V] {
+	return &Memory[V]{
+		items:   make(map[string]entry[V]),
+		ttl:     ttl,
+		maxSize: maxSize,
+	}
+}
+
+// Get 读取缓存，过期视为不存在
+func (m *Memory[V]) Get(key string) (V, bool) {
+	m.mu.RLock()
+	e, ok := m.items[key]
+	m.mu.RUnlock()
+	if !ok || time.Now().After(e.expireAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set 使用默认过期时间写入
+func (m *Memory[V]) Set(key string, value V) {
+	m.SetWithTTL(key, value, m.ttl)
+}
+
+// SetWithTTL 使用指定过期时间写入
+func (m *Memory[V]) SetWithTTL(key string, value V, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.maxSize > 0 && len(m.items) >= m.maxSize {
+		if _, exists := m.items[key]; !exists {
+			m.evictLocked()
+		}
+	}
+	m.items[key] = entry[V]{value: value, expireAt: time.Now().Add(ttl)}
+}
+
+// Delete 删除缓存
+func (m *Memory[V]) Delete(key string) {
+	m.mu.Lock()
+	delete(m.items, key)
+	m.mu.Unlock()
+}
+
+// Clear 清空缓存
+func (m *Memory[V]) Clear() {
+	m.mu.Lock()
+	m.items = make(map[string]entry[V])
+	m.mu.Unlock()
+}
+
+// evictLocked 先清理过期条目，仍然超限时淘汰最早过期的一条
+func (m *Memory[V]) evictLocked() {
+	now := time.Now()
+	for k, e := range m.items {
+		if now.After(e.expireAt) {
+			delete(m.items, k)
+		}
+	}
+	if len(m.items) < m.maxSize {
+		return
+	}
+	var oldestKey string
+	var oldest time.Time
+	for k, e := range m.items {
+		if oldestKey == "" || e.expireAt.Before(oldest) {
+			oldestKey, oldest = k, e.expireAt
+		}
+	}
+	delete(m.items, oldestKey)
+}
@@ -3,15 +3,22 @@ package config
 import "fmt"
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Recommend RecommendConfig
+	Search    SearchConfig
+	Storage   StorageConfig
+	Upload    UploadConfig
+	Cloud     CloudConfig
+	Chart     ChartConfig
 	// 其他模块配置...
 }
 
 type ServerConfig struct {
-	Port int
-	Mode string
+	Port       int
+	Mode       string
+	AdminToken string `mapstructure:"admin_token"` // 管理后台接口令牌，为空时禁用管理接口
 }
 
 type DatabaseConfig struct {
@@ -30,6 +37,61 @@ type RedisConfig struct {
 	Password string
 }
 
+type RecommendConfig struct {
+	RankModelPath         string   `mapstructure:"rank_model_path"`         // 排序模型文件，为空时按召回得分排序
+	Filters               []string `mapstructure:"filters"`                 // 过滤链，按顺序执行，为空时使用默认过滤链
+	FatigueWindowHours    int      `mapstructure:"fatigue_window_hours"`    // 曝光疲劳统计窗口（小时）
+	FatigueMaxImpressions int      `mapstructure:"fatigue_max_impressions"` // 窗口内同一首歌最多曝光次数
+	Timezone              string   `mapstructure:"timezone"`                // 每日推荐使用的时区，如 Asia/Shanghai
+	DailyRunAt            string   `mapstructure:"daily_run_at"`            // 每日推荐生成时间，格式 15:04
+	DailySize             int      `mapstructure:"daily_size"`              // 每日推荐歌曲数
+	DailyActiveDays       int      `mapstructure:"daily_active_days"`       // 批量生成覆盖最近多少天活跃的用户
+	DailyWorkers          int      `mapstructure:"daily_workers"`           // 批量生成并发数
+}
+
+type SearchConfig struct {
+	SyncIntervalSeconds    int `mapstructure:"sync_interval_seconds"`    // 增量同步间隔（秒）
+	RebuildIntervalMinutes int `mapstructure:"rebuild_interval_minutes"` // 全量重建间隔（分钟）
+	HotWindowHours         int `mapstructure:"hot_window_hours"`         // 热搜统计窗口（小时）
+	HotHalfLifeHours       int `mapstructure:"hot_half_life_hours"`      // 热搜热度半衰期（小时）
+	HotSize                int `mapstructure:"hot_size"`                 // 热搜榜长度
+}
+
+type StorageConfig struct {
+	Driver           string   `mapstructure:"driver"`             // 存储驱动 local/s3，默认 local
+	LocalRoot        string   `mapstructure:"local_root"`         // 本地存储根目录
+	S3               S3Config `mapstructure:"s3"`                 // S3 兼容存储，driver 为 s3 时生效
+	SignSecret       string   `mapstructure:"sign_secret"`        // 媒体链接签名密钥，为空时启动时随机生成
+	URLExpireMinutes int      `mapstructure:"url_expire_minutes"` // 媒体链接有效期（分钟）
+}
+
+// S3Config S3 兼容对象存储（AWS S3、MinIO、OSS、COS 等）
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`   // 服务地址，如 http://127.0.0.1:9000
+	Region    string `mapstructure:"region"`     // 区域，MinIO 可填 us-east-1
+	Bucket    string `mapstructure:"bucket"`     // 存储桶
+	AccessKey string `mapstructure:"access_key"` // 访问密钥 ID
+	SecretKey string `mapstructure:"secret_key"` // 访问密钥
+	PathStyle bool   `mapstructure:"path_style"` // 使用路径风格（endpoint/bucket/key），MinIO 等自建服务需开启
+}
+
+type UploadConfig struct {
+	MaxSizeMB       int `mapstructure:"max_size_mb"`       // 单个音频文件上限（MB）
+	ChunkSizeMB     int `mapstructure:"chunk_size_mb"`     // 分片上传的分片大小（MB）
+	SessionTTLHours int `mapstructure:"session_ttl_hours"` // 未完成的分片上传保留时长（小时）
+}
+
+type CloudConfig struct {
+	QuotaMB int `mapstructure:"quota_mb"` // 每个用户的云盘容量（MB）
+}
+
+type ChartConfig struct {
+	RefreshMinutes int      `mapstructure:"refresh_minutes"` // 榜单重新计算间隔（分钟）
+	Size           int      `mapstructure:"size"`            // 每个榜单的歌曲数
+	OriginalTag    string   `mapstructure:"original_tag"`    // 原创榜统计带有该标签的歌曲
+	Tags           []string `mapstructure:"tags"`            // 按标签生成的曲风榜
+}
+
 // 可以添加辅助方法，比如生成 DSN
 func (d *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true",
@@ -0,0 +1,32 @@
+package domain
+
+// Artist 歌手
+type Artist struct {
+	ID          int64  `gorm:"primaryKey"`
+	Name        string `gorm:"index"`
+	Alias       string // 别名/外文名
+	AvatarURL   string
+	Description string `gorm:"type:text"`
+	CreatedAt   int64
+	UpdatedAt   int64
+}
+
+// SongArtist 歌曲与歌手的多对多关系，Position 为署名顺序
+type SongArtist struct {
+	SongID   int64 `gorm:"primaryKey"`
+	ArtistID int64 `gorm:"primaryKey;index"`
+	Position int
+}
+
+// Album 专辑
+type Album struct {
+	ID          int64  `gorm:"primaryKey"`
+	Name        string `gorm:"index"`
+	ArtistID    int64  `gorm:"index"` // 专辑主歌手
+	CoverURL    string
+	Description string `gorm:"type:text"`
+	Language    string `gorm:"size:8;index"` // 语种，为空表示未知
+	ReleaseTime int64  `gorm:"index"`        // 发行时间戳
+	CreatedAt   int64
+	UpdatedAt   int64
+}
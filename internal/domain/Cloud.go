@@ -0,0 +1,26 @@
+package domain
+
+// CloudSong 用户云盘中的歌曲。匹配到曲库时 SongID 指向曲库歌曲，否则指向上传时创建的私有歌曲，
+// 因此云盘歌曲可以像曲库歌曲一样加入歌单和播放历史
+type CloudSong struct {
+	ID          int64  `gorm:"primaryKey"`
+	UserID      int64  `gorm:"uniqueIndex:idx_cloud_user_song,priority:1;index:idx_cloud_user_fp,priority:1"`
+	SongID      int64  `gorm:"uniqueIndex:idx_cloud_user_song,priority:2"`
+	Matched     bool   // 是否匹配到曲库歌曲
+	Fingerprint string `gorm:"size:64;index:idx_cloud_user_fp,priority:2"` // 由歌名和歌手归一化生成
+	FileName    string
+	Key         string `gorm:"size:512"` // 存储中的对象 key
+	Format      string `gorm:"size:16"`
+	MimeType    string `gorm:"size:64"`
+	Quality     string `gorm:"size:16"`
+	Bitrate     int    // kbps
+	Size        int64  // 字节
+	CreatedAt   int64
+}
+
+// CloudUsage 用户云盘用量，随 CloudSong 增删维护
+type CloudUsage struct {
+	UserID    int64 `gorm:"primaryKey"`
+	UsedBytes int64
+	SongCount int
+}
@@ -0,0 +1,47 @@
+package domain
+
+// 可评论的目标类型
+const (
+	CommentTargetSong     = "song"
+	CommentTargetPlaylist = "playlist"
+	CommentTargetAlbum    = "album"
+)
+
+// 评论状态
+const (
+	CommentNormal  = 0
+	CommentDeleted = 1 // 作者删除
+	CommentRemoved = 2 // 管理员屏蔽，可恢复
+)
+
+// Comment 评论。顶层评论的 ParentID 为 0；楼中楼回复统一挂在顶层评论下，
+// ReplyToID 指向被回复的那条评论
+type Comment struct {
+	ID            int64  `gorm:"primaryKey"`
+	TargetType    string `gorm:"size:16;index:idx_comment_target,priority:1"`
+	TargetID      int64  `gorm:"index:idx_comment_target,priority:2"`
+	UserID        int64  `gorm:"index"`
+	ParentID      int64  `gorm:"index:idx_comment_parent,priority:1"`
+	ReplyToID     int64
+	ReplyToUserID int64
+	Content       string `gorm:"type:text"`
+	LikeCount     int64
+	ReplyCount    int // 正常状态的回复数
+	Status        int8
+	CreatedAt     int64 `gorm:"index:idx_comment_parent,priority:2"`
+	UpdatedAt     int64
+}
+
+// CommentLike 评论点赞
+type CommentLike struct {
+	CommentID int64 `gorm:"primaryKey"`
+	UserID    int64 `gorm:"primaryKey;index"`
+	CreatedAt int64
+}
+
+// CommentCount 评论目标的可见评论总数（含回复）
+type CommentCount struct {
+	TargetType string `gorm:"primaryKey;size:16"`
+	TargetID   int64  `gorm:"primaryKey"`
+	Total      int64
+}
@@ -0,0 +1,23 @@
+package domain
+
+// SongLike 用户喜欢的歌曲（“我喜欢的音乐”）
+type SongLike struct {
+	UserID    int64 `gorm:"primaryKey;index:idx_user_liked,priority:1"`
+	SongID    int64 `gorm:"primaryKey;index"`
+	CreatedAt int64 `gorm:"index:idx_user_liked,priority:2"`
+}
+
+// 可收藏的目标类型
+const (
+	SubscribePlaylist = "playlist"
+	SubscribeAlbum    = "album"
+	SubscribeArtist   = "artist"
+)
+
+// Subscription 用户收藏的歌单、专辑或关注的歌手
+type Subscription struct {
+	UserID     int64  `gorm:"primaryKey;index:idx_user_subscribed,priority:1"`
+	TargetType string `gorm:"primaryKey;size:16;index:idx_user_subscribed,priority:2;index:idx_subscription_target,priority:1"`
+	TargetID   int64  `gorm:"primaryKey;index:idx_subscription_target,priority:2"`
+	CreatedAt  int64  `gorm:"index:idx_user_subscribed,priority:3"`
+}
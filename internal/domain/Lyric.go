@@ -0,0 +1,13 @@
+package domain
+
+// Lyric 歌曲歌词，一首歌一条。LRC 为带时间轴的原文，TranslatedLRC 为对应的翻译，
+// 没有时间轴的歌词只填 Plain
+type Lyric struct {
+	SongID        int64  `gorm:"primaryKey;autoIncrement:false"`
+	Plain         string `gorm:"type:text"`
+	LRC           string `gorm:"column:lrc;type:mediumtext"`
+	TranslatedLRC string `gorm:"column:translated_lrc;type:mediumtext"`
+	Contributor   string `gorm:"size:64"` // 歌词贡献者
+	CreatedAt     int64
+	UpdatedAt     int64
+}
@@ -0,0 +1,27 @@
+package domain
+
+// Playlist 用户创建的歌单
+type Playlist struct {
+	ID             int64    `gorm:"primaryKey"`
+	UserID         int64    `gorm:"index"` // 创建者
+	Name           string   `gorm:"size:128;index"`
+	Description    string   `gorm:"type:text"`
+	CoverURL       string   // 封面，为空时客户端使用第一首歌的封面
+	CoverColor     string   `gorm:"size:7"` // 封面主色 #rrggbb，设置封面时提取
+	Tags           []string `gorm:"serializer:json"`
+	Public         bool     // 非公开歌单只有创建者可见
+	SongCount      int      // 歌曲数，随增删歌曲维护
+	PlayCount      int64
+	SubscribeCount int64 // 收藏数，随 Subscription 维护
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+// PlaylistSong 歌单中的歌曲。Position 为稀疏排序值，拖动排序只需修改被移动歌曲的 Position，
+// 相邻位置间隔用尽时整单重新编号
+type PlaylistSong struct {
+	PlaylistID int64 `gorm:"primaryKey;index:idx_playlist_position,priority:1"`
+	SongID     int64 `gorm:"primaryKey"`
+	Position   int64 `gorm:"index:idx_playlist_position,priority:2"`
+	AddedAt    int64
+}
@@ -0,0 +1,50 @@
+package domain
+
+// 歌曲和专辑的语种，对应新歌速递的地区分类
+const (
+	LanguageChinese  = "zh" // 华语
+	LanguageWestern  = "en" // 欧美
+	LanguageJapanese = "ja" // 日本
+	LanguageKorean   = "ko" // 韩国
+)
+
+// Languages 全部语种
+var Languages = []string{LanguageChinese, LanguageWestern, LanguageJapanese, LanguageKorean}
+
+// Song 曲库中的歌曲
+type Song struct {
+	ID          int64     `gorm:"primaryKey"`
+	Name        string    `gorm:"index"`
+	Artist      string    // 歌手名展示文本，多位歌手以 " / " 分隔，随 SongArtist 维护
+	Album       string    // 专辑名展示文本
+	AlbumID     int64     `gorm:"index"`
+	DiscNo      int       // 碟片序号，从 1 开始
+	TrackNo     int       // 碟内曲目序号，从 1 开始
+	Tags        []string  `gorm:"serializer:json"` // 风格标签
+	Language    string    `gorm:"size:8;index"`    // 语种，为空表示未知
+	Duration    int       // 时长（秒）
+	PublishTime int64     `gorm:"index"` // 发布时间戳
+	CoverURL    string    // 封面
+	CoverColor  string    `gorm:"size:7"` // 封面主色 #rrggbb，上传封面时提取
+	Explicit    bool      // 是否含不适宜内容
+	Unavailable bool      // 下架或无版权
+	Regions     []string  `gorm:"serializer:json"` // 限定可播放的地区，为空表示不限
+	Features    []float64 `gorm:"serializer:json"` // 音频特征向量（推荐使用）
+	PlayCount   int64     // 累计播放次数
+	LikeCount   int64     // 被喜欢次数，随 SongLike 维护
+	OwnerID     int64     `gorm:"index"` // 云盘上传且未匹配到曲库的私有歌曲的上传者，曲库歌曲为 0
+	CreatedAt   int64
+	UpdatedAt   int64
+
+	Artists []*Artist `gorm:"-"` // 按署名顺序排列的歌手，由仓储按需填充
+}
+
+// SongFilter 歌曲列表的筛选条件，零值表示不限
+type SongFilter struct {
+	Tag             string
+	ArtistID        int64
+	PublishedAfter  int64
+	PublishedBefore int64
+	Page            int
+	PageSize        int
+}
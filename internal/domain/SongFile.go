@@ -0,0 +1,25 @@
+package domain
+
+// 音质，由低到高
+const (
+	QualityStandard = "standard" // 标准，约 128kbps
+	QualityHigher   = "higher"   // 较高，约 320kbps
+	QualityLossless = "lossless" // 无损
+)
+
+// Qualities 按由低到高排列的全部音质
+var Qualities = []string{QualityStandard, QualityHigher, QualityLossless}
+
+// SongFile 歌曲某一音质的音频文件
+type SongFile struct {
+	ID        int64  `gorm:"primaryKey"`
+	SongID    int64  `gorm:"uniqueIndex:idx_song_quality,priority:1"`
+	Quality   string `gorm:"size:16;uniqueIndex:idx_song_quality,priority:2"`
+	Key       string `gorm:"size:512"` // 存储中的对象 key
+	Format    string `gorm:"size:16"`  // mp3/flac/m4a...
+	MimeType  string `gorm:"size:64"`
+	Bitrate   int    // kbps
+	Size      int64  // 字节
+	CreatedAt int64
+	UpdatedAt int64
+}
@@ -0,0 +1,21 @@
+package domain
+
+// UploadSession 分片上传会话，分片暂存在存储的 uploads/<ID>/ 下，完成或过期后删除
+type UploadSession struct {
+	ID         string `gorm:"primaryKey;size:32"`
+	UserID     int64  `gorm:"index"` // 上传者，管理后台上传为 0
+	FileName   string
+	Size       int64 // 文件总字节数
+	ChunkSize  int64 // 除最后一片外每片的字节数
+	ChunkCount int
+	ExpiresAt  int64 `gorm:"index"`
+	CreatedAt  int64
+}
+
+// UploadChunk 已接收的分片，ChunkIndex 从 0 开始
+type UploadChunk struct {
+	SessionID  string `gorm:"primaryKey;size:32"`
+	ChunkIndex int    `gorm:"primaryKey"`
+	Size       int64
+	CreatedAt  int64
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	service "wyy/internal/service/catalog"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlbumHandler struct {
+	AlbumService *service.AlbumService
+	Images       *media.ImageLinker
+}
+
+func NewAlbumHandler(albumService *service.AlbumService, images *media.ImageLinker) *AlbumHandler {
+	return &AlbumHandler{AlbumService: albumService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *AlbumHandler) RegisterRoutes(r gin.IRouter) {
+	albums := r.Group("/albums")
+	{
+		albums.GET("/:id", h.getAlbum)
+	}
+}
+
+// getAlbum 专辑详情
+// @Summary      专辑详情
+// @Description  返回专辑信息、主歌手和按曲目顺序排列的歌曲
+// @Tags         曲库
+// @Produce      json
+// @Param        id   path      int  true  "专辑ID"
+// @Success      200  {object}  utils.Response{data=AlbumDetailResponse}
+// @Router       /api/albums/{id} [get]
+func (h *AlbumHandler) getAlbum(c *gin.Context) {
+	id, ok := pathID(c, "album")
+	if !ok {
+		return
+	}
+	detail, err := h.AlbumService.GetAlbumDetail(c.Request.Context(), id)
+	if errors.Is(err, service.ErrAlbumNotFound) {
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := AlbumDetailResponse{
+		Album:  toAlbumResponse(h.Images, detail.Album),
+		Tracks: toSongResponses(h.Images, detail.Tracks),
+	}
+	if detail.Artist != nil {
+		artist := toArtistResponse(h.Images, detail.Artist)
+		resp.Artist = &artist
+	}
+	utils.Success(c, resp)
+}
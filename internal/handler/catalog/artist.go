@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	service "wyy/internal/service/catalog"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArtistHandler struct {
+	ArtistService *service.ArtistService
+	Images        *media.ImageLinker
+}
+
+func NewArtistHandler(artistService *service.ArtistService, images *media.ImageLinker) *ArtistHandler {
+	return &ArtistHandler{ArtistService: artistService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *ArtistHandler) RegisterRoutes(r gin.IRouter) {
+	artists := r.Group("/artists")
+	{
+		artists.GET("/:id", h.getArtist)
+	}
+}
+
+// getArtist 歌手详情
+// @Summary      歌手详情
+// @Description  返回歌手信息、热门歌曲和专辑列表
+// @Tags         曲库
+// @Produce      json
+// @Param        id   path      int  true  "歌手ID"
+// @Success      200  {object}  utils.Response{data=ArtistDetailResponse}
+// @Router       /api/artists/{id} [get]
+func (h *ArtistHandler) getArtist(c *gin.Context) {
+	id, ok := pathID(c, "artist")
+	if !ok {
+		return
+	}
+	detail, err := h.ArtistService.GetArtistDetail(c.Request.Context(), id)
+	if errors.Is(err, service.ErrArtistNotFound) {
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	albums := make([]AlbumResponse, 0, len(detail.Albums))
+	for _, album := range detail.Albums {
+		albums = append(albums, toAlbumResponse(h.Images, album))
+	}
+	utils.Success(c, ArtistDetailResponse{
+		Artist:   toArtistResponse(h.Images, detail.Artist),
+		TopSongs: toSongResponses(h.Images, detail.TopSongs),
+		Albums:   albums,
+	})
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
+	cloud "wyy/internal/service/cloud"
+	media "wyy/internal/service/media"
+	upload "wyy/internal/service/upload"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CloudHandler 我的音乐云盘：上传自己的音乐，匹配到曲库时按曲库歌曲展示，否则作为仅自己可见的歌曲
+type CloudHandler struct {
+	CloudService  *cloud.CloudService
+	UploadService *upload.UploadService
+	Images        *media.ImageLinker
+}
+
+func NewCloudHandler(cloudService *cloud.CloudService, uploadService *upload.UploadService, images *media.ImageLinker) *CloudHandler {
+	return &CloudHandler{CloudService: cloudService, UploadService: uploadService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *CloudHandler) RegisterRoutes(r gin.IRouter) {
+	me := r.Group("/users/me/cloud", middleware.Auth())
+	{
+		me.GET("", h.usage)
+		me.GET("/songs", h.list)
+		me.POST("/songs", h.upload)
+		me.DELETE("/songs/:id", h.delete)
+
+		me.POST("/uploads", h.createSession)
+		me.GET("/uploads/:id", h.getSession)
+		me.PUT("/uploads/:id/chunks/:index", h.putChunk)
+		me.POST("/uploads/:id/complete", h.complete)
+		me.DELETE("/uploads/:id", h.abort)
+	}
+}
+
+// usage 云盘用量
+// @Summary      云盘用量
+// @Tags         音乐云盘
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Success      200        {object}  utils.Response{data=CloudUsageResponse}
+// @Router       /api/users/me/cloud [get]
+func (h *CloudHandler) usage(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	usage, err := h.CloudService.Usage(c.Request.Context(), userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, CloudUsageResponse{UsedBytes: usage.UsedBytes, QuotaBytes: usage.QuotaBytes, SongCount: usage.SongCount})
+}
+
+// list 云盘歌曲
+// @Summary      云盘歌曲
+// @Description  分页返回云盘歌曲，最近上传的在前
+// @Tags         音乐云盘
+// @Produce      json
+// @Param        X-User-ID  header    int  true   "用户ID"
+// @Param        page       query     int  false  "页码，从 1 开始"
+// @Param        page_size  query     int  false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=CloudSongPageResponse}
+// @Router       /api/users/me/cloud/songs [get]
+func (h *CloudHandler) list(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	page, pageSize := utils.ParsePage(c)
+	songs, total, err := h.CloudService.List(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	list := make([]CloudSongResponse, 0, len(songs))
+	for _, song := range songs {
+		list = append(list, toCloudSongResponse(h.Images, song))
+	}
+	utils.Success(c, CloudSongPageResponse{List: list, Total: total, Page: page, PageSize: pageSize})
+}
+
+// upload 上传到云盘
+// @Summary      上传到云盘
+// @Description  按歌名、歌手和时长匹配曲库，匹配成功时关联曲库歌曲，否则按标签创建仅自己可见的歌曲；再次上传同一首歌时替换原文件
+// @Tags         音乐云盘
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        X-User-ID  header    int   true  "用户ID"
+// @Param        file       formData  file  true  "音频文件"
+// @Success      200        {object}  utils.Response{data=CloudSongResponse}
+// @Router       /api/users/me/cloud/songs [post]
+func (h *CloudHandler) upload(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	if fileHeader.Size > h.UploadService.MaxSize() {
+		respondCloudError(c, upload.ErrFileTooLarge)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	userID, _ := middleware.GetUserID(c)
+	song, err := h.CloudService.Upload(c.Request.Context(), userID, file, fileHeader.Size, fileHeader.Filename)
+	if err != nil {
+		respondCloudError(c, err)
+		return
+	}
+	utils.Success(c, toCloudSongResponse(h.Images, song))
+}
+
+// delete 从云盘删除
+// @Summary      从云盘删除
+// @Description  删除文件并释放容量；未匹配曲库的歌曲同时下架，歌单和播放历史中的记录变为不可播放
+// @Tags         音乐云盘
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Param        id         path      int  true  "云盘歌曲ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/cloud/songs/{id} [delete]
+func (h *CloudHandler) delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid cloud song id")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.CloudService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondCloudError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// createSession 创建云盘分片上传
+// @Summary      创建云盘分片上传
+// @Description  按返回的 chunk_size 切分文件，依次上传各分片后调用 complete；中断后可查询已收到的分片继续上传
+// @Tags         音乐云盘
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int                   true  "用户ID"
+// @Param        body       body      UploadSessionRequest  true  "文件信息"
+// @Success      200        {object}  utils.Response{data=UploadSessionResponse}
+// @Router       /api/users/me/cloud/uploads [post]
+func (h *CloudHandler) createSession(c *gin.Context) {
+	var req UploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	ctx := c.Request.Context()
+	// 提前拒绝放不下的文件，免得上传完全部分片才发现超额
+	usage, err := h.CloudService.Usage(ctx, userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if usage.UsedBytes+req.Size > usage.QuotaBytes {
+		respondCloudError(c, cloud.ErrQuotaExceeded)
+		return
+	}
+	session, err := h.UploadService.Create(ctx, userID, req.FileName, req.Size)
+	if err != nil {
+		respondCloudError(c, err)
+		return
+	}
+	utils.Success(c, toUploadSessionResponse(session, nil))
+}
+
+// getSession 查询云盘分片上传进度
+// @Summary      查询云盘分片上传进度
+// @Tags         音乐云盘
+// @Produce      json
+// @Param        X-User-ID  header    int     true  "用户ID"
+// @Param        id         path      string  true  "上传ID"
+// @Success      200        {object}  utils.Response{data=UploadSessionResponse}
+// @Router       /api/users/me/cloud/uploads/{id} [get]
+func (h *CloudHandler) getSession(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	session, received, err := h.UploadService.Get(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondCloudError(c, err)
+		return
+	}
+	utils.Success(c, toUploadSessionResponse(session, received))
+}
+
+// putChunk 上传云盘分片
+// @Summary      上传云盘分片
+// @Description  请求体为分片的原始字节，除最后一片外大小必须等于 chunk_size；重复上传同一分片会覆盖
+// @Tags         音乐云盘
+// @Accept       application/octet-stream
+// @Produce      json
+// @Param        X-User-ID  header    int     true  "用户ID"
+// @Param        id         path      string  true  "上传ID"
+// @Param        index      path      int     true  "分片序号，从 0 开始"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/cloud/uploads/{id}/chunks/{index} [put]
+func (h *CloudHandler) putChunk(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	putChunk(c, h.UploadService, userID)
+}
+
+// complete 完成云盘分片上传
+// @Summary      完成云盘分片上传
+// @Description  合并分片并按上传到云盘的规则处理，成功后删除分片
+// @Tags         音乐云盘
+// @Produce      json
+// @Param        X-User-ID  header    int     true  "用户ID"
+// @Param        id         path      string  true  "上传ID"
+// @Success      200        {object}  utils.Response{data=CloudSongResponse}
+// @Router       /api/users/me/cloud/uploads/{id}/complete [post]
+func (h *CloudHandler) complete(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	ctx := c.Request.Context()
+	file, err := h.UploadService.Assemble(ctx, userID, c.Param("id"))
+	if err != nil {
+		respondCloudError(c, err)
+		return
+	}
+	defer file.Close()
+
+	song, err := h.CloudService.Upload(ctx, userID, file, file.Session.Size, file.Session.FileName)
+	if err != nil {
+		respondCloudError(c, err)
+		return
+	}
+	if err := h.UploadService.Finish(ctx, file.Session.ID); err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, toCloudSongResponse(h.Images, song))
+}
+
+// abort 取消云盘分片上传
+// @Summary      取消云盘分片上传
+// @Tags         音乐云盘
+// @Produce      json
+// @Param        X-User-ID  header    int     true  "用户ID"
+// @Param        id         path      string  true  "上传ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/cloud/uploads/{id} [delete]
+func (h *CloudHandler) abort(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	if err := h.UploadService.Abort(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondCloudError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+func respondCloudError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, cloud.ErrQuotaExceeded):
+		utils.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, cloud.ErrCloudSongNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		respondUploadError(c, err)
+	}
+}
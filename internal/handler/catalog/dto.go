@@ -0,0 +1,492 @@
+package handler
+
+import (
+	"wyy/internal/domain"
+	catalog "wyy/internal/service/catalog"
+	cloud "wyy/internal/service/cloud"
+	media "wyy/internal/service/media"
+	upload "wyy/internal/service/upload"
+)
+
+// ArtistBrief 歌手简要信息
+type ArtistBrief struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// SongResponse 歌曲详情
+type SongResponse struct {
+	ID          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Artist      string        `json:"artist"` // 歌手名展示文本
+	Artists     []ArtistBrief `json:"artists"`
+	Album       string        `json:"album"`
+	AlbumID     int64         `json:"album_id"`
+	DiscNo      int           `json:"disc_no"`
+	TrackNo     int           `json:"track_no"`
+	Tags        []string      `json:"tags"`
+	Language    string        `json:"language"`
+	Duration    int           `json:"duration"`
+	PublishTime int64         `json:"publish_time"`
+	CoverURL    string        `json:"cover_url"`
+	CoverColor  string        `json:"cover_color"` // 封面主色 #rrggbb，可能为空
+	Explicit    bool          `json:"explicit"`
+	Available   bool          `json:"available"`
+	LikeCount   int64         `json:"like_count"`
+}
+
+// SongPageResponse 歌曲分页列表
+type SongPageResponse struct {
+	List     []SongResponse `json:"list"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
+
+// ArtistResponse 歌手信息
+type ArtistResponse struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Alias       string `json:"alias"`
+	AvatarURL   string `json:"avatar_url"`
+	Description string `json:"description"`
+}
+
+// AlbumResponse 专辑信息
+type AlbumResponse struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	ArtistID    int64  `json:"artist_id"`
+	CoverURL    string `json:"cover_url"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	ReleaseTime int64  `json:"release_time"`
+}
+
+// ArtistDetailResponse 歌手页
+type ArtistDetailResponse struct {
+	Artist   ArtistResponse  `json:"artist"`
+	TopSongs []SongResponse  `json:"top_songs"`
+	Albums   []AlbumResponse `json:"albums"`
+}
+
+// AlbumDetailResponse 专辑页
+type AlbumDetailResponse struct {
+	Album  AlbumResponse   `json:"album"`
+	Artist *ArtistResponse `json:"artist"`
+	Tracks []SongResponse  `json:"tracks"`
+}
+
+func toArtistResponse(images *media.ImageLinker, artist *domain.Artist) ArtistResponse {
+	return ArtistResponse{
+		ID:          artist.ID,
+		Name:        artist.Name,
+		Alias:       artist.Alias,
+		AvatarURL:   images.Link(artist.AvatarURL),
+		Description: artist.Description,
+	}
+}
+
+func toAlbumResponse(images *media.ImageLinker, album *domain.Album) AlbumResponse {
+	return AlbumResponse{
+		ID:          album.ID,
+		Name:        album.Name,
+		ArtistID:    album.ArtistID,
+		CoverURL:    images.Link(album.CoverURL),
+		Description: album.Description,
+		Language:    album.Language,
+		ReleaseTime: album.ReleaseTime,
+	}
+}
+
+func toSongResponse(images *media.ImageLinker, song *domain.Song) SongResponse {
+	artists := make([]ArtistBrief, 0, len(song.Artists))
+	for _, artist := range song.Artists {
+		artists = append(artists, ArtistBrief{ID: artist.ID, Name: artist.Name})
+	}
+	return SongResponse{
+		ID:          song.ID,
+		Name:        song.Name,
+		Artist:      song.Artist,
+		Artists:     artists,
+		Album:       song.Album,
+		AlbumID:     song.AlbumID,
+		DiscNo:      song.DiscNo,
+		TrackNo:     song.TrackNo,
+		Tags:        song.Tags,
+		Language:    song.Language,
+		Duration:    song.Duration,
+		PublishTime: song.PublishTime,
+		CoverURL:    images.Link(song.CoverURL),
+		CoverColor:  song.CoverColor,
+		Explicit:    song.Explicit,
+		Available:   !song.Unavailable,
+		LikeCount:   song.LikeCount,
+	}
+}
+
+func toSongResponses(images *media.ImageLinker, songs []*domain.Song) []SongResponse {
+	resp := make([]SongResponse, 0, len(songs))
+	for _, song := range songs {
+		resp = append(resp, toSongResponse(images, song))
+	}
+	return resp
+}
+
+// SearchResponse 搜索结果，只返回与 type 对应的列表
+type SearchResponse struct {
+	Type      string             `json:"type"`
+	Total     int                `json:"total"`
+	Page      int                `json:"page"`
+	PageSize  int                `json:"page_size"`
+	Songs     []SongResponse     `json:"songs,omitempty"`
+	Artists   []ArtistResponse   `json:"artists,omitempty"`
+	Albums    []AlbumResponse    `json:"albums,omitempty"`
+	Playlists []PlaylistResponse `json:"playlists,omitempty"`
+}
+
+// SuggestionResponse 联想词条
+type SuggestionResponse struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+}
+
+// HotKeywordResponse 热搜词
+type HotKeywordResponse struct {
+	Query string  `json:"query"`
+	Score float64 `json:"score"`
+}
+
+// BlockedTermRequest 添加热搜屏蔽词请求体
+type BlockedTermRequest struct {
+	Term string `json:"term" binding:"required"`
+}
+
+// BlockedTermResponse 热搜屏蔽词
+type BlockedTermResponse struct {
+	ID        int64  `json:"id"`
+	Term      string `json:"term"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// PlaylistRequest 创建/更新歌单请求体
+type PlaylistRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	CoverURL    string   `json:"cover_url"`
+	Tags        []string `json:"tags"`
+	Public      bool     `json:"public"`
+}
+
+// PlaylistSongsRequest 歌单增删歌曲请求体
+type PlaylistSongsRequest struct {
+	SongIDs []int64 `json:"song_ids" binding:"required"`
+}
+
+// PlaylistMoveRequest 歌单拖动排序请求体，AfterSongID 为 0 时移到最前
+type PlaylistMoveRequest struct {
+	SongID      int64 `json:"song_id" binding:"required"`
+	AfterSongID int64 `json:"after_song_id"`
+}
+
+// PlaylistSongsResponse 歌单增删歌曲结果
+type PlaylistSongsResponse struct {
+	Affected int `json:"affected"`
+}
+
+// PlaylistResponse 歌单信息
+type PlaylistResponse struct {
+	ID             int64    `json:"id"`
+	UserID         int64    `json:"user_id"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	CoverURL       string   `json:"cover_url"`
+	CoverColor     string   `json:"cover_color"` // 封面主色 #rrggbb，可能为空
+	Tags           []string `json:"tags"`
+	Public         bool     `json:"public"`
+	SongCount      int      `json:"song_count"`
+	PlayCount      int64    `json:"play_count"`
+	SubscribeCount int64    `json:"subscribe_count"`
+	CreatedAt      int64    `json:"created_at"`
+	UpdatedAt      int64    `json:"updated_at"`
+}
+
+// PlaylistDetailResponse 歌单页
+type PlaylistDetailResponse struct {
+	Playlist PlaylistResponse `json:"playlist"`
+	Songs    []SongResponse   `json:"songs"`
+}
+
+// PlaylistPageResponse 歌单分页列表
+type PlaylistPageResponse struct {
+	List     []PlaylistResponse `json:"list"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+func (r *PlaylistRequest) toPlaylist(id int64) *domain.Playlist {
+	return &domain.Playlist{
+		ID:          id,
+		Name:        r.Name,
+		Description: r.Description,
+		CoverURL:    media.StoredImageURL(r.CoverURL),
+		Tags:        r.Tags,
+		Public:      r.Public,
+	}
+}
+
+func toPlaylistResponse(images *media.ImageLinker, playlist *domain.Playlist) PlaylistResponse {
+	return PlaylistResponse{
+		ID:             playlist.ID,
+		UserID:         playlist.UserID,
+		Name:           playlist.Name,
+		Description:    playlist.Description,
+		CoverURL:       images.Link(playlist.CoverURL),
+		CoverColor:     playlist.CoverColor,
+		Tags:           playlist.Tags,
+		Public:         playlist.Public,
+		SongCount:      playlist.SongCount,
+		PlayCount:      playlist.PlayCount,
+		SubscribeCount: playlist.SubscribeCount,
+		CreatedAt:      playlist.CreatedAt,
+		UpdatedAt:      playlist.UpdatedAt,
+	}
+}
+
+// LikeRequest 喜欢歌曲请求体
+type LikeRequest struct {
+	SongID int64 `json:"song_id" binding:"required"`
+}
+
+// LikedSongResponse 喜欢的歌曲
+type LikedSongResponse struct {
+	Song    SongResponse `json:"song"`
+	LikedAt int64        `json:"liked_at"`
+}
+
+// LikedSongPageResponse 喜欢的歌曲分页列表
+type LikedSongPageResponse struct {
+	List     []LikedSongResponse `json:"list"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// SubscribeRequest 收藏请求体
+type SubscribeRequest struct {
+	Type     string `json:"type" binding:"required"` // playlist/album/artist
+	TargetID int64  `json:"target_id" binding:"required"`
+}
+
+// SubscriptionPageResponse 收藏分页列表，只返回与 type 对应的列表
+type SubscriptionPageResponse struct {
+	Type      string             `json:"type"`
+	Total     int64              `json:"total"`
+	Page      int                `json:"page"`
+	PageSize  int                `json:"page_size"`
+	Playlists []PlaylistResponse `json:"playlists,omitempty"`
+	Albums    []AlbumResponse    `json:"albums,omitempty"`
+	Artists   []ArtistResponse   `json:"artists,omitempty"`
+}
+
+// SongURLResponse 某一音质的签名播放链接
+type SongURLResponse struct {
+	Quality   string `json:"quality"`
+	URL       string `json:"url"`
+	Format    string `json:"format"`
+	Bitrate   int    `json:"bitrate"`
+	Size      int64  `json:"size"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// SongURLsResponse 歌曲播放链接
+type SongURLsResponse struct {
+	SongID int64             `json:"song_id"`
+	URLs   []SongURLResponse `json:"urls"`
+}
+
+// SongImportRequest 上传音频时手动指定的歌曲信息，为空的字段使用音频标签中的值
+type SongImportRequest struct {
+	SongID   int64    `json:"song_id" form:"song_id"` // 为已有歌曲补充音质
+	Name     string   `json:"name" form:"name"`
+	Artists  []string `json:"artists" form:"artists"`
+	Album    string   `json:"album" form:"album"`
+	Language string   `json:"language" form:"language"` // zh/en/ja/ko，新建歌曲时使用
+	Quality  string   `json:"quality" form:"quality"`   // standard/higher/lossless，为空时按格式和码率判断
+}
+
+// SongFileResponse 音频文件信息
+type SongFileResponse struct {
+	Quality string `json:"quality"`
+	Format  string `json:"format"`
+	Bitrate int    `json:"bitrate"`
+	Size    int64  `json:"size"`
+}
+
+// SongImportResponse 上传结果
+type SongImportResponse struct {
+	Song SongResponse     `json:"song"`
+	File SongFileResponse `json:"file"`
+}
+
+// UploadSessionRequest 创建分片上传请求体
+type UploadSessionRequest struct {
+	FileName string `json:"file_name" binding:"required"`
+	Size     int64  `json:"size" binding:"required"`
+}
+
+// UploadSessionResponse 分片上传会话，received 为已收到的分片序号
+type UploadSessionResponse struct {
+	ID         string `json:"id"`
+	FileName   string `json:"file_name"`
+	Size       int64  `json:"size"`
+	ChunkSize  int64  `json:"chunk_size"`
+	ChunkCount int    `json:"chunk_count"`
+	Received   []int  `json:"received"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+func (r *SongImportRequest) toSongInfo() upload.SongInfo {
+	return upload.SongInfo{
+		SongID:   r.SongID,
+		Name:     r.Name,
+		Artists:  r.Artists,
+		Album:    r.Album,
+		Language: r.Language,
+		Quality:  r.Quality,
+	}
+}
+
+func toSongImportResponse(images *media.ImageLinker, result *upload.ImportResult) SongImportResponse {
+	return SongImportResponse{
+		Song: toSongResponse(images, result.Song),
+		File: SongFileResponse{
+			Quality: result.File.Quality,
+			Format:  result.File.Format,
+			Bitrate: result.File.Bitrate,
+			Size:    result.File.Size,
+		},
+	}
+}
+
+func toUploadSessionResponse(session *domain.UploadSession, received []int) UploadSessionResponse {
+	if received == nil {
+		received = []int{}
+	}
+	return UploadSessionResponse{
+		ID:         session.ID,
+		FileName:   session.FileName,
+		Size:       session.Size,
+		ChunkSize:  session.ChunkSize,
+		ChunkCount: session.ChunkCount,
+		Received:   received,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
+
+// CloudSongResponse 云盘歌曲，matched 表示已匹配到曲库歌曲
+type CloudSongResponse struct {
+	ID        int64        `json:"id"`
+	Song      SongResponse `json:"song"`
+	Matched   bool         `json:"matched"`
+	FileName  string       `json:"file_name"`
+	Format    string       `json:"format"`
+	Quality   string       `json:"quality"`
+	Bitrate   int          `json:"bitrate"`
+	Size      int64        `json:"size"`
+	CreatedAt int64        `json:"created_at"`
+}
+
+// CloudSongPageResponse 云盘歌曲分页列表
+type CloudSongPageResponse struct {
+	List     []CloudSongResponse `json:"list"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// CloudUsageResponse 云盘用量（字节）
+type CloudUsageResponse struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	QuotaBytes int64 `json:"quota_bytes"`
+	SongCount  int   `json:"song_count"`
+}
+
+func toCloudSongResponse(images *media.ImageLinker, song *cloud.CloudSong) CloudSongResponse {
+	return CloudSongResponse{
+		ID:        song.ID,
+		Song:      toSongResponse(images, song.Song),
+		Matched:   song.Matched,
+		FileName:  song.FileName,
+		Format:    song.Format,
+		Quality:   song.Quality,
+		Bitrate:   song.Bitrate,
+		Size:      song.Size,
+		CreatedAt: song.CreatedAt,
+	}
+}
+
+// LyricLineResponse 一行歌词，time 为开始时间（毫秒）
+type LyricLineResponse struct {
+	Time        int64  `json:"time"`
+	Text        string `json:"text"`
+	Translation string `json:"translation,omitempty"`
+}
+
+// LyricResponse 歌词。synced 为 true 时按 lines 的时间轴滚动显示，否则显示 plain
+type LyricResponse struct {
+	SongID         int64               `json:"song_id"`
+	Synced         bool                `json:"synced"`
+	Lines          []LyricLineResponse `json:"lines"`
+	Plain          string              `json:"plain"`
+	HasTranslation bool                `json:"has_translation"`
+	Contributor    string              `json:"contributor"`
+	UpdatedAt      int64               `json:"updated_at"`
+}
+
+// LyricRequest 编辑歌词请求体，整体替换原有歌词
+type LyricRequest struct {
+	Plain         string `json:"plain"`
+	LRC           string `json:"lrc"`
+	TranslatedLRC string `json:"translated_lrc"`
+	Contributor   string `json:"contributor"`
+}
+
+// LyricDetailResponse 歌词原文，供管理后台编辑
+type LyricDetailResponse struct {
+	SongID        int64  `json:"song_id"`
+	Plain         string `json:"plain"`
+	LRC           string `json:"lrc"`
+	TranslatedLRC string `json:"translated_lrc"`
+	Contributor   string `json:"contributor"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+func toLyricResponse(lyric *catalog.LyricResult) LyricResponse {
+	lines := make([]LyricLineResponse, 0, len(lyric.Lines))
+	for _, line := range lyric.Lines {
+		lines = append(lines, LyricLineResponse{Time: line.Time, Text: line.Text, Translation: line.Translation})
+	}
+	return LyricResponse{
+		SongID:         lyric.SongID,
+		Synced:         lyric.Synced,
+		Lines:          lines,
+		Plain:          lyric.Plain,
+		HasTranslation: lyric.HasTranslation,
+		Contributor:    lyric.Contributor,
+		UpdatedAt:      lyric.UpdatedAt,
+	}
+}
+
+func toLyricDetailResponse(lyric *domain.Lyric) LyricDetailResponse {
+	return LyricDetailResponse{
+		SongID:        lyric.SongID,
+		Plain:         lyric.Plain,
+		LRC:           lyric.LRC,
+		TranslatedLRC: lyric.TranslatedLRC,
+		Contributor:   lyric.Contributor,
+		UpdatedAt:     lyric.UpdatedAt,
+	}
+}
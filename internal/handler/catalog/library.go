@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/domain"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/library"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LibraryHandler 我喜欢的音乐与收藏
+type LibraryHandler struct {
+	LibraryService *service.LibraryService
+	Images         *media.ImageLinker
+}
+
+func NewLibraryHandler(libraryService *service.LibraryService, images *media.ImageLinker) *LibraryHandler {
+	return &LibraryHandler{LibraryService: libraryService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *LibraryHandler) RegisterRoutes(r gin.IRouter) {
+	me := r.Group("/users/me", middleware.Auth())
+	{
+		me.GET("/likes", h.listLikes)
+		me.GET("/likes/ids", h.likedIDs)
+		me.POST("/likes", h.like)
+		me.DELETE("/likes/:song_id", h.unlike)
+
+		me.GET("/subscriptions", h.listSubscriptions)
+		me.POST("/subscriptions", h.subscribe)
+		me.DELETE("/subscriptions/:type/:target_id", h.unsubscribe)
+	}
+}
+
+// listLikes 我喜欢的音乐
+// @Summary      我喜欢的音乐
+// @Description  分页返回喜欢的歌曲，最近喜欢的在前
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int  true   "用户ID"
+// @Param        page       query     int  false  "页码，从 1 开始"
+// @Param        page_size  query     int  false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=LikedSongPageResponse}
+// @Router       /api/users/me/likes [get]
+func (h *LibraryHandler) listLikes(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	page, pageSize := utils.ParsePage(c)
+	likes, total, err := h.LibraryService.ListLikedSongs(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	list := make([]LikedSongResponse, 0, len(likes))
+	for _, like := range likes {
+		list = append(list, LikedSongResponse{Song: toSongResponse(h.Images, like.Song), LikedAt: like.LikedAt})
+	}
+	utils.Success(c, LikedSongPageResponse{List: list, Total: total, Page: page, PageSize: pageSize})
+}
+
+// likedIDs 喜欢的歌曲 ID
+// @Summary      喜欢的歌曲ID列表
+// @Description  返回全部喜欢的歌曲 ID，供客户端标记红心
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Success      200        {object}  utils.Response{data=[]int64}
+// @Router       /api/users/me/likes/ids [get]
+func (h *LibraryHandler) likedIDs(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	ids, err := h.LibraryService.LikedSongIDs(c.Request.Context(), userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, ids)
+}
+
+// like 喜欢歌曲
+// @Summary      喜欢歌曲
+// @Description  重复喜欢不报错；同时记为 like 行为供推荐使用
+// @Tags         我的音乐
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int          true  "用户ID"
+// @Param        request    body      LikeRequest  true  "歌曲"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/likes [post]
+func (h *LibraryHandler) like(c *gin.Context) {
+	var req LikeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.LibraryService.LikeSong(c.Request.Context(), userID, req.SongID); err != nil {
+		respondLibraryError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// unlike 取消喜欢
+// @Summary      取消喜欢
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Param        song_id    path      int  true  "歌曲ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/likes/{song_id} [delete]
+func (h *LibraryHandler) unlike(c *gin.Context) {
+	songID, err := strconv.ParseInt(c.Param("song_id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid song id")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.LibraryService.UnlikeSong(c.Request.Context(), userID, songID); err != nil {
+		respondLibraryError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// listSubscriptions 我的收藏
+// @Summary      我的收藏
+// @Description  分页返回收藏的歌单、专辑或关注的歌手，最近收藏的在前
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int     true   "用户ID"
+// @Param        type       query     string  false  "收藏类型 playlist/album/artist，默认 playlist"
+// @Param        page       query     int     false  "页码，从 1 开始"
+// @Param        page_size  query     int     false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=SubscriptionPageResponse}
+// @Router       /api/users/me/subscriptions [get]
+func (h *LibraryHandler) listSubscriptions(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	page, pageSize := utils.ParsePage(c)
+	result, err := h.LibraryService.ListSubscriptions(c.Request.Context(), userID,
+		c.DefaultQuery("type", domain.SubscribePlaylist), page, pageSize)
+	if err != nil {
+		respondLibraryError(c, err)
+		return
+	}
+	resp := SubscriptionPageResponse{Type: result.Type, Total: result.Total, Page: page, PageSize: pageSize}
+	for _, playlist := range result.Playlists {
+		resp.Playlists = append(resp.Playlists, toPlaylistResponse(h.Images, playlist))
+	}
+	for _, album := range result.Albums {
+		resp.Albums = append(resp.Albums, toAlbumResponse(h.Images, album))
+	}
+	for _, artist := range result.Artists {
+		resp.Artists = append(resp.Artists, toArtistResponse(h.Images, artist))
+	}
+	utils.Success(c, resp)
+}
+
+// subscribe 收藏
+// @Summary      收藏
+// @Description  收藏歌单、专辑或关注歌手，重复收藏不报错；不能收藏自己创建的歌单
+// @Tags         我的音乐
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int               true  "用户ID"
+// @Param        request    body      SubscribeRequest  true  "收藏目标"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/subscriptions [post]
+func (h *LibraryHandler) subscribe(c *gin.Context) {
+	var req SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.LibraryService.Subscribe(c.Request.Context(), userID, req.Type, req.TargetID); err != nil {
+		respondLibraryError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// unsubscribe 取消收藏
+// @Summary      取消收藏
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int     true  "用户ID"
+// @Param        type       path      string  true  "收藏类型 playlist/album/artist"
+// @Param        target_id  path      int     true  "收藏目标ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/subscriptions/{type}/{target_id} [delete]
+func (h *LibraryHandler) unsubscribe(c *gin.Context) {
+	targetID, err := strconv.ParseInt(c.Param("target_id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid target id")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.LibraryService.Unsubscribe(c.Request.Context(), userID, c.Param("type"), targetID); err != nil {
+		respondLibraryError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+func respondLibraryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSubscriptionType), errors.Is(err, service.ErrSubscribeOwnPlaylist):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrTargetNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"unicode/utf8"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/catalog"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLyricFileSize 上传的歌词文件上限
+const maxLyricFileSize = 256 << 10
+
+// LyricHandler 歌词读取
+type LyricHandler struct {
+	LyricService *service.LyricService
+}
+
+func NewLyricHandler(lyricService *service.LyricService) *LyricHandler {
+	return &LyricHandler{LyricService: lyricService}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *LyricHandler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/songs/:id/lyrics", h.get)
+}
+
+// get 歌词
+// @Summary      歌词
+// @Description  有时间轴时返回按时间排序的歌词行（已按 offset 修正），翻译按时间对齐到原文行；否则只返回纯文本
+// @Tags         曲库
+// @Produce      json
+// @Param        id   path      int  true  "歌曲ID"
+// @Success      200  {object}  utils.Response{data=LyricResponse}
+// @Router       /api/songs/{id}/lyrics [get]
+func (h *LyricHandler) get(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	lyric, err := h.LyricService.GetLyric(c.Request.Context(), id)
+	if err != nil {
+		respondLyricError(c, err)
+		return
+	}
+	utils.Success(c, toLyricResponse(lyric))
+}
+
+// LyricAdminHandler 歌词管理后台接口
+type LyricAdminHandler struct {
+	LyricService *service.LyricService
+	adminToken   string
+}
+
+func NewLyricAdminHandler(lyricService *service.LyricService, adminToken string) *LyricAdminHandler {
+	return &LyricAdminHandler{LyricService: lyricService, adminToken: adminToken}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *LyricAdminHandler) RegisterRoutes(r gin.IRouter) {
+	lyrics := r.Group("/admin/songs/:id/lyrics", middleware.Admin(h.adminToken))
+	{
+		lyrics.GET("", h.get)
+		lyrics.PUT("", h.update)
+		lyrics.POST("/upload", h.upload)
+		lyrics.DELETE("", h.delete)
+	}
+}
+
+// get 歌词原文
+// @Summary      歌词原文
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      int     true  "歌曲ID"
+// @Success      200            {object}  utils.Response{data=LyricDetailResponse}
+// @Router       /api/admin/songs/{id}/lyrics [get]
+func (h *LyricAdminHandler) get(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	lyric, err := h.LyricService.GetRaw(c.Request.Context(), id)
+	if err != nil {
+		respondLyricError(c, err)
+		return
+	}
+	utils.Success(c, toLyricDetailResponse(lyric))
+}
+
+// update 编辑歌词
+// @Summary      编辑歌词
+// @Description  整体替换歌词。plain 与 lrc 至少填一个，translated_lrc 需要 lrc；LRC 校验失败时返回出错的行号
+// @Tags         管理后台
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-Token  header    string        true  "管理令牌"
+// @Param        id             path      int           true  "歌曲ID"
+// @Param        request        body      LyricRequest  true  "歌词"
+// @Success      200            {object}  utils.Response{data=LyricDetailResponse}
+// @Router       /api/admin/songs/{id}/lyrics [put]
+func (h *LyricAdminHandler) update(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	var req LyricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	lyric, err := h.LyricService.SaveLyric(c.Request.Context(), id, service.LyricInput{
+		Plain:         &req.Plain,
+		LRC:           &req.LRC,
+		TranslatedLRC: &req.TranslatedLRC,
+		Contributor:   &req.Contributor,
+	})
+	if err != nil {
+		respondLyricError(c, err)
+		return
+	}
+	utils.Success(c, toLyricDetailResponse(lyric))
+}
+
+// upload 上传歌词文件
+// @Summary      上传歌词文件
+// @Description  上传 .lrc 原文、翻译或 .txt 纯文本（各不超过 256KB），只替换本次上传的部分
+// @Tags         管理后台
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true   "管理令牌"
+// @Param        id             path      int     true   "歌曲ID"
+// @Param        lrc            formData  file    false  "LRC 歌词"
+// @Param        translation    formData  file    false  "LRC 翻译"
+// @Param        plain          formData  file    false  "纯文本歌词"
+// @Param        contributor    formData  string  false  "贡献者"
+// @Success      200            {object}  utils.Response{data=LyricDetailResponse}
+// @Router       /api/admin/songs/{id}/lyrics/upload [post]
+func (h *LyricAdminHandler) upload(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	var input service.LyricInput
+	for field, dst := range map[string]**string{"lrc": &input.LRC, "translation": &input.TranslatedLRC, "plain": &input.Plain} {
+		fileHeader, err := c.FormFile(field)
+		if errors.Is(err, http.ErrMissingFile) {
+			continue
+		}
+		if err != nil {
+			utils.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		text, err := readLyricFile(fileHeader)
+		if err != nil {
+			utils.Error(c, http.StatusBadRequest, field+": "+err.Error())
+			return
+		}
+		*dst = &text
+	}
+	if input.LRC == nil && input.TranslatedLRC == nil && input.Plain == nil {
+		utils.Error(c, http.StatusBadRequest, "lrc, translation or plain file is required")
+		return
+	}
+	if contributor, ok := c.GetPostForm("contributor"); ok {
+		input.Contributor = &contributor
+	}
+	lyric, err := h.LyricService.SaveLyric(c.Request.Context(), id, input)
+	if err != nil {
+		respondLyricError(c, err)
+		return
+	}
+	utils.Success(c, toLyricDetailResponse(lyric))
+}
+
+// delete 删除歌词
+// @Summary      删除歌词
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      int     true  "歌曲ID"
+// @Success      200            {object}  utils.Response
+// @Router       /api/admin/songs/{id}/lyrics [delete]
+func (h *LyricAdminHandler) delete(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	if err := h.LyricService.DeleteLyric(c.Request.Context(), id); err != nil {
+		respondLyricError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// readLyricFile 读取歌词文件，要求为 UTF-8 文本
+func readLyricFile(fileHeader *multipart.FileHeader) (string, error) {
+	if fileHeader.Size > maxLyricFileSize {
+		return "", errors.New("file too large")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxLyricFileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxLyricFileSize {
+		return "", errors.New("file too large")
+	}
+	if !utf8.Valid(data) {
+		return "", errors.New("file must be utf-8 encoded")
+	}
+	return string(data), nil
+}
+
+func respondLyricError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidLyric):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrLyricNotFound), errors.Is(err, service.ErrSongNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
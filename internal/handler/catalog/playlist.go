@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"wyy/internal/middleware"
+	media "wyy/internal/service/media"
+	service "wyy/internal/service/playlist"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistHandler struct {
+	PlaylistService *service.PlaylistService
+	Images          *media.ImageLinker
+}
+
+func NewPlaylistHandler(playlistService *service.PlaylistService, images *media.ImageLinker) *PlaylistHandler {
+	return &PlaylistHandler{PlaylistService: playlistService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *PlaylistHandler) RegisterRoutes(r gin.IRouter) {
+	playlists := r.Group("/playlists")
+	{
+		playlists.POST("", middleware.Auth(), h.create)
+		playlists.GET("/:id", middleware.OptionalAuth(), h.get)
+		playlists.PUT("/:id", middleware.Auth(), h.update)
+		playlists.DELETE("/:id", middleware.Auth(), h.delete)
+		playlists.POST("/:id/songs", middleware.Auth(), h.addSongs)
+		playlists.DELETE("/:id/songs", middleware.Auth(), h.removeSongs)
+		playlists.PUT("/:id/songs/order", middleware.Auth(), h.moveSong)
+	}
+	r.GET("/users/:id/playlists", middleware.OptionalAuth(), h.listByUser)
+}
+
+// create 创建歌单
+// @Summary      创建歌单
+// @Tags         歌单
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int              true  "用户ID"
+// @Param        request    body      PlaylistRequest  true  "歌单信息"
+// @Success      200        {object}  utils.Response{data=PlaylistResponse}
+// @Router       /api/playlists [post]
+func (h *PlaylistHandler) create(c *gin.Context) {
+	var req PlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	playlist := req.toPlaylist(0)
+	if err := h.PlaylistService.Create(c.Request.Context(), userID, playlist); err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+	utils.Success(c, toPlaylistResponse(h.Images, playlist))
+}
+
+// get 歌单详情
+// @Summary      歌单详情
+// @Description  返回歌单信息和按顺序排列的歌曲；非公开歌单只有创建者可见
+// @Tags         歌单
+// @Produce      json
+// @Param        X-User-ID  header    int  false  "用户ID"
+// @Param        id         path      int  true   "歌单ID"
+// @Success      200        {object}  utils.Response{data=PlaylistDetailResponse}
+// @Router       /api/playlists/{id} [get]
+func (h *PlaylistHandler) get(c *gin.Context) {
+	id, ok := pathID(c, "playlist")
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	detail, err := h.PlaylistService.GetDetail(c.Request.Context(), userID, id)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+	utils.Success(c, PlaylistDetailResponse{
+		Playlist: toPlaylistResponse(h.Images, detail.Playlist),
+		Songs:    toSongResponses(h.Images, detail.Songs),
+	})
+}
+
+// update 更新歌单信息
+// @Summary      更新歌单
+// @Description  更新名称、描述、封面、标签和公开状态，只有创建者可以修改
+// @Tags         歌单
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int              true  "用户ID"
+// @Param        id         path      int              true  "歌单ID"
+// @Param        request    body      PlaylistRequest  true  "歌单信息"
+// @Success      200        {object}  utils.Response{data=PlaylistResponse}
+// @Router       /api/playlists/{id} [put]
+func (h *PlaylistHandler) update(c *gin.Context) {
+	id, ok := pathID(c, "playlist")
+	if !ok {
+		return
+	}
+	var req PlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	playlist := req.toPlaylist(id)
+	if err := h.PlaylistService.Update(c.Request.Context(), userID, playlist); err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+	utils.Success(c, toPlaylistResponse(h.Images, playlist))
+}
+
+// delete 删除歌单
+// @Summary      删除歌单
+// @Tags         歌单
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Param        id         path      int  true  "歌单ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/playlists/{id} [delete]
+func (h *PlaylistHandler) delete(c *gin.Context) {
+	id, ok := pathID(c, "playlist")
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.PlaylistService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// addSongs 添加歌曲
+// @Summary      歌单添加歌曲
+// @Description  按顺序追加到歌单末尾，已在歌单中的歌曲被忽略，单次最多 100 首
+// @Tags         歌单
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int                   true  "用户ID"
+// @Param        id         path      int                   true  "歌单ID"
+// @Param        request    body      PlaylistSongsRequest  true  "歌曲ID列表"
+// @Success      200        {object}  utils.Response{data=PlaylistSongsResponse}
+// @Router       /api/playlists/{id}/songs [post]
+func (h *PlaylistHandler) addSongs(c *gin.Context) {
+	id, ok := pathID(c, "playlist")
+	if !ok {
+		return
+	}
+	var req PlaylistSongsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	added, err := h.PlaylistService.AddSongs(c.Request.Context(), userID, id, req.SongIDs)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+	utils.Success(c, PlaylistSongsResponse{Affected: added})
+}
+
+// removeSongs 移除歌曲
+// @Summary      歌单移除歌曲
+// @Tags         歌单
+// @Produce      json
+// @Param        X-User-ID  header    int     true  "用户ID"
+// @Param        id         path      int     true  "歌单ID"
+// @Param        song_ids   query     string  true  "歌曲ID列表，逗号分隔，最多 100 首"
+// @Success      200        {object}  utils.Response{data=PlaylistSongsResponse}
+// @Router       /api/playlists/{id}/songs [delete]
+func (h *PlaylistHandler) removeSongs(c *gin.Context) {
+	id, ok := pathID(c, "playlist")
+	if !ok {
+		return
+	}
+	songIDs, ok := parseIDs(c, c.Query("song_ids"), "song", service.MaxPlaylistBatch)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	removed, err := h.PlaylistService.RemoveSongs(c.Request.Context(), userID, id, songIDs)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+	utils.Success(c, PlaylistSongsResponse{Affected: removed})
+}
+
+// moveSong 拖动排序
+// @Summary      歌单歌曲排序
+// @Description  将歌曲移动到 after_song_id 之后，after_song_id 为 0 时移到最前
+// @Tags         歌单
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int                  true  "用户ID"
+// @Param        id         path      int                  true  "歌单ID"
+// @Param        request    body      PlaylistMoveRequest  true  "移动位置"
+// @Success      200        {object}  utils.Response
+// @Router       /api/playlists/{id}/songs/order [put]
+func (h *PlaylistHandler) moveSong(c *gin.Context) {
+	id, ok := pathID(c, "playlist")
+	if !ok {
+		return
+	}
+	var req PlaylistMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.PlaylistService.MoveSong(c.Request.Context(), userID, id, req.SongID, req.AfterSongID); err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// listByUser 用户创建的歌单
+// @Summary      用户歌单列表
+// @Description  返回用户创建的歌单，本人可以看到非公开歌单
+// @Tags         歌单
+// @Produce      json
+// @Param        X-User-ID  header    int  false  "用户ID"
+// @Param        id         path      int  true   "歌单创建者ID"
+// @Param        page       query     int  false  "页码，从 1 开始"
+// @Param        page_size  query     int  false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=PlaylistPageResponse}
+// @Router       /api/users/{id}/playlists [get]
+func (h *PlaylistHandler) listByUser(c *gin.Context) {
+	ownerID, ok := pathID(c, "user")
+	if !ok {
+		return
+	}
+	page, pageSize := utils.ParsePage(c)
+	userID, _ := middleware.GetUserID(c)
+	playlists, total, err := h.PlaylistService.ListByUser(c.Request.Context(), userID, ownerID, page, pageSize)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	list := make([]PlaylistResponse, 0, len(playlists))
+	for _, playlist := range playlists {
+		list = append(list, toPlaylistResponse(h.Images, playlist))
+	}
+	utils.Success(c, PlaylistPageResponse{List: list, Total: total, Page: page, PageSize: pageSize})
+}
+
+func respondPlaylistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPlaylist):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrPlaylistForbidden):
+		utils.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrPlaylistNotFound), errors.Is(err, service.ErrSongNotInPlaylist):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
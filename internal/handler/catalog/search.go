@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"wyy/internal/middleware"
+	"wyy/internal/search"
+	media "wyy/internal/service/media"
+	service "wyy/internal/service/search"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSuggestions 联想词条数上限
+const maxSuggestions = 10
+
+type SearchHandler struct {
+	SearchService    *service.SearchService
+	HotSearchService *service.HotSearchService
+	Images           *media.ImageLinker
+}
+
+func NewSearchHandler(searchService *service.SearchService, hotSearchService *service.HotSearchService, images *media.ImageLinker) *SearchHandler {
+	return &SearchHandler{SearchService: searchService, HotSearchService: hotSearchService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *SearchHandler) RegisterRoutes(r gin.IRouter) {
+	search := r.Group("/search")
+	{
+		search.GET("", middleware.OptionalAuth(), h.search)
+		search.GET("/suggest", h.suggest)
+		search.GET("/hot", h.hot)
+	}
+}
+
+// search 全文搜索
+// @Summary      搜索
+// @Description  按关键词搜索歌曲、歌手、专辑或歌单，支持中文分词、拼音及首字母、前缀匹配，结果按相关度与热度排序
+// @Tags         搜索
+// @Produce      json
+// @Param        q          query     string  true   "关键词"
+// @Param        type       query     string  false  "类型 song/artist/album/playlist，默认 song"
+// @Param        page       query     int     false  "页码，从 1 开始"
+// @Param        page_size  query     int     false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=SearchResponse}
+// @Router       /api/search [get]
+func (h *SearchHandler) search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		utils.Error(c, http.StatusBadRequest, "missing q")
+		return
+	}
+	page, pageSize := utils.ParsePage(c)
+	result, err := h.SearchService.Search(c.Request.Context(), c.DefaultQuery("type", search.TypeSong), q, page, pageSize)
+	if errors.Is(err, service.ErrInvalidSearchType) {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 翻页不重复计入热搜
+	if page == 1 {
+		userID, _ := middleware.GetUserID(c)
+		h.HotSearchService.Log(c.Request.Context(), userID, q)
+	}
+
+	resp := SearchResponse{Type: result.Type, Total: result.Total, Page: page, PageSize: pageSize}
+	if result.Songs != nil {
+		resp.Songs = toSongResponses(h.Images, result.Songs)
+	}
+	for _, artist := range result.Artists {
+		resp.Artists = append(resp.Artists, toArtistResponse(h.Images, artist))
+	}
+	for _, album := range result.Albums {
+		resp.Albums = append(resp.Albums, toAlbumResponse(h.Images, album))
+	}
+	for _, playlist := range result.Playlists {
+		resp.Playlists = append(resp.Playlists, toPlaylistResponse(h.Images, playlist))
+	}
+	utils.Success(c, resp)
+}
+
+// suggest 搜索联想
+// @Summary      搜索联想
+// @Description  输入时实时联想，按名称、拼音全拼或首字母前缀匹配，混合返回歌曲、歌手、专辑
+// @Tags         搜索
+// @Produce      json
+// @Param        q      query     string  true   "已输入的前缀"
+// @Param        limit  query     int     false  "返回数量，默认 10，最多 10"
+// @Success      200    {object}  utils.Response{data=[]SuggestionResponse}
+// @Router       /api/search/suggest [get]
+func (h *SearchHandler) suggest(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(maxSuggestions)))
+	if err != nil || limit <= 0 || limit > maxSuggestions {
+		utils.Error(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	suggestions := h.SearchService.Suggest(c.Query("q"), limit)
+	resp := make([]SuggestionResponse, 0, len(suggestions))
+	for _, sug := range suggestions {
+		resp = append(resp, SuggestionResponse{Type: sug.Type, ID: sug.ID, Text: sug.Text})
+	}
+	utils.Success(c, resp)
+}
+
+// hot 热搜榜
+// @Summary      热搜榜
+// @Description  按近期搜索次数随时间衰减计算，已过滤屏蔽词
+// @Tags         搜索
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]HotKeywordResponse}
+// @Router       /api/search/hot [get]
+func (h *SearchHandler) hot(c *gin.Context) {
+	hot, err := h.HotSearchService.Hot(c.Request.Context())
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := make([]HotKeywordResponse, 0, len(hot))
+	for _, kw := range hot {
+		resp = append(resp, HotKeywordResponse{Query: kw.Query, Score: kw.Score})
+	}
+	utils.Success(c, resp)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
+	repo "wyy/internal/repo/search"
+	service "wyy/internal/service/search"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchAdminHandler 热搜屏蔽词管理后台接口
+type SearchAdminHandler struct {
+	HotSearchService *service.HotSearchService
+	adminToken       string
+}
+
+func NewSearchAdminHandler(hotSearchService *service.HotSearchService, adminToken string) *SearchAdminHandler {
+	return &SearchAdminHandler{HotSearchService: hotSearchService, adminToken: adminToken}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *SearchAdminHandler) RegisterRoutes(r gin.IRouter) {
+	terms := r.Group("/admin/search/blocked-terms", middleware.Admin(h.adminToken))
+	{
+		terms.GET("", h.list)
+		terms.POST("", h.create)
+		terms.DELETE("/:id", h.delete)
+	}
+}
+
+// list 屏蔽词列表
+// @Summary      热搜屏蔽词列表
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Success      200            {object}  utils.Response{data=[]BlockedTermResponse}
+// @Router       /api/admin/search/blocked-terms [get]
+func (h *SearchAdminHandler) list(c *gin.Context) {
+	terms, err := h.HotSearchService.ListBlockedTerms(c.Request.Context())
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := make([]BlockedTermResponse, 0, len(terms))
+	for _, term := range terms {
+		resp = append(resp, toBlockedTermResponse(term))
+	}
+	utils.Success(c, resp)
+}
+
+// create 添加屏蔽词
+// @Summary      添加热搜屏蔽词
+// @Description  包含该词的搜索词不再出现在热搜榜，立即生效
+// @Tags         管理后台
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-Token  header    string              true  "管理令牌"
+// @Param        request        body      BlockedTermRequest  true  "屏蔽词"
+// @Success      200            {object}  utils.Response{data=BlockedTermResponse}
+// @Router       /api/admin/search/blocked-terms [post]
+func (h *SearchAdminHandler) create(c *gin.Context) {
+	var req BlockedTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	term, err := h.HotSearchService.BlockTerm(c.Request.Context(), req.Term)
+	if errors.Is(err, service.ErrInvalidBlockedTerm) {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, toBlockedTermResponse(term))
+}
+
+// delete 删除屏蔽词
+// @Summary      删除热搜屏蔽词
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      int     true  "屏蔽词ID"
+// @Success      200            {object}  utils.Response
+// @Router       /api/admin/search/blocked-terms/{id} [delete]
+func (h *SearchAdminHandler) delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid blocked term id")
+		return
+	}
+	err = h.HotSearchService.UnblockTerm(c.Request.Context(), id)
+	if errors.Is(err, service.ErrBlockedTermNotFound) {
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, nil)
+}
+
+func toBlockedTermResponse(term *repo.BlockedTerm) BlockedTermResponse {
+	return BlockedTermResponse{ID: term.ID, Term: term.Term, CreatedAt: term.CreatedAt}
+}
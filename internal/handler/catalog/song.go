@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"wyy/internal/domain"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/catalog"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SongHandler struct {
+	SongService *service.SongService
+	Images      *media.ImageLinker
+}
+
+func NewSongHandler(songService *service.SongService, images *media.ImageLinker) *SongHandler {
+	return &SongHandler{SongService: songService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *SongHandler) RegisterRoutes(r gin.IRouter) {
+	songs := r.Group("/songs")
+	{
+		songs.GET("", middleware.OptionalAuth(), h.listSongs)
+		songs.GET("/:id", middleware.OptionalAuth(), h.getSong)
+	}
+}
+
+// getSong 歌曲详情
+// @Summary      歌曲详情
+// @Description  云盘私有歌曲只有上传者可见
+// @Tags         曲库
+// @Produce      json
+// @Param        X-User-ID  header    int  false  "用户ID"
+// @Param        id         path      int  true   "歌曲ID"
+// @Success      200        {object}  utils.Response{data=SongResponse}
+// @Router       /api/songs/{id} [get]
+func (h *SongHandler) getSong(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	song, err := h.SongService.GetSong(c.Request.Context(), userID, id)
+	if errors.Is(err, service.ErrSongNotFound) {
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, toSongResponse(h.Images, song))
+}
+
+// listSongs 批量获取或分页查询歌曲
+// @Summary      歌曲列表
+// @Description  传 ids 时按顺序批量返回歌曲详情（最多 100 首）；否则按条件分页查询，按发布时间倒序
+// @Tags         曲库
+// @Produce      json
+// @Param        X-User-ID         header    int     false  "用户ID"
+// @Param        ids               query     string  false  "歌曲ID列表，逗号分隔，云盘私有歌曲只对上传者返回"
+// @Param        tag               query     string  false  "标签"
+// @Param        artist_id         query     int     false  "歌手ID"
+// @Param        published_after   query     int     false  "发布时间下限（时间戳，含）"
+// @Param        published_before  query     int     false  "发布时间上限（时间戳，不含）"
+// @Param        page              query     int     false  "页码，从 1 开始"
+// @Param        page_size         query     int     false  "每页数量，默认 20，最多 100"
+// @Success      200               {object}  utils.Response{data=SongPageResponse}
+// @Router       /api/songs [get]
+func (h *SongHandler) listSongs(c *gin.Context) {
+	if idsParam := c.Query("ids"); idsParam != "" {
+		h.batchSongs(c, idsParam)
+		return
+	}
+
+	filter := domain.SongFilter{Tag: c.Query("tag")}
+	var err error
+	if filter.ArtistID, err = queryInt64(c, "artist_id"); err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid artist_id")
+		return
+	}
+	if filter.PublishedAfter, err = queryInt64(c, "published_after"); err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid published_after")
+		return
+	}
+	if filter.PublishedBefore, err = queryInt64(c, "published_before"); err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid published_before")
+		return
+	}
+	filter.Page, filter.PageSize = utils.ParsePage(c)
+
+	songs, total, err := h.SongService.ListSongs(c.Request.Context(), filter)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, SongPageResponse{
+		List:     toSongResponses(h.Images, songs),
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	})
+}
+
+func (h *SongHandler) batchSongs(c *gin.Context, idsParam string) {
+	ids, ok := parseIDs(c, idsParam, "song", service.MaxBatchSongs)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	songs, err := h.SongService.GetSongs(c.Request.Context(), userID, ids)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, SongPageResponse{
+		List:     toSongResponses(h.Images, songs),
+		Total:    int64(len(songs)),
+		Page:     1,
+		PageSize: len(ids),
+	})
+}
+
+// pathID 解析路径中的 :id 参数，失败时直接返回 400
+func pathID(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid "+name+" id")
+		return 0, false
+	}
+	return id, true
+}
+
+// parseIDs 解析逗号分隔的 ID 列表，失败时直接返回 400
+func parseIDs(c *gin.Context, param, name string, max int) ([]int64, bool) {
+	parts := strings.Split(param, ",")
+	if len(parts) > max {
+		utils.Error(c, http.StatusBadRequest, "too many ids")
+		return nil, false
+	}
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			utils.Error(c, http.StatusBadRequest, "invalid "+name+" id "+part)
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+func queryInt64(c *gin.Context, key string) (int64, error) {
+	v := c.Query(key)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
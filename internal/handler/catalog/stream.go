@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"wyy/internal/domain"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/catalog"
+	"wyy/internal/urlsign"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamHandler 音频播放，播放接口只接受 /songs/:id/url 签发的链接
+type StreamHandler struct {
+	StreamService *service.StreamService
+	Signer        *urlsign.Signer
+}
+
+func NewStreamHandler(streamService *service.StreamService, signer *urlsign.Signer) *StreamHandler {
+	return &StreamHandler{StreamService: streamService, Signer: signer}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *StreamHandler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/songs/:id/url", middleware.OptionalAuth(), h.urls)
+
+	signed := r.Group("", middleware.SignedURL(h.Signer))
+	signed.GET("/songs/:id/stream", h.stream)
+	signed.HEAD("/songs/:id/stream", h.stream)
+}
+
+// urls 获取播放链接
+// @Summary      获取播放链接
+// @Description  返回歌曲各音质的签名播放链接，链接在 expires_at 后失效；登录时链接绑定当前用户，曲库无法播放时使用该用户云盘中的同一首歌。指定 quality 时只返回该音质（不存在时自动降级）
+// @Tags         曲库
+// @Produce      json
+// @Param        id         path      int     true   "歌曲ID"
+// @Param        quality    query     string  false  "音质 standard/higher/lossless，为空时返回全部音质"
+// @Param        X-User-ID  header    int     false  "用户ID"
+// @Success      200        {object}  utils.Response{data=SongURLsResponse}
+// @Router       /api/songs/{id}/url [get]
+func (h *StreamHandler) urls(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	var files []*domain.SongFile
+	if quality := c.Query("quality"); quality != "" {
+		file, err := h.StreamService.SelectFile(c.Request.Context(), id, quality, userID)
+		if err != nil {
+			respondStreamError(c, err)
+			return
+		}
+		files = []*domain.SongFile{file}
+	} else {
+		var err error
+		files, err = h.StreamService.ListFiles(c.Request.Context(), id, userID)
+		if err != nil {
+			respondStreamError(c, err)
+			return
+		}
+	}
+
+	now := time.Now()
+	path := fmt.Sprintf("/api/songs/%d/stream", id)
+	resp := SongURLsResponse{SongID: id, URLs: make([]SongURLResponse, 0, len(files))}
+	for _, file := range files {
+		// 链接固定为实际音质，播放时不再降级
+		link, expires := h.Signer.Sign(path, url.Values{"quality": {file.Quality}}, userID, now)
+		resp.URLs = append(resp.URLs, SongURLResponse{
+			Quality:   file.Quality,
+			URL:       link,
+			Format:    file.Format,
+			Bitrate:   file.Bitrate,
+			Size:      file.Size,
+			ExpiresAt: expires.Unix(),
+		})
+	}
+	utils.Success(c, resp)
+}
+
+// stream 播放音频
+// @Summary      播放音频
+// @Description  通过 /api/songs/{id}/url 签发的链接访问，签名无效或过期时返回 403。返回音频文件，支持 Range 断点与拖动（206）、ETag 缓存校验；请求的音质不存在时自动降级
+// @Tags         曲库
+// @Produce      audio/mpeg
+// @Param        id             path      int     true   "歌曲ID"
+// @Param        quality        query     string  false  "音质 standard/higher/lossless，默认 standard"
+// @Param        expires        query     int     true   "链接过期时间（Unix 秒）"
+// @Param        uid            query     int     false  "链接绑定的用户ID"
+// @Param        sig            query     string  true   "链接签名"
+// @Param        Range          header    string  false  "字节范围，如 bytes=0-"
+// @Param        If-None-Match  header    string  false  "上次返回的 ETag"
+// @Success      200            {file}    file
+// @Success      206            {file}    file
+// @Router       /api/songs/{id}/stream [get]
+func (h *StreamHandler) stream(c *gin.Context) {
+	id, ok := pathID(c, "song")
+	if !ok {
+		return
+	}
+	// uid 已由签名校验，云盘歌曲据此找到上传者的文件
+	userID, _ := strconv.ParseInt(c.Query(urlsign.ParamUserID), 10, 64)
+	s, err := h.StreamService.Open(c.Request.Context(), id, c.DefaultQuery("quality", domain.QualityStandard), userID)
+	if err != nil {
+		respondStreamError(c, err)
+		return
+	}
+	defer s.Reader.Close()
+
+	header := c.Writer.Header()
+	header.Set("ETag", strconv.Quote(s.Object.ETag))
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Cache-Control", "private, max-age=86400")
+	header.Set("X-Audio-Quality", s.File.Quality)
+	if s.Object.ContentType != "" {
+		header.Set("Content-Type", s.Object.ContentType)
+	}
+	// ServeContent 处理 Range/206、If-None-Match/304 和 If-Range，未设置 Content-Type 时按内容探测
+	http.ServeContent(c.Writer, c.Request, s.Object.Key, s.Object.ModTime, s.Reader)
+}
+
+func respondStreamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidQuality):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrSongNotFound), errors.Is(err, service.ErrNoAudio):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrSongUnavailable):
+		utils.Error(c, http.StatusUnavailableForLegalReasons, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
+	media "wyy/internal/service/media"
+	upload "wyy/internal/service/upload"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadAdminHandler 管理后台上传音频：小文件直接上传，大文件（如无损）使用分片断点续传
+type UploadAdminHandler struct {
+	UploadService *upload.UploadService
+	ImportService *upload.ImportService
+	Images        *media.ImageLinker
+	adminToken    string
+}
+
+func NewUploadAdminHandler(uploadService *upload.UploadService, importService *upload.ImportService, images *media.ImageLinker, adminToken string) *UploadAdminHandler {
+	return &UploadAdminHandler{UploadService: uploadService, ImportService: importService, Images: images, adminToken: adminToken}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *UploadAdminHandler) RegisterRoutes(r gin.IRouter) {
+	admin := r.Group("/admin", middleware.Admin(h.adminToken))
+	{
+		admin.POST("/songs/upload", h.upload)
+
+		admin.POST("/uploads", h.createSession)
+		admin.GET("/uploads/:id", h.getSession)
+		admin.PUT("/uploads/:id/chunks/:index", h.putChunk)
+		admin.POST("/uploads/:id/complete", h.complete)
+		admin.DELETE("/uploads/:id", h.abort)
+	}
+}
+
+// upload 上传音频
+// @Summary      上传音频
+// @Description  上传 MP3/FLAC/Ogg/M4A 音频，从 ID3、Vorbis Comment、MP4 标签中解析歌名、歌手、专辑、时长和封面并创建歌曲；指定 song_id 时为已有歌曲补充音质
+// @Tags         管理后台
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true   "管理令牌"
+// @Param        file           formData  file    true   "音频文件"
+// @Param        song_id        formData  int     false  "已有歌曲ID"
+// @Param        name           formData  string  false  "歌名"
+// @Param        artists        formData  []string  false  "歌手，可重复"
+// @Param        album          formData  string  false  "专辑"
+// @Param        language       formData  string  false  "语种 zh/en/ja/ko"
+// @Param        quality        formData  string  false  "音质 standard/higher/lossless"
+// @Success      200            {object}  utils.Response{data=SongImportResponse}
+// @Router       /api/admin/songs/upload [post]
+func (h *UploadAdminHandler) upload(c *gin.Context) {
+	var req SongImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	if fileHeader.Size > h.UploadService.MaxSize() {
+		respondUploadError(c, upload.ErrFileTooLarge)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	result, err := h.ImportService.Import(c.Request.Context(), file, fileHeader.Size, fileHeader.Filename, req.toSongInfo())
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+	utils.Success(c, toSongImportResponse(h.Images, result))
+}
+
+// createSession 创建分片上传
+// @Summary      创建分片上传
+// @Description  按返回的 chunk_size 切分文件，依次上传各分片后调用 complete；中断后可查询已收到的分片继续上传
+// @Tags         管理后台
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-Token  header    string                true  "管理令牌"
+// @Param        body           body      UploadSessionRequest  true  "文件信息"
+// @Success      200            {object}  utils.Response{data=UploadSessionResponse}
+// @Router       /api/admin/uploads [post]
+func (h *UploadAdminHandler) createSession(c *gin.Context) {
+	var req UploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	session, err := h.UploadService.Create(c.Request.Context(), 0, req.FileName, req.Size)
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+	utils.Success(c, toUploadSessionResponse(session, nil))
+}
+
+// getSession 查询分片上传进度
+// @Summary      查询分片上传进度
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      string  true  "上传ID"
+// @Success      200            {object}  utils.Response{data=UploadSessionResponse}
+// @Router       /api/admin/uploads/{id} [get]
+func (h *UploadAdminHandler) getSession(c *gin.Context) {
+	session, received, err := h.UploadService.Get(c.Request.Context(), 0, c.Param("id"))
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+	utils.Success(c, toUploadSessionResponse(session, received))
+}
+
+// putChunk 上传分片
+// @Summary      上传分片
+// @Description  请求体为分片的原始字节，除最后一片外大小必须等于 chunk_size；重复上传同一分片会覆盖
+// @Tags         管理后台
+// @Accept       application/octet-stream
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      string  true  "上传ID"
+// @Param        index          path      int     true  "分片序号，从 0 开始"
+// @Success      200            {object}  utils.Response
+// @Router       /api/admin/uploads/{id}/chunks/{index} [put]
+func (h *UploadAdminHandler) putChunk(c *gin.Context) {
+	putChunk(c, h.UploadService, 0)
+}
+
+// complete 完成分片上传
+// @Summary      完成分片上传
+// @Description  合并分片并按上传音频的规则创建歌曲，成功后删除分片
+// @Tags         管理后台
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-Token  header    string             true   "管理令牌"
+// @Param        id             path      string             true   "上传ID"
+// @Param        body           body      SongImportRequest  false  "歌曲信息"
+// @Success      200            {object}  utils.Response{data=SongImportResponse}
+// @Router       /api/admin/uploads/{id}/complete [post]
+func (h *UploadAdminHandler) complete(c *gin.Context) {
+	var req SongImportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	ctx := c.Request.Context()
+	file, err := h.UploadService.Assemble(ctx, 0, c.Param("id"))
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.ImportService.Import(ctx, file, file.Session.Size, file.Session.FileName, req.toSongInfo())
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+	if err := h.UploadService.Finish(ctx, file.Session.ID); err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, toSongImportResponse(h.Images, result))
+}
+
+// abort 取消分片上传
+// @Summary      取消分片上传
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      string  true  "上传ID"
+// @Success      200            {object}  utils.Response
+// @Router       /api/admin/uploads/{id} [delete]
+func (h *UploadAdminHandler) abort(c *gin.Context) {
+	if err := h.UploadService.Abort(c.Request.Context(), 0, c.Param("id")); err != nil {
+		respondUploadError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// putChunk 读取请求体作为分片，userID 为会话所属用户
+func putChunk(c *gin.Context, uploadService *upload.UploadService, userID int64) {
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid chunk index")
+		return
+	}
+	if c.Request.ContentLength < 0 {
+		utils.Error(c, http.StatusLengthRequired, "content-length is required")
+		return
+	}
+	err = uploadService.PutChunk(c.Request.Context(), userID, c.Param("id"), index, c.Request.Body, c.Request.ContentLength)
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+func respondUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, upload.ErrInvalidUpload), errors.Is(err, upload.ErrInvalidChunk),
+		errors.Is(err, upload.ErrInvalidAudio), errors.Is(err, upload.ErrInvalidSongInfo):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, upload.ErrUploadIncomplete):
+		utils.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, upload.ErrFileTooLarge):
+		utils.Error(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, upload.ErrUploadNotFound), errors.Is(err, upload.ErrSongNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
+	repo "wyy/internal/repo/comment"
+	service "wyy/internal/service/comment"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	CommentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{CommentService: commentService}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *CommentHandler) RegisterRoutes(r gin.IRouter) {
+	comments := r.Group("/comments")
+	{
+		comments.GET("", middleware.OptionalAuth(), h.list)
+		comments.POST("", middleware.Auth(), h.post)
+		comments.GET("/:id/replies", middleware.OptionalAuth(), h.replies)
+		comments.DELETE("/:id", middleware.Auth(), h.delete)
+		comments.POST("/:id/like", middleware.Auth(), h.like)
+		comments.DELETE("/:id/like", middleware.Auth(), h.unlike)
+	}
+}
+
+// list 评论列表
+// @Summary      评论列表
+// @Description  顶层评论列表，hot 按点赞数、latest 按发布时间倒序，游标分页
+// @Tags         评论
+// @Produce      json
+// @Param        X-User-ID  header    int     false  "用户ID，传入时标记已点赞的评论"
+// @Param        type       query     string  true   "评论目标类型 song/playlist/album"
+// @Param        target_id  query     int     true   "评论目标ID"
+// @Param        sort       query     string  false  "排序 hot/latest，默认 latest"
+// @Param        cursor     query     string  false  "上一页返回的 next_cursor"
+// @Param        limit      query     int     false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=CommentPageResponse}
+// @Router       /api/comments [get]
+func (h *CommentHandler) list(c *gin.Context) {
+	targetID, err := strconv.ParseInt(c.Query("target_id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid target_id")
+		return
+	}
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	page, err := h.CommentService.List(c.Request.Context(), userID, c.Query("type"), targetID,
+		c.DefaultQuery("sort", repo.SortLatest), c.Query("cursor"), limit)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	utils.Success(c, toCommentPageResponse(page))
+}
+
+// post 发布评论
+// @Summary      发布评论
+// @Description  reply_to_id 为 0 时发布顶层评论，否则回复对应评论，回复统一归入顶层评论的楼中楼
+// @Tags         评论
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int             true  "用户ID"
+// @Param        request    body      CommentRequest  true  "评论"
+// @Success      200        {object}  utils.Response{data=CommentResponse}
+// @Router       /api/comments [post]
+func (h *CommentHandler) post(c *gin.Context) {
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	comment, err := h.CommentService.Post(c.Request.Context(), userID, req.Type, req.TargetID, req.Content, req.ReplyToID)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	utils.Success(c, toCommentResponse(&service.CommentView{Comment: comment}))
+}
+
+// replies 楼中楼回复
+// @Summary      评论回复列表
+// @Description  顶层评论下的回复，按发布时间正序，游标分页
+// @Tags         评论
+// @Produce      json
+// @Param        X-User-ID  header    int     false  "用户ID，传入时标记已点赞的评论"
+// @Param        id         path      int     true   "顶层评论ID"
+// @Param        cursor     query     string  false  "上一页返回的 next_cursor"
+// @Param        limit      query     int     false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=CommentPageResponse}
+// @Router       /api/comments/{id}/replies [get]
+func (h *CommentHandler) replies(c *gin.Context) {
+	id, ok := commentID(c)
+	if !ok {
+		return
+	}
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	page, err := h.CommentService.Replies(c.Request.Context(), userID, id, c.Query("cursor"), limit)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	utils.Success(c, toCommentPageResponse(page))
+}
+
+// delete 删除评论
+// @Summary      删除评论
+// @Description  作者删除自己的评论
+// @Tags         评论
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Param        id         path      int  true  "评论ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/comments/{id} [delete]
+func (h *CommentHandler) delete(c *gin.Context) {
+	id, ok := commentID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.CommentService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// like 点赞评论
+// @Summary      点赞评论
+// @Tags         评论
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Param        id         path      int  true  "评论ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/comments/{id}/like [post]
+func (h *CommentHandler) like(c *gin.Context) {
+	id, ok := commentID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.CommentService.Like(c.Request.Context(), userID, id); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// unlike 取消点赞
+// @Summary      取消点赞评论
+// @Tags         评论
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Param        id         path      int  true  "评论ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/comments/{id}/like [delete]
+func (h *CommentHandler) unlike(c *gin.Context) {
+	id, ok := commentID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	if err := h.CommentService.Unlike(c.Request.Context(), userID, id); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+func commentID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid comment id")
+		return 0, false
+	}
+	return id, true
+}
+
+func parseLimit(c *gin.Context) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(utils.DefaultPageSize)))
+	if err != nil || limit <= 0 || limit > utils.MaxPageSize {
+		utils.Error(c, http.StatusBadRequest, "invalid limit")
+		return 0, false
+	}
+	return limit, true
+}
+
+func respondCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidComment), errors.Is(err, service.ErrInvalidTarget),
+		errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrInvalidSort):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrCommentForbidden):
+		utils.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrCommentNotFound), errors.Is(err, service.ErrTargetNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
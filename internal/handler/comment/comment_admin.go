@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"wyy/internal/middleware"
+	service "wyy/internal/service/comment"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommentAdminHandler 评论审核管理后台接口
+type CommentAdminHandler struct {
+	CommentService *service.CommentService
+	adminToken     string
+}
+
+func NewCommentAdminHandler(commentService *service.CommentService, adminToken string) *CommentAdminHandler {
+	return &CommentAdminHandler{CommentService: commentService, adminToken: adminToken}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *CommentAdminHandler) RegisterRoutes(r gin.IRouter) {
+	comments := r.Group("/admin/comments", middleware.Admin(h.adminToken))
+	{
+		comments.DELETE("/:id", h.remove)
+		comments.POST("/:id/restore", h.restore)
+	}
+}
+
+// remove 屏蔽评论
+// @Summary      屏蔽评论
+// @Description  审核屏蔽评论，屏蔽后不再展示且不计入评论数，可恢复
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      int     true  "评论ID"
+// @Success      200            {object}  utils.Response
+// @Router       /api/admin/comments/{id} [delete]
+func (h *CommentAdminHandler) remove(c *gin.Context) {
+	h.moderate(c, true)
+}
+
+// restore 恢复评论
+// @Summary      恢复评论
+// @Description  恢复被屏蔽的评论，作者自己删除的评论不能恢复
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      int     true  "评论ID"
+// @Success      200            {object}  utils.Response
+// @Router       /api/admin/comments/{id}/restore [post]
+func (h *CommentAdminHandler) restore(c *gin.Context) {
+	h.moderate(c, false)
+}
+
+func (h *CommentAdminHandler) moderate(c *gin.Context, removed bool) {
+	id, ok := commentID(c)
+	if !ok {
+		return
+	}
+	if err := h.CommentService.Moderate(c.Request.Context(), id, removed); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
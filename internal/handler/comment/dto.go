@@ -0,0 +1,55 @@
+package handler
+
+import service "wyy/internal/service/comment"
+
+// CommentRequest 发布评论请求体
+type CommentRequest struct {
+	Type      string `json:"type" binding:"required"` // song/playlist/album
+	TargetID  int64  `json:"target_id" binding:"required"`
+	Content   string `json:"content" binding:"required"`
+	ReplyToID int64  `json:"reply_to_id"` // 回复的评论，为 0 时发布顶层评论
+}
+
+// CommentResponse 评论
+type CommentResponse struct {
+	ID            int64  `json:"id"`
+	UserID        int64  `json:"user_id"`
+	ParentID      int64  `json:"parent_id"`
+	ReplyToID     int64  `json:"reply_to_id"`
+	ReplyToUserID int64  `json:"reply_to_user_id"`
+	Content       string `json:"content"`
+	LikeCount     int64  `json:"like_count"`
+	ReplyCount    int    `json:"reply_count"`
+	Liked         bool   `json:"liked"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+// CommentPageResponse 一页评论
+type CommentPageResponse struct {
+	Total      int64             `json:"total"` // 目标评论总数（含回复），回复列表中为 0
+	List       []CommentResponse `json:"list"`
+	NextCursor string            `json:"next_cursor"` // 为空表示没有更多
+}
+
+func toCommentResponse(c *service.CommentView) CommentResponse {
+	return CommentResponse{
+		ID:            c.ID,
+		UserID:        c.UserID,
+		ParentID:      c.ParentID,
+		ReplyToID:     c.ReplyToID,
+		ReplyToUserID: c.ReplyToUserID,
+		Content:       c.Content,
+		LikeCount:     c.LikeCount,
+		ReplyCount:    c.ReplyCount,
+		Liked:         c.Liked,
+		CreatedAt:     c.CreatedAt,
+	}
+}
+
+func toCommentPageResponse(page *service.CommentPage) CommentPageResponse {
+	list := make([]CommentResponse, 0, len(page.Comments))
+	for _, c := range page.Comments {
+		list = append(list, toCommentResponse(c))
+	}
+	return CommentPageResponse{Total: page.Total, List: list, NextCursor: page.NextCursor}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/discover"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BannerHandler Banner 管理后台接口
+type BannerHandler struct {
+	BannerService *service.BannerService
+	Images        *media.ImageLinker
+	adminToken    string
+}
+
+func NewBannerHandler(bannerService *service.BannerService, images *media.ImageLinker, adminToken string) *BannerHandler {
+	return &BannerHandler{BannerService: bannerService, Images: images, adminToken: adminToken}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *BannerHandler) RegisterRoutes(r gin.IRouter) {
+	banners := r.Group("/admin/banners", middleware.Admin(h.adminToken))
+	{
+		banners.GET("", h.list)
+		banners.GET("/:id", h.get)
+		banners.POST("", h.create)
+		banners.PUT("/:id", h.update)
+		banners.DELETE("/:id", h.delete)
+	}
+}
+
+// list Banner 列表
+// @Summary      Banner 列表
+// @Description  管理后台获取全部 Banner，包括未开始和已结束的
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Success      200            {object}  utils.Response{data=[]BannerDetailResponse}
+// @Router       /api/admin/banners [get]
+func (h *BannerHandler) list(c *gin.Context) {
+	banners, err := h.BannerService.List(c.Request.Context())
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := make([]BannerDetailResponse, 0, len(banners))
+	for _, banner := range banners {
+		resp = append(resp, toBannerDetailResponse(h.Images, banner))
+	}
+	utils.Success(c, resp)
+}
+
+// get Banner 详情
+// @Summary      Banner 详情
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      int     true  "Banner ID"
+// @Success      200            {object}  utils.Response{data=BannerDetailResponse}
+// @Router       /api/admin/banners/{id} [get]
+func (h *BannerHandler) get(c *gin.Context) {
+	id, ok := bannerID(c)
+	if !ok {
+		return
+	}
+	banner, err := h.BannerService.Get(c.Request.Context(), id)
+	if err != nil {
+		respondBannerError(c, err)
+		return
+	}
+	utils.Success(c, toBannerDetailResponse(h.Images, banner))
+}
+
+// create 创建 Banner
+// @Summary      创建 Banner
+// @Tags         管理后台
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-Token  header    string         true  "管理令牌"
+// @Param        request        body      BannerRequest  true  "Banner"
+// @Success      200            {object}  utils.Response{data=BannerDetailResponse}
+// @Router       /api/admin/banners [post]
+func (h *BannerHandler) create(c *gin.Context) {
+	var req BannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	banner := req.toBanner(0)
+	if err := h.BannerService.Create(c.Request.Context(), banner); err != nil {
+		respondBannerError(c, err)
+		return
+	}
+	utils.Success(c, toBannerDetailResponse(h.Images, banner))
+}
+
+// update 更新 Banner
+// @Summary      更新 Banner
+// @Tags         管理后台
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-Token  header    string         true  "管理令牌"
+// @Param        id             path      int            true  "Banner ID"
+// @Param        request        body      BannerRequest  true  "Banner"
+// @Success      200            {object}  utils.Response{data=BannerDetailResponse}
+// @Router       /api/admin/banners/{id} [put]
+func (h *BannerHandler) update(c *gin.Context) {
+	id, ok := bannerID(c)
+	if !ok {
+		return
+	}
+	var req BannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	banner := req.toBanner(id)
+	if err := h.BannerService.Update(c.Request.Context(), banner); err != nil {
+		respondBannerError(c, err)
+		return
+	}
+	utils.Success(c, toBannerDetailResponse(h.Images, banner))
+}
+
+// delete 删除 Banner
+// @Summary      删除 Banner
+// @Tags         管理后台
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        id             path      int     true  "Banner ID"
+// @Success      200            {object}  utils.Response
+// @Router       /api/admin/banners/{id} [delete]
+func (h *BannerHandler) delete(c *gin.Context) {
+	id, ok := bannerID(c)
+	if !ok {
+		return
+	}
+	if err := h.BannerService.Delete(c.Request.Context(), id); err != nil {
+		respondBannerError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+func bannerID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid banner id")
+		return 0, false
+	}
+	return id, true
+}
+
+func respondBannerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidBanner):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrBannerNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,328 @@
+package handler
+
+import (
+	repo "wyy/internal/repo/discover"
+	service "wyy/internal/service/discover"
+	media "wyy/internal/service/media"
+)
+
+// FeedbackRequest “不感兴趣”请求体
+type FeedbackRequest struct {
+	Type     string `json:"type" binding:"required,oneof=song artist tag"` // 反馈目标类型
+	TargetID string `json:"target_id" binding:"required"`                  // 歌曲 ID、歌手 ID 或标签
+}
+
+// FeedbackResponse 负反馈记录
+type FeedbackResponse struct {
+	Type      string `json:"type"`
+	TargetID  string `json:"target_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// SongResponse 推荐歌曲
+type SongResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Artist      string   `json:"artist"`
+	ArtistIDs   []string `json:"artist_ids"`
+	Album       string   `json:"album"`
+	CoverURL    string   `json:"cover_url"`
+	Tags        []string `json:"tags"`
+	Language    string   `json:"language"`
+	Duration    int      `json:"duration"`
+	PublishTime int64    `json:"publish_time"`
+}
+
+// ReasonResponse 推荐理由
+type ReasonResponse struct {
+	Type    string `json:"type"`
+	RefType string `json:"ref_type,omitempty"`
+	RefID   string `json:"ref_id,omitempty"`
+	Text    string `json:"text"`
+}
+
+// RecommendedSongResponse 推荐歌曲及推荐理由
+type RecommendedSongResponse struct {
+	SongResponse
+	Reason *ReasonResponse `json:"reason,omitempty"`
+}
+
+// RecommendedPlaylistResponse 推荐歌单及推荐理由
+type RecommendedPlaylistResponse struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	CreatorID      string          `json:"creator_id"`
+	CoverURL       string          `json:"cover_url"`
+	Tags           []string        `json:"tags"`
+	SongCount      int             `json:"song_count"`
+	PlayCount      int64           `json:"play_count"`
+	SubscribeCount int64           `json:"subscribe_count"`
+	Reason         *ReasonResponse `json:"reason,omitempty"`
+}
+
+// DailyResponse 每日推荐
+type DailyResponse struct {
+	Songs []RecommendedSongResponse `json:"songs"`
+}
+
+// FMResponse 私人 FM 下一批歌曲
+type FMResponse struct {
+	Songs []RecommendedSongResponse `json:"songs"`
+}
+
+// FMFeedbackRequest 私人 FM 反馈请求体
+type FMFeedbackRequest struct {
+	SongID string `json:"song_id" binding:"required"`
+	Action string `json:"action" binding:"required,oneof=like skip trash"`
+}
+
+// ToplistResponse 榜单
+type ToplistResponse struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Period      string                `json:"period"` // 期数 2006-01-02，尚未生成时为空
+	UpdatedAt   int64                 `json:"updated_at"`
+	Songs       []ToplistSongResponse `json:"songs"` // 榜单列表中只包含前几名
+}
+
+// ToplistSongResponse 榜单歌曲及排名变化
+type ToplistSongResponse struct {
+	SongResponse
+	Rank       int  `json:"rank"`
+	LastRank   int  `json:"last_rank"`   // 上一期排名，0 表示上一期未上榜
+	RankChange int  `json:"rank_change"` // 正数为上升，新上榜时为 0
+	IsNew      bool `json:"is_new"`
+}
+
+// AlbumResponse 新碟
+type AlbumResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ArtistID    string `json:"artist_id"`
+	ArtistName  string `json:"artist_name"`
+	CoverURL    string `json:"cover_url"`
+	Language    string `json:"language"`
+	ReleaseTime int64  `json:"release_time"`
+	SongCount   int    `json:"song_count"`
+}
+
+// ReleaseWeekResponse 一周内发布的新歌和新碟
+type ReleaseWeekResponse struct {
+	Week   string          `json:"week"` // 该周周一的日期
+	Songs  []SongResponse  `json:"songs"`
+	Albums []AlbumResponse `json:"albums"`
+}
+
+// ReleasePageResponse 按周分组的新歌、新碟
+type ReleasePageResponse struct {
+	Weeks      []ReleaseWeekResponse `json:"weeks"`
+	NextBefore int64                 `json:"next_before"` // 作为 before 参数加载更早的作品
+}
+
+// BannerRequest 创建/更新 Banner 请求体
+type BannerRequest struct {
+	Title        string   `json:"title"`
+	ImageURL     string   `json:"image_url" binding:"required"`
+	TargetType   string   `json:"target_type" binding:"required,oneof=song album playlist url"`
+	TargetID     string   `json:"target_id" binding:"required"` // 目标为 url 时为链接
+	DisplayOrder int      `json:"display_order"`
+	StartAt      int64    `json:"start_at"` // 0 表示立即展示
+	EndAt        int64    `json:"end_at"`   // 0 表示不限
+	Platforms    []string `json:"platforms"`
+}
+
+// BannerResponse Banner（前台）
+type BannerResponse struct {
+	ID         int64  `json:"id"`
+	Title      string `json:"title"`
+	ImageURL   string `json:"image_url"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+}
+
+// BannerDetailResponse Banner（管理后台）
+type BannerDetailResponse struct {
+	BannerResponse
+	DisplayOrder int      `json:"display_order"`
+	StartAt      int64    `json:"start_at"`
+	EndAt        int64    `json:"end_at"`
+	Platforms    []string `json:"platforms"`
+	CreatedAt    int64    `json:"created_at"`
+	UpdatedAt    int64    `json:"updated_at"`
+}
+
+func (r *BannerRequest) toBanner(id int64) *repo.Banner {
+	return &repo.Banner{
+		ID:           id,
+		Title:        r.Title,
+		ImageURL:     media.StoredImageURL(r.ImageURL),
+		TargetType:   r.TargetType,
+		TargetID:     r.TargetID,
+		DisplayOrder: r.DisplayOrder,
+		StartAt:      r.StartAt,
+		EndAt:        r.EndAt,
+		Platforms:    r.Platforms,
+	}
+}
+
+func toBannerResponse(images *media.ImageLinker, banner *repo.Banner) BannerResponse {
+	return BannerResponse{
+		ID:         banner.ID,
+		Title:      banner.Title,
+		ImageURL:   images.Link(banner.ImageURL),
+		TargetType: banner.TargetType,
+		TargetID:   banner.TargetID,
+	}
+}
+
+func toBannerDetailResponse(images *media.ImageLinker, banner *repo.Banner) BannerDetailResponse {
+	return BannerDetailResponse{
+		BannerResponse: toBannerResponse(images, banner),
+		DisplayOrder:   banner.DisplayOrder,
+		StartAt:        banner.StartAt,
+		EndAt:          banner.EndAt,
+		Platforms:      banner.Platforms,
+		CreatedAt:      banner.CreatedAt,
+		UpdatedAt:      banner.UpdatedAt,
+	}
+}
+
+func toSongResponse(images *media.ImageLinker, song *repo.Song) SongResponse {
+	return SongResponse{
+		ID:          song.ID,
+		Name:        song.Name,
+		Artist:      song.Artist,
+		ArtistIDs:   song.ArtistIDs,
+		Album:       song.Album,
+		CoverURL:    images.Link(song.CoverURL),
+		Tags:        song.Tags,
+		Language:    song.Language,
+		Duration:    song.Duration,
+		PublishTime: song.PublishTime,
+	}
+}
+
+func toRecommendedResponses(images *media.ImageLinker, songs []*service.RecommendedSong, lang string) []RecommendedSongResponse {
+	resp := make([]RecommendedSongResponse, 0, len(songs))
+	for _, song := range songs {
+		resp = append(resp, RecommendedSongResponse{
+			SongResponse: toSongResponse(images, song.Song),
+			Reason:       localizeReason(song.Reason, lang),
+		})
+	}
+	return resp
+}
+
+// PlayRequest 播放上报请求体
+type PlayRequest struct {
+	SongID   string `json:"song_id" binding:"required"`
+	Duration int    `json:"duration"` // 实际播放秒数
+}
+
+// HistoryItemResponse 一条播放历史
+type HistoryItemResponse struct {
+	ID       int64        `json:"id"`
+	Song     SongResponse `json:"song"`
+	PlayedAt int64        `json:"played_at"`
+	Plays    int          `json:"plays"` // 连续重复播放次数
+}
+
+// HistoryDayResponse 同一天的播放历史
+type HistoryDayResponse struct {
+	Date  string                `json:"date"`
+	Items []HistoryItemResponse `json:"items"`
+}
+
+// HistoryResponse 一页播放历史
+type HistoryResponse struct {
+	Days       []HistoryDayResponse `json:"days"`
+	NextCursor string               `json:"next_cursor"` // 为空表示没有更多
+}
+
+// RankedSongResponse 听歌排行
+type RankedSongResponse struct {
+	SongResponse
+	Plays int `json:"plays"`
+}
+
+func toHistoryResponse(images *media.ImageLinker, page *service.HistoryPage) HistoryResponse {
+	days := make([]HistoryDayResponse, 0, len(page.Days))
+	for _, day := range page.Days {
+		items := make([]HistoryItemResponse, 0, len(day.Items))
+		for _, item := range day.Items {
+			items = append(items, HistoryItemResponse{
+				ID:       item.ID,
+				Song:     toSongResponse(images, item.Song),
+				PlayedAt: item.PlayedAt,
+				Plays:    item.Plays,
+			})
+		}
+		days = append(days, HistoryDayResponse{Date: day.Date, Items: items})
+	}
+	return HistoryResponse{Days: days, NextCursor: page.NextCursor}
+}
+
+func toToplistResponse(images *media.ImageLinker, toplist *service.Toplist) ToplistResponse {
+	songs := make([]ToplistSongResponse, 0, len(toplist.Songs))
+	for _, song := range toplist.Songs {
+		resp := ToplistSongResponse{
+			SongResponse: toSongResponse(images, song.Song),
+			Rank:         song.Rank,
+			LastRank:     song.LastRank,
+			IsNew:        song.LastRank == 0,
+		}
+		if song.LastRank > 0 {
+			resp.RankChange = song.LastRank - song.Rank
+		}
+		songs = append(songs, resp)
+	}
+	return ToplistResponse{
+		ID:          toplist.ID,
+		Name:        toplist.Name,
+		Description: toplist.Description,
+		Period:      toplist.Period,
+		UpdatedAt:   toplist.UpdatedAt,
+		Songs:       songs,
+	}
+}
+
+func toReleasePageResponse(images *media.ImageLinker, page *service.ReleasePage) ReleasePageResponse {
+	weeks := make([]ReleaseWeekResponse, 0, len(page.Weeks))
+	for _, week := range page.Weeks {
+		songs := make([]SongResponse, 0, len(week.Songs))
+		for _, song := range week.Songs {
+			songs = append(songs, toSongResponse(images, song))
+		}
+		albums := make([]AlbumResponse, 0, len(week.Albums))
+		for _, album := range week.Albums {
+			albums = append(albums, AlbumResponse{
+				ID:          album.ID,
+				Name:        album.Name,
+				ArtistID:    album.ArtistID,
+				ArtistName:  album.ArtistName,
+				CoverURL:    images.Link(album.CoverURL),
+				Language:    album.Language,
+				ReleaseTime: album.ReleaseTime,
+				SongCount:   album.SongCount,
+			})
+		}
+		weeks = append(weeks, ReleaseWeekResponse{Week: week.Week, Songs: songs, Albums: albums})
+	}
+	return ReleasePageResponse{Weeks: weeks, NextBefore: page.NextBefore}
+}
+
+func toRecommendedPlaylistResponse(images *media.ImageLinker, item *service.RecommendedPlaylist, lang string) RecommendedPlaylistResponse {
+	playlist := item.Playlist
+	return RecommendedPlaylistResponse{
+		ID:             playlist.ID,
+		Name:           playlist.Name,
+		CreatorID:      playlist.CreatorID,
+		CoverURL:       images.Link(playlist.CoverURL),
+		Tags:           playlist.Tags,
+		SongCount:      playlist.SongCount,
+		PlayCount:      playlist.PlayCount,
+		SubscribeCount: playlist.SubscribeCount,
+		Reason:         localizeReason(item.Reason, lang),
+	}
+}
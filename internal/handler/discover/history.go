@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/discover"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryHandler 最近播放与听歌排行
+type HistoryHandler struct {
+	HistoryService *service.HistoryService
+	Images         *media.ImageLinker
+}
+
+func NewHistoryHandler(historyService *service.HistoryService, images *media.ImageLinker) *HistoryHandler {
+	return &HistoryHandler{HistoryService: historyService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *HistoryHandler) RegisterRoutes(r gin.IRouter) {
+	history := r.Group("/users/me/history", middleware.Auth())
+	{
+		history.GET("", h.list)
+		history.POST("", h.record)
+		history.DELETE("", h.clear)
+		history.DELETE("/:id", h.delete)
+		history.GET("/ranking", h.ranking)
+	}
+}
+
+// list 最近播放
+// @Summary      最近播放
+// @Description  按播放时间倒序、按天分组返回播放历史；连续重复播放同一首歌合并为一条
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int     true   "用户ID"
+// @Param        cursor     query     string  false  "上一页返回的 next_cursor，不传从最新开始"
+// @Param        limit      query     int     false  "每页数量，默认 20，最多 100"
+// @Success      200        {object}  utils.Response{data=HistoryResponse}
+// @Router       /api/users/me/history [get]
+func (h *HistoryHandler) list(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(utils.DefaultPageSize)))
+	if err != nil || limit <= 0 || limit > utils.MaxPageSize {
+		utils.Error(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	page, err := h.HistoryService.History(c.Request.Context(), currentUserID(c), c.Query("cursor"), limit)
+	if err != nil {
+		respondHistoryError(c, err)
+		return
+	}
+	utils.Success(c, toHistoryResponse(h.Images, page))
+}
+
+// record 播放上报
+// @Summary      播放上报
+// @Description  客户端播放一首歌后上报，写入最近播放并计入播放量和推荐行为
+// @Tags         我的音乐
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int          true  "用户ID"
+// @Param        request    body      PlayRequest  true  "播放记录"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/history [post]
+func (h *HistoryHandler) record(c *gin.Context) {
+	var req PlayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.HistoryService.Record(c.Request.Context(), currentUserID(c), req.SongID, req.Duration); err != nil {
+		respondHistoryError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// delete 删除一条播放历史
+// @Summary      删除播放历史
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Param        id         path      int  true  "播放历史ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/history/{id} [delete]
+func (h *HistoryHandler) delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "invalid history id")
+		return
+	}
+	if err := h.HistoryService.Delete(c.Request.Context(), currentUserID(c), id); err != nil {
+		respondHistoryError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// clear 清空播放历史
+// @Summary      清空播放历史
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Success      200        {object}  utils.Response
+// @Router       /api/users/me/history [delete]
+func (h *HistoryHandler) clear(c *gin.Context) {
+	if err := h.HistoryService.Clear(c.Request.Context(), currentUserID(c)); err != nil {
+		respondHistoryError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// ranking 听歌排行
+// @Summary      听歌排行
+// @Description  最近一周或全部时间内播放次数最多的歌曲，最多 100 首
+// @Tags         我的音乐
+// @Produce      json
+// @Param        X-User-ID  header    int     true   "用户ID"
+// @Param        range      query     string  false  "统计范围 week/all，默认 week"
+// @Success      200        {object}  utils.Response{data=[]RankedSongResponse}
+// @Router       /api/users/me/history/ranking [get]
+func (h *HistoryHandler) ranking(c *gin.Context) {
+	ranked, err := h.HistoryService.Ranking(c.Request.Context(), currentUserID(c), c.DefaultQuery("range", service.RankRangeWeek))
+	if err != nil {
+		respondHistoryError(c, err)
+		return
+	}
+	resp := make([]RankedSongResponse, 0, len(ranked))
+	for _, song := range ranked {
+		resp = append(resp, RankedSongResponse{SongResponse: toSongResponse(h.Images, song.Song), Plays: song.Plays})
+	}
+	utils.Success(c, resp)
+}
+
+func respondHistoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrInvalidRankRange),
+		errors.Is(err, service.ErrInvalidPlayLength):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrHistorySong), errors.Is(err, service.ErrHistoryNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	repo "wyy/internal/repo/discover"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 推荐理由文案，%s 为引用实体名称
+var reasonTexts = map[string]map[string]string{
+	"zh": {
+		repo.ReasonSimilarSong:  "与你喜欢的《%s》相似",
+		repo.ReasonSimilarUsers: "和你口味相似的人也在听",
+		repo.ReasonArtistNew:    "你喜欢的歌手 %s 的新歌",
+		repo.ReasonTag:          "根据你喜欢的%s风格推荐",
+		repo.ReasonHot:          "大家都在听的热门歌曲",
+		repo.ReasonLikedSongs:   "收录了你喜欢的《%s》",
+		repo.ReasonHotPlaylist:  "大家都在听的热门歌单",
+	},
+	"en": {
+		repo.ReasonSimilarSong:  "Similar to \"%s\", which you liked",
+		repo.ReasonSimilarUsers: "Popular among listeners like you",
+		repo.ReasonArtistNew:    "New from %s, an artist you like",
+		repo.ReasonTag:          "Because you like %s",
+		repo.ReasonHot:          "Trending now",
+		repo.ReasonLikedSongs:   "Includes \"%s\", which you liked",
+		repo.ReasonHotPlaylist:  "Popular playlist",
+	},
+}
+
+const defaultLang = "zh"
+
+// requestLang 根据 Accept-Language 选择文案语言，默认中文
+func requestLang(c *gin.Context) string {
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		lang := strings.SplitN(tag, "-", 2)[0]
+		if _, ok := reasonTexts[lang]; ok {
+			return lang
+		}
+	}
+	return defaultLang
+}
+
+// localizeReason 将结构化推荐理由转为对应语言的文案
+func localizeReason(reason *repo.Reason, lang string) *ReasonResponse {
+	if reason == nil {
+		return nil
+	}
+	text := reasonTexts[lang][reason.Type]
+	if strings.Contains(text, "%s") {
+		text = fmt.Sprintf(text, reason.RefName)
+	}
+	return &ReasonResponse{
+		Type:    reason.Type,
+		RefType: reason.RefType,
+		RefID:   reason.RefID,
+		Text:    text,
+	}
+}
@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
 	service "wyy/internal/service/discover"
+	media "wyy/internal/service/media"
 	"wyy/utils"
 
 	"github.com/gin-gonic/gin"
@@ -9,10 +14,24 @@ import (
 
 type RecommendHandler struct {
 	RecommendService *service.RecommendService
+	FeedbackService  *service.FeedbackService
+	DailyService     *service.DailyService
+	FMService        *service.FMService
+	BannerService    *service.BannerService
+	PlaylistService  *service.PlaylistRecommendService
+	Images           *media.ImageLinker
 }
 
-func NewRecommendHandler(recommendService *service.RecommendService) *RecommendHandler {
-	return &RecommendHandler{RecommendService: recommendService}
+func NewRecommendHandler(recommendService *service.RecommendService, feedbackService *service.FeedbackService, dailyService *service.DailyService, fmService *service.FMService, bannerService *service.BannerService, playlistService *service.PlaylistRecommendService, images *media.ImageLinker) *RecommendHandler {
+	return &RecommendHandler{
+		RecommendService: recommendService,
+		FeedbackService:  feedbackService,
+		DailyService:     dailyService,
+		FMService:        fmService,
+		BannerService:    bannerService,
+		PlaylistService:  playlistService,
+		Images:           images,
+	}
 }
 
 // RegisterRoutes 实现 route.Registrar 接口
@@ -20,25 +39,222 @@ func (h *RecommendHandler) RegisterRoutes(r gin.IRouter) {
 	recommends := r.Group("/recommends")
 	{
 		recommends.GET("/banners", h.getReBanners)
+		recommends.GET("/daily", middleware.Auth(), h.getDaily)
+		recommends.GET("/fm", middleware.Auth(), h.getFM)
+		recommends.POST("/fm/feedback", middleware.Auth(), h.fmFeedback)
+		recommends.GET("/playlists", middleware.OptionalAuth(), h.getPlaylists)
+
+		feedback := recommends.Group("/feedback", middleware.Auth())
+		feedback.GET("", h.listFeedback)
+		feedback.POST("", h.submitFeedback)
+		feedback.DELETE("/:type/:target_id", h.undoFeedback)
 	}
 }
 
-// getReBanners 返回 Banner 假数据
+// getReBanners 返回当前展示中的 Banner
 // @Summary      获取推荐 Banner
-// @Description  返回 Banner 图片 URL 列表（当前为假数据）
+// @Description  返回当前处于展示期的 Banner 列表，按展示顺序排列
 // @Tags         推荐模块
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "成功返回 banners 列表"
+// @Param        platform  query     string  false  "平台 web/ios/android，不传时返回全部"
+// @Success      200       {object}  utils.Response{data=[]BannerResponse}
 // @Router       /api/recommends/banners [get]
-// @Example      { "banners": ["https://p5.music.126.net/obj/wonDlsKUwrLClGjCm8Kx/78326360541/d092/1425/a80f/5ee0ef793063a2f70d3001d5cacc517c.jpg"] }
 func (h *RecommendHandler) getReBanners(c *gin.Context) {
-	banners := []string{
-		"https://p5.music.126.net/obj/wonDlsKUwrLClGjCm8Kx/78326360541/d092/1425/a80f/5ee0ef793063a2f70d3001d5cacc517c.jpg",
-		"https://p5.music.126.net/obj/wonDlsKUwrLClGjCm8Kx/78325617293/4133/91d2/02de/f484f8c5d5b9925e056dd6dd7a746f03.jpg",
-		"https://p5.music.126.net/obj/wonDlsKUwrLClGjCm8Kx/78519438579/d965/e534/e59e/d36fec79dd734ad54f4e3132957436d9.jpg",
-		"https://p5.music.126.net/obj/wonDlsKUwrLClGjCm8Kx/78519299065/508f/4cb2/54df/e5e53d3323562aad27ede4a8134070b0.jpg",
-		"https://p5.music.126.net/obj/wonDlsKUwrLClGjCm8Kx/78366956157/9df4/26fd/9489/9d78de15c7ee8865fc251141de6ed216.png",
+	banners, err := h.BannerService.ListActive(c.Request.Context(), c.Query("platform"))
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := make([]BannerResponse, 0, len(banners))
+	for _, banner := range banners {
+		resp = append(resp, toBannerResponse(h.Images, banner))
 	}
 	//包装的返回值对象
-	utils.Success(c, banners)
+	utils.Success(c, resp)
+}
+
+// getDaily 每日推荐
+// @Summary      每日推荐
+// @Description  返回用户当天固定的每日推荐歌曲及推荐理由，每天定时刷新
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID        header    int     true   "用户ID"
+// @Param        Accept-Language  header    string  false  "推荐理由语言 zh/en"
+// @Success      200              {object}  utils.Response{data=DailyResponse}
+// @Router       /api/recommends/daily [get]
+func (h *RecommendHandler) getDaily(c *gin.Context) {
+	songs, err := h.DailyService.GetDaily(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, DailyResponse{Songs: toRecommendedResponses(h.Images, songs, requestLang(c))})
+}
+
+// getFM 私人 FM
+// @Summary      私人FM
+// @Description  返回私人 FM 会话中接下来的几首歌
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID  header    int  true   "用户ID"
+// @Param        count      query     int  false  "返回数量，默认 3，最多 10"
+// @Success      200        {object}  utils.Response{data=FMResponse}
+// @Router       /api/recommends/fm [get]
+func (h *RecommendHandler) getFM(c *gin.Context) {
+	count, err := strconv.Atoi(c.DefaultQuery("count", "3"))
+	if err != nil || count <= 0 || count > 10 {
+		utils.Error(c, http.StatusBadRequest, "invalid count")
+		return
+	}
+	songs, err := h.FMService.Next(c.Request.Context(), currentUserID(c), count)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Success(c, FMResponse{Songs: toRecommendedResponses(h.Images, songs, requestLang(c))})
+}
+
+// getPlaylists 推荐歌单
+// @Summary      推荐歌单
+// @Description  根据偏好标签和喜欢的歌曲推荐公开歌单，不包括自己创建和已收藏的歌单；未登录或个性化结果不足时返回热门歌单
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID        header    int     false  "用户ID"
+// @Param        Accept-Language  header    string  false  "推荐理由语言 zh/en"
+// @Param        limit            query     int     false  "返回数量，默认 6，最多 30"
+// @Success      200              {object}  utils.Response{data=[]RecommendedPlaylistResponse}
+// @Router       /api/recommends/playlists [get]
+func (h *RecommendHandler) getPlaylists(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "6"))
+	if err != nil || limit <= 0 || limit > 30 {
+		utils.Error(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	userID := ""
+	if _, ok := middleware.GetUserID(c); ok {
+		userID = currentUserID(c)
+	}
+	playlists, err := h.PlaylistService.GetRecommendations(c.Request.Context(), userID, limit)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lang := requestLang(c)
+	resp := make([]RecommendedPlaylistResponse, 0, len(playlists))
+	for _, playlist := range playlists {
+		resp = append(resp, toRecommendedPlaylistResponse(h.Images, playlist, lang))
+	}
+	utils.Success(c, resp)
+}
+
+// fmFeedback 私人 FM 会话内反馈
+// @Summary      私人FM反馈
+// @Description  喜欢、跳过或不再播放当前歌曲，下一次获取时立即生效
+// @Tags         推荐模块
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int                true  "用户ID"
+// @Param        request    body      FMFeedbackRequest  true  "反馈"
+// @Success      200        {object}  utils.Response
+// @Router       /api/recommends/fm/feedback [post]
+func (h *RecommendHandler) fmFeedback(c *gin.Context) {
+	var req FMFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := h.FMService.Feedback(c.Request.Context(), currentUserID(c), req.SongID, req.Action)
+	switch {
+	case errors.Is(err, service.ErrInvalidFMAction):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrFeedbackTarget):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	case err != nil:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	default:
+		utils.Success(c, nil)
+	}
+}
+
+// submitFeedback 提交“不感兴趣”
+// @Summary      不感兴趣
+// @Description  对歌曲、歌手或标签标记不感兴趣，之后的推荐中立即生效
+// @Tags         推荐模块
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    int              true  "用户ID"
+// @Param        request    body      FeedbackRequest  true  "反馈目标"
+// @Success      200        {object}  utils.Response
+// @Router       /api/recommends/feedback [post]
+func (h *RecommendHandler) submitFeedback(c *gin.Context) {
+	var req FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := h.FeedbackService.Submit(c.Request.Context(), currentUserID(c), req.Type, req.TargetID)
+	if err != nil {
+		respondFeedbackError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// undoFeedback 撤销“不感兴趣”
+// @Summary      撤销不感兴趣
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID  header    int     true  "用户ID"
+// @Param        type       path      string  true  "反馈类型 song/artist/tag"
+// @Param        target_id  path      string  true  "反馈目标"
+// @Success      200        {object}  utils.Response
+// @Router       /api/recommends/feedback/{type}/{target_id} [delete]
+func (h *RecommendHandler) undoFeedback(c *gin.Context) {
+	err := h.FeedbackService.Undo(c.Request.Context(), currentUserID(c), c.Param("type"), c.Param("target_id"))
+	if err != nil {
+		respondFeedbackError(c, err)
+		return
+	}
+	utils.Success(c, nil)
+}
+
+// listFeedback 获取“不感兴趣”列表
+// @Summary      不感兴趣列表
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID  header    int  true  "用户ID"
+// @Success      200        {object}  utils.Response{data=[]FeedbackResponse}
+// @Router       /api/recommends/feedback [get]
+func (h *RecommendHandler) listFeedback(c *gin.Context) {
+	feedback, err := h.FeedbackService.List(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := make([]FeedbackResponse, 0, len(feedback))
+	for _, fb := range feedback {
+		resp = append(resp, FeedbackResponse{
+			Type:      fb.TargetType,
+			TargetID:  fb.TargetID,
+			CreatedAt: fb.CreatedAt,
+		})
+	}
+	utils.Success(c, resp)
+}
+
+func respondFeedbackError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidFeedbackType):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrFeedbackTarget), errors.Is(err, service.ErrFeedbackNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// currentUserID 推荐模块内部使用字符串形式的用户 ID
+func currentUserID(c *gin.Context) string {
+	userID, _ := middleware.GetUserID(c)
+	return strconv.FormatInt(userID, 10)
 }
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/discover"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReleaseHandler 新歌速递与新碟上架
+type ReleaseHandler struct {
+	ReleaseService *service.ReleaseService
+	Images         *media.ImageLinker
+}
+
+func NewReleaseHandler(releaseService *service.ReleaseService, images *media.ImageLinker) *ReleaseHandler {
+	return &ReleaseHandler{ReleaseService: releaseService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *ReleaseHandler) RegisterRoutes(r gin.IRouter) {
+	releases := r.Group("/releases")
+	{
+		releases.GET("/songs", middleware.OptionalAuth(), h.songs)
+		releases.GET("/albums", middleware.OptionalAuth(), h.albums)
+		releases.GET("/following", middleware.Auth(), h.following)
+	}
+}
+
+// songs 新歌速递
+// @Summary      新歌速递
+// @Description  按发布时间倒序、按周分组返回新歌；登录用户不传 region 时按用户设置的地区过滤版权
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID  header    int     false  "用户ID"
+// @Param        language   query     string  false  "语种 zh/en/ja/ko，不传为全部"
+// @Param        region     query     string  false  "只返回在该地区可播放的歌曲"
+// @Param        before     query     int     false  "上一页返回的 next_before，不传从当前时间开始"
+// @Param        weeks      query     int     false  "返回的周数，默认 2，最多 12"
+// @Success      200        {object}  utils.Response{data=ReleasePageResponse}
+// @Router       /api/releases/songs [get]
+func (h *ReleaseHandler) songs(c *gin.Context) {
+	filter, ok := releaseFilter(c)
+	if !ok {
+		return
+	}
+	page, err := h.ReleaseService.NewSongs(c.Request.Context(), filter)
+	if err != nil {
+		respondReleaseError(c, err)
+		return
+	}
+	utils.Success(c, toReleasePageResponse(h.Images, page))
+}
+
+// albums 新碟上架
+// @Summary      新碟上架
+// @Description  按发行时间倒序、按周分组返回新专辑，只包含有可播放曲目的专辑
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID  header    int     false  "用户ID"
+// @Param        language   query     string  false  "语种 zh/en/ja/ko，不传为全部"
+// @Param        region     query     string  false  "只返回在该地区有可播放曲目的专辑"
+// @Param        before     query     int     false  "上一页返回的 next_before，不传从当前时间开始"
+// @Param        weeks      query     int     false  "返回的周数，默认 2，最多 12"
+// @Success      200        {object}  utils.Response{data=ReleasePageResponse}
+// @Router       /api/releases/albums [get]
+func (h *ReleaseHandler) albums(c *gin.Context) {
+	filter, ok := releaseFilter(c)
+	if !ok {
+		return
+	}
+	page, err := h.ReleaseService.NewAlbums(c.Request.Context(), filter)
+	if err != nil {
+		respondReleaseError(c, err)
+		return
+	}
+	utils.Success(c, toReleasePageResponse(h.Images, page))
+}
+
+// following 关注歌手的新作品
+// @Summary      关注歌手的新作品
+// @Description  返回关注的歌手发布的新歌和新碟，按周分组
+// @Tags         推荐模块
+// @Produce      json
+// @Param        X-User-ID  header    int     true   "用户ID"
+// @Param        language   query     string  false  "语种 zh/en/ja/ko，不传为全部"
+// @Param        region     query     string  false  "只返回在该地区可播放的作品"
+// @Param        before     query     int     false  "上一页返回的 next_before，不传从当前时间开始"
+// @Param        weeks      query     int     false  "返回的周数，默认 2，最多 12"
+// @Success      200        {object}  utils.Response{data=ReleasePageResponse}
+// @Router       /api/releases/following [get]
+func (h *ReleaseHandler) following(c *gin.Context) {
+	filter, ok := releaseFilter(c)
+	if !ok {
+		return
+	}
+	page, err := h.ReleaseService.Following(c.Request.Context(), filter)
+	if err != nil {
+		respondReleaseError(c, err)
+		return
+	}
+	utils.Success(c, toReleasePageResponse(h.Images, page))
+}
+
+func releaseFilter(c *gin.Context) (service.ReleaseFilter, bool) {
+	filter := service.ReleaseFilter{
+		Language: c.Query("language"),
+		Region:   c.Query("region"),
+	}
+	if _, ok := middleware.GetUserID(c); ok {
+		filter.UserID = currentUserID(c)
+	}
+	if before := c.Query("before"); before != "" {
+		v, err := strconv.ParseInt(before, 10, 64)
+		if err != nil || v <= 0 {
+			utils.Error(c, http.StatusBadRequest, "invalid before")
+			return filter, false
+		}
+		filter.Before = v
+	}
+	if weeks := c.Query("weeks"); weeks != "" {
+		v, err := strconv.Atoi(weeks)
+		if err != nil || v <= 0 {
+			utils.Error(c, http.StatusBadRequest, "invalid weeks")
+			return filter, false
+		}
+		filter.Weeks = v
+	}
+	return filter, true
+}
+
+func respondReleaseError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReleaseFilter):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
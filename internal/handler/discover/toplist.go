@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	service "wyy/internal/service/discover"
+	media "wyy/internal/service/media"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ToplistHandler 排行榜
+type ToplistHandler struct {
+	ChartService *service.ChartService
+	Images       *media.ImageLinker
+}
+
+func NewToplistHandler(chartService *service.ChartService, images *media.ImageLinker) *ToplistHandler {
+	return &ToplistHandler{ChartService: chartService, Images: images}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *ToplistHandler) RegisterRoutes(r gin.IRouter) {
+	toplists := r.Group("/toplists")
+	{
+		toplists.GET("", h.list)
+		toplists.GET("/:id", h.get)
+	}
+}
+
+// list 榜单列表
+// @Summary      榜单列表
+// @Description  返回全部榜单的最新一期，每个榜单只包含前 3 名；包括热歌榜、飙升榜、新歌榜、原创榜和曲风榜
+// @Tags         推荐模块
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]ToplistResponse}
+// @Router       /api/toplists [get]
+func (h *ToplistHandler) list(c *gin.Context) {
+	toplists, err := h.ChartService.ListToplists(c.Request.Context())
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := make([]ToplistResponse, 0, len(toplists))
+	for _, toplist := range toplists {
+		resp = append(resp, toToplistResponse(h.Images, toplist))
+	}
+	utils.Success(c, resp)
+}
+
+// get 榜单详情
+// @Summary      榜单详情
+// @Description  返回榜单某一期的完整歌曲列表及与上一期相比的排名变化，不传 period 时返回最新一期
+// @Tags         推荐模块
+// @Produce      json
+// @Param        id      path      string  true   "榜单 ID，如 hot、surge、new、original、tag-摇滚"
+// @Param        period  query     string  false  "期数，格式 2006-01-02"
+// @Success      200     {object}  utils.Response{data=ToplistResponse}
+// @Router       /api/toplists/{id} [get]
+func (h *ToplistHandler) get(c *gin.Context) {
+	toplist, err := h.ChartService.GetToplist(c.Request.Context(), c.Param("id"), c.Query("period"))
+	if err != nil {
+		respondToplistError(c, err)
+		return
+	}
+	utils.Success(c, toToplistResponse(h.Images, toplist))
+}
+
+func respondToplistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPeriod):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrToplistNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
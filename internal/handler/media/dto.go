@@ -0,0 +1,9 @@
+package handler
+
+// ImageResponse 上传后的图片
+type ImageResponse struct {
+	Key         string `json:"key"`
+	URL         string `json:"url"` // 可直接用作 Banner、封面等的图片地址
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"wyy/internal/imaging"
+	"wyy/internal/middleware"
+	service "wyy/internal/service/media"
+	"wyy/internal/urlsign"
+	"wyy/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImageHandler 图片读取，只接受接口返回的签名链接。图片按内容寻址，返回长期缓存头，
+// 但只允许客户端缓存，避免共享缓存绕过签名
+type ImageHandler struct {
+	ImageService *service.ImageService
+	Signer       *urlsign.Signer
+}
+
+func NewImageHandler(imageService *service.ImageService, signer *urlsign.Signer) *ImageHandler {
+	return &ImageHandler{ImageService: imageService, Signer: signer}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *ImageHandler) RegisterRoutes(r gin.IRouter) {
+	signed := r.Group("", middleware.SignedURL(h.Signer, service.ImageParam))
+	signed.GET("/images/:name", h.get)
+	signed.HEAD("/images/:name", h.get)
+}
+
+// get 读取图片
+// @Summary      读取图片
+// @Description  param 为 200y200 时等比缩放后居中裁剪为 200x200，为 200x200 时等比缩放到不超过 200x200，宽或高为 0 时按另一边缩放；
+// @Description  宽高只能取 0、40、64、80、100、120、140、160、200、240、300、400、480、640、800、1024、1280、1600、2048，其他尺寸返回 400；
+// @Description  不放大原图。缩略图不透明时为 JPEG，否则为 PNG；Accept 不含 image/webp 时 WebP 原图转为 JPEG/PNG。
+// @Description  通过接口返回的签名链接访问，param 不参与签名可自行追加，签名无效或过期时返回 403
+// @Tags         媒体
+// @Produce      image/jpeg,image/png,image/gif,image/webp
+// @Param        name           path      string  true   "图片名，即上传返回的 key"
+// @Param        param          query     string  false  "缩略图规格，如 200y200"
+// @Param        expires        query     int     true   "链接过期时间（Unix 秒）"
+// @Param        sig            query     string  true   "链接签名"
+// @Param        If-None-Match  header    string  false  "上次返回的 ETag"
+// @Success      200            {file}    file
+// @Router       /api/images/{name} [get]
+func (h *ImageHandler) get(c *gin.Context) {
+	spec, err := imaging.ParseParam(c.Query("param"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	accept := c.GetHeader("Accept")
+	reader, obj, err := h.ImageService.OpenVariant(c.Request.Context(), c.Param("name"), spec, acceptsWebP(accept))
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	header := c.Writer.Header()
+	if strings.HasSuffix(c.Param("name"), ".webp") {
+		header.Set("Vary", "Accept")
+	}
+	header.Set("ETag", strconv.Quote(obj.ETag))
+	header.Set("Cache-Control", "private, max-age=31536000, immutable")
+	if obj.ContentType != "" {
+		header.Set("Content-Type", obj.ContentType)
+	}
+	http.ServeContent(c.Writer, c.Request, obj.Key, obj.ModTime, reader)
+}
+
+// ImageAdminHandler 图片上传管理后台接口
+type ImageAdminHandler struct {
+	ImageService *service.ImageService
+	adminToken   string
+}
+
+func NewImageAdminHandler(imageService *service.ImageService, adminToken string) *ImageAdminHandler {
+	return &ImageAdminHandler{ImageService: imageService, adminToken: adminToken}
+}
+
+// RegisterRoutes 实现 route.Registrar 接口
+func (h *ImageAdminHandler) RegisterRoutes(r gin.IRouter) {
+	r.POST("/admin/images", middleware.Admin(h.adminToken), h.upload)
+}
+
+// upload 上传图片
+// @Summary      上传图片
+// @Description  上传 JPEG/PNG/GIF/WebP 图片（不超过 10MB），返回的 url 可用于 Banner、封面等；相同内容重复上传返回同一地址
+// @Tags         管理后台
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true  "管理令牌"
+// @Param        file           formData  file    true  "图片文件"
+// @Success      200            {object}  utils.Response{data=ImageResponse}
+// @Router       /api/admin/images [post]
+func (h *ImageAdminHandler) upload(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	obj, err := h.ImageService.Upload(c.Request.Context(), file)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+	utils.Success(c, ImageResponse{
+		Key:         obj.Key,
+		URL:         service.ImageURL(obj.Key),
+		Size:        obj.Size,
+		ContentType: obj.ContentType,
+	})
+}
+
+// acceptsWebP 未带 Accept 或接受任意图片类型的客户端视为支持 WebP
+func acceptsWebP(accept string) bool {
+	if accept == "" {
+		return true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.ReplaceAll(params, " ", "") == "q=0" {
+			continue
+		}
+		switch strings.TrimSpace(mediaType) {
+		case "image/webp", "image/*", "*/*":
+			return true
+		}
+	}
+	return false
+}
+
+func respondImageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidImage):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrImageTooLarge):
+		utils.Error(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, service.ErrImageNotFound):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
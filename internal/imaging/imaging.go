@@ -0,0 +1,241 @@
+// Package imaging 纯 Go 的图片解码、缩放裁剪、编码和主色提取，用于生成封面、头像、Banner 的缩略图
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrInvalidParam = errors.New("invalid image param")
+	ErrUnsupported  = errors.New("unsupported image")
+	ErrTooLarge     = errors.New("image dimensions too large")
+)
+
+// 输出格式
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+)
+
+// 缩放方式，对应 param 中宽高之间的分隔字母
+const (
+	ModeCrop = 'y' // 等比缩放后居中裁剪到指定宽高
+	ModeFit  = 'x' // 等比缩放到不超过指定宽高，不裁剪
+)
+
+const (
+	MaxSide     = 2048       // 缩略图单边上限
+	maxPixels   = 40_000_000 // 解码前检查原图像素数，避免超大图片耗尽内存
+	jpegQuality = 85
+	colorSample = 64 // 提取主色前先缩小到该尺寸以内
+)
+
+// thumbnailSides 允许的缩略图边长，param 可以任意拼接时每种规格都会落盘一份，
+// 限定取值后单张图片的缩略图数量有上限
+var thumbnailSides = []int{40, 64, 80, 100, 120, 140, 160, 200, 240, 300, 400, 480, 640, 800, 1024, 1280, 1600, MaxSide}
+
+// Spec 缩略图规格，宽或高为 0 时按另一边等比缩放
+type Spec struct {
+	Width  int
+	Height int
+	Mode   byte
+}
+
+// IsZero 是否未指定规格，即返回原图
+func (s Spec) IsZero() bool {
+	return s.Width == 0 && s.Height == 0
+}
+
+// String 规格的规范写法，如 200y200，用作缓存 key
+func (s Spec) String() string {
+	return strconv.Itoa(s.Width) + string(s.Mode) + strconv.Itoa(s.Height)
+}
+
+// ParseParam 解析 200y200（缩放并裁剪）或 200x200（等比缩放不裁剪）形式的参数，空字符串返回零值。
+// 宽高只能取 0 或 thumbnailSides 中的值
+func ParseParam(param string) (Spec, error) {
+	if param == "" {
+		return Spec{}, nil
+	}
+	i := strings.IndexAny(param, "xy")
+	if i < 0 {
+		return Spec{}, fmt.Errorf("%w: %q", ErrInvalidParam, param)
+	}
+	width, err1 := strconv.Atoi(param[:i])
+	height, err2 := strconv.Atoi(param[i+1:])
+	if err1 != nil || err2 != nil || width < 0 || height < 0 || width+height == 0 {
+		return Spec{}, fmt.Errorf("%w: %q", ErrInvalidParam, param)
+	}
+	if !allowedSide(width) || !allowedSide(height) {
+		return Spec{}, fmt.Errorf("%w: unsupported size %q", ErrInvalidParam, param)
+	}
+	return Spec{Width: width, Height: height, Mode: param[i]}, nil
+}
+
+func allowedSide(n int) bool {
+	return n == 0 || slices.Contains(thumbnailSides, n)
+}
+
+// Decode 解码 JPEG/PNG/GIF/WebP，GIF 只取第一帧
+func Decode(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	return img, nil
+}
+
+// Resize 按规格缩放裁剪，不放大原图：原图小于目标尺寸时按原图能提供的最大尺寸输出，宽高比不变
+func Resize(img image.Image, spec Spec) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := float64(bounds.Dx()), float64(bounds.Dy())
+	if spec.IsZero() || srcW == 0 || srcH == 0 {
+		return img
+	}
+	w, h := float64(spec.Width), float64(spec.Height)
+	src := bounds
+
+	var scale float64
+	if spec.Mode == ModeCrop && w > 0 && h > 0 {
+		scale = max(w/srcW, h/srcH)
+		if scale > 1 {
+			w, h, scale = w/scale, h/scale, 1
+		}
+		// 从原图中央取与目标宽高比相同的区域
+		cropW, cropH := w/scale, h/scale
+		x0 := bounds.Min.X + int((srcW-cropW)/2)
+		y0 := bounds.Min.Y + int((srcH-cropH)/2)
+		src = image.Rect(x0, y0, x0+int(cropW+0.5), y0+int(cropH+0.5)).Intersect(bounds)
+	} else {
+		switch {
+		case w > 0 && h > 0:
+			scale = min(w/srcW, h/srcH)
+		case w > 0:
+			scale = w / srcW
+		default:
+			scale = h / srcH
+		}
+		scale = min(scale, 1)
+		w, h = srcW*scale, srcH*scale
+	}
+
+	dstW, dstH := max(int(w+0.5), 1), max(int(h+0.5), 1)
+	if src == bounds && dstW == bounds.Dx() && dstH == bounds.Dy() {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
+
+// Opaque 图片是否不含透明像素
+func Opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Encode 按格式编码
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		return png.Encode(w, img)
+	default:
+		return fmt.Errorf("%w: cannot encode %s", ErrUnsupported, format)
+	}
+}
+
+// DominantColor 提取主色：缩小后按 RGB 各 4 位量化统计，饱和度高的颜色加权，取权重最大的颜色桶的平均色。
+// 图片完全透明时 ok 为 false
+func DominantColor(img image.Image) (c color.RGBA, ok bool) {
+	small := Resize(img, Spec{Width: colorSample, Height: colorSample, Mode: ModeFit})
+	type bucket struct {
+		weight     float64
+		r, g, b, n uint64
+	}
+	buckets := make(map[uint16]*bucket)
+	bounds := small.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			nc := color.NRGBAModel.Convert(small.At(x, y)).(color.NRGBA)
+			if nc.A < 128 {
+				continue
+			}
+			key := uint16(nc.R>>4)<<8 | uint16(nc.G>>4)<<4 | uint16(nc.B>>4)
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.weight += 1 + 2*saturation(nc)
+			bk.r += uint64(nc.R)
+			bk.g += uint64(nc.G)
+			bk.b += uint64(nc.B)
+			bk.n++
+		}
+	}
+	var best *bucket
+	var bestKey uint16
+	for key, bk := range buckets {
+		// 权重相同时取 key 较小的桶，保证结果稳定
+		if best == nil || bk.weight > best.weight || (bk.weight == best.weight && key < bestKey) {
+			best, bestKey = bk, key
+		}
+	}
+	if best == nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{R: uint8(best.r / best.n), G: uint8(best.g / best.n), B: uint8(best.b / best.n), A: 0xff}, true
+}
+
+// HexColor 转为 #rrggbb
+func HexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// saturation HSL 饱和度，0 到 1
+func saturation(c color.NRGBA) float64 {
+	maxC := max(c.R, c.G, c.B)
+	minC := min(c.R, c.G, c.B)
+	if maxC == minC {
+		return 0
+	}
+	l := (float64(maxC) + float64(minC)) / 510
+	d := float64(maxC-minC) / 255
+	if l > 0.5 {
+		return d / (2 - float64(maxC)/255 - float64(minC)/255)
+	}
+	return d / (float64(maxC)/255 + float64(minC)/255)
+}
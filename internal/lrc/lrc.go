@@ -0,0 +1,189 @@
+// Package lrc 解析和校验 LRC 时间轴歌词，支持一行多个时间标签、[offset] 偏移和增强格式中的逐字时间标签
+package lrc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid lrc")
+
+// Line 一行歌词，Time 为已按 offset 修正后的开始时间（毫秒）
+type Line struct {
+	Time int64
+	Text string
+}
+
+// Lyric 解析后的歌词，Lines 按时间升序
+type Lyric struct {
+	Title  string
+	Artist string
+	Album  string
+	By     string // 歌词制作者
+	Offset int64  // 毫秒，正数表示歌词提前显示
+	Lines  []Line
+}
+
+// Parse 解析 LRC 文本。有无法识别的时间标签、没有时间标签的歌词行或没有任何歌词行时返回 ErrInvalid，
+// 错误信息包含行号
+func Parse(text string) (*Lyric, error) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	lyric := &Lyric{}
+	type timed struct {
+		time int64
+		text string
+	}
+	var lines []timed
+	for i, raw := range strings.Split(text, "\n") {
+		lineNo := i + 1
+		rest := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if rest == "" {
+			continue
+		}
+		var times []int64
+		for strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: line %d: unclosed tag", ErrInvalid, lineNo)
+			}
+			tag := rest[1:end]
+			rest = strings.TrimSpace(rest[end+1:])
+			if tag == "" {
+				continue
+			}
+			if tag[0] >= '0' && tag[0] <= '9' {
+				t, ok := parseTime(tag)
+				if !ok {
+					return nil, fmt.Errorf("%w: line %d: invalid time tag [%s]", ErrInvalid, lineNo, tag)
+				}
+				times = append(times, t)
+				continue
+			}
+			if len(times) > 0 {
+				return nil, fmt.Errorf("%w: line %d: unexpected tag [%s] after time tag", ErrInvalid, lineNo, tag)
+			}
+			if err := lyric.setTag(tag); err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalid, lineNo, err)
+			}
+		}
+		if len(times) == 0 {
+			if rest != "" {
+				return nil, fmt.Errorf("%w: line %d: missing time tag", ErrInvalid, lineNo)
+			}
+			continue
+		}
+		rest = stripWordTimes(rest)
+		for _, t := range times {
+			lines = append(lines, timed{time: t, text: rest})
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: no timed lines", ErrInvalid)
+	}
+
+	// 一行多个时间标签时展开后重新排序，同一时间保持原有顺序
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].time < lines[j].time })
+	lyric.Lines = make([]Line, len(lines))
+	for i, l := range lines {
+		lyric.Lines[i] = Line{Time: max(l.time-lyric.Offset, 0), Text: l.text}
+	}
+	return lyric, nil
+}
+
+// Validate 校验 LRC 文本，规则与 Parse 相同
+func Validate(text string) error {
+	_, err := Parse(text)
+	return err
+}
+
+// setTag 记录 [key:value] 形式的信息标签，不认识的标签忽略
+func (l *Lyric) setTag(tag string) error {
+	key, value, ok := strings.Cut(tag, ":")
+	if !ok {
+		return fmt.Errorf("invalid tag [%s]", tag)
+	}
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "ti":
+		l.Title = value
+	case "ar":
+		l.Artist = value
+	case "al":
+		l.Album = value
+	case "by":
+		l.By = value
+	case "offset":
+		offset, err := strconv.ParseInt(strings.TrimPrefix(value, "+"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid offset %q", value)
+		}
+		l.Offset = offset
+	}
+	return nil
+}
+
+// parseTime 解析 mm:ss、mm:ss.xx、mm:ss.xxx 或 mm:ss:xx，返回毫秒
+func parseTime(tag string) (int64, bool) {
+	minPart, rest, ok := strings.Cut(tag, ":")
+	if !ok {
+		return 0, false
+	}
+	secPart, fracPart := rest, ""
+	if i := strings.IndexAny(rest, ".:"); i >= 0 {
+		secPart, fracPart = rest[:i], rest[i+1:]
+	}
+	minutes, err := strconv.ParseInt(minPart, 10, 64)
+	if err != nil || minutes < 0 {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(secPart, 10, 64)
+	if err != nil || seconds < 0 || seconds >= 60 || len(secPart) > 2 {
+		return 0, false
+	}
+	var millis int64
+	if fracPart != "" {
+		if len(fracPart) > 3 {
+			return 0, false
+		}
+		frac, err := strconv.ParseInt(fracPart, 10, 64)
+		if err != nil || frac < 0 {
+			return 0, false
+		}
+		// 按位数换算：.5 为 500ms，.50 为 500ms，.500 为 500ms
+		for i := len(fracPart); i < 3; i++ {
+			frac *= 10
+		}
+		millis = frac
+	}
+	return (minutes*60+seconds)*1000 + millis, true
+}
+
+// stripWordTimes 去掉增强格式中 <mm:ss.xx> 形式的逐字时间标签
+func stripWordTimes(text string) string {
+	if !strings.Contains(text, "<") {
+		return text
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(text, '<')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '>')
+		if end < 0 {
+			break
+		}
+		if _, ok := parseTime(text[start+1 : start+end]); !ok {
+			b.WriteString(text[:start+end+1])
+			text = text[start+end+1:]
+			continue
+		}
+		b.WriteString(text[:start])
+		text = text[start+end+1:]
+	}
+	b.WriteString(text)
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type AlbumRepo struct {
+	db *gorm.DB
+}
+
+func NewAlbumRepo(db *gorm.DB) *AlbumRepo {
+	return &AlbumRepo{db: db}
+}
+
+// GetByID 根据 ID 查询专辑，不存在时返回 nil
+func (r *AlbumRepo) GetByID(ctx context.Context, id int64) (*domain.Album, error) {
+	var album domain.Album
+	err := r.db.WithContext(ctx).First(&album, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// GetByIDs 批量查询专辑，结果顺序不保证
+func (r *AlbumRepo) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Album, error) {
+	if len(ids) == 0 {
+		return []*domain.Album{}, nil
+	}
+	var albums []*domain.Album
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&albums).Error
+	return albums, err
+}
+
+// GetByArtistAndName 查询歌手名下指定名称的专辑，不存在时返回 nil
+func (r *AlbumRepo) GetByArtistAndName(ctx context.Context, artistID int64, name string) (*domain.Album, error) {
+	var album domain.Album
+	err := r.db.WithContext(ctx).Where("artist_id = ? AND name = ?", artistID, name).First(&album).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// ListByArtist 按发行时间倒序返回歌手的专辑
+func (r *AlbumRepo) ListByArtist(ctx context.Context, artistID int64, limit int) ([]*domain.Album, error) {
+	var albums []*domain.Album
+	err := r.db.WithContext(ctx).
+		Where("artist_id = ?", artistID).
+		Order("release_time DESC, id DESC").
+		Limit(limit).
+		Find(&albums).Error
+	return albums, err
+}
+
+// Create 创建专辑
+func (r *AlbumRepo) Create(ctx context.Context, album *domain.Album) error {
+	return r.db.WithContext(ctx).Create(album).Error
+}
+
+// Update 更新专辑
+func (r *AlbumRepo) Update(ctx context.Context, album *domain.Album) error {
+	return r.db.WithContext(ctx).Save(album).Error
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type ArtistRepo struct {
+	db *gorm.DB
+}
+
+func NewArtistRepo(db *gorm.DB) *ArtistRepo {
+	return &ArtistRepo{db: db}
+}
+
+// GetByID 根据 ID 查询歌手，不存在时返回 nil
+func (r *ArtistRepo) GetByID(ctx context.Context, id int64) (*domain.Artist, error) {
+	var artist domain.Artist
+	err := r.db.WithContext(ctx).First(&artist, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// GetByIDs 批量查询歌手，结果顺序不保证
+func (r *ArtistRepo) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Artist, error) {
+	if len(ids) == 0 {
+		return []*domain.Artist{}, nil
+	}
+	var artists []*domain.Artist
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&artists).Error
+	return artists, err
+}
+
+// GetByName 根据名称精确查询歌手，不存在时返回 nil
+func (r *ArtistRepo) GetByName(ctx context.Context, name string) (*domain.Artist, error) {
+	var artist domain.Artist
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&artist).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// Create 创建歌手
+func (r *ArtistRepo) Create(ctx context.Context, artist *domain.Artist) error {
+	return r.db.WithContext(ctx).Create(artist).Error
+}
+
+// Update 更新歌手
+func (r *ArtistRepo) Update(ctx context.Context, artist *domain.Artist) error {
+	return r.db.WithContext(ctx).Save(artist).Error
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LyricRepo struct {
+	db *gorm.DB
+}
+
+func NewLyricRepo(db *gorm.DB) *LyricRepo {
+	return &LyricRepo{db: db}
+}
+
+// GetBySongID 不存在时返回 nil, nil
+func (r *LyricRepo) GetBySongID(ctx context.Context, songID int64) (*domain.Lyric, error) {
+	var lyric domain.Lyric
+	err := r.db.WithContext(ctx).Where("song_id = ?", songID).First(&lyric).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lyric, nil
+}
+
+// Save 保存歌词，已存在时覆盖
+func (r *LyricRepo) Save(ctx context.Context, lyric *domain.Lyric) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "song_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"plain", "lrc", "translated_lrc", "contributor", "updated_at"}),
+	}).Create(lyric).Error
+}
+
+// Delete 删除歌词，返回是否存在
+func (r *LyricRepo) Delete(ctx context.Context, songID int64) (bool, error) {
+	result := r.db.WithContext(ctx).Where("song_id = ?", songID).Delete(&domain.Lyric{})
+	return result.RowsAffected > 0, result.Error
+}
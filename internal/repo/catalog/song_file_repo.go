@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"context"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SongFileRepo struct {
+	db *gorm.DB
+}
+
+func NewSongFileRepo(db *gorm.DB) *SongFileRepo {
+	return &SongFileRepo{db: db}
+}
+
+// ListBySong 歌曲的全部音频文件
+func (r *SongFileRepo) ListBySong(ctx context.Context, songID int64) ([]*domain.SongFile, error) {
+	var files []*domain.SongFile
+	err := r.db.WithContext(ctx).Where("song_id = ?", songID).Find(&files).Error
+	return files, err
+}
+
+// Save 保存音频文件，同一首歌同一音质已存在时覆盖
+func (r *SongFileRepo) Save(ctx context.Context, file *domain.SongFile) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "song_id"}, {Name: "quality"}},
+		DoUpdates: clause.AssignmentColumns([]string{"key", "format", "mime_type", "bitrate", "size", "updated_at"}),
+	}).Create(file).Error
+}
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type SongRepo struct {
+	db *gorm.DB
+}
+
+func NewSongRepo(db *gorm.DB) *SongRepo {
+	return &SongRepo{db: db}
+}
+
+// GetByID 根据 ID 查询歌曲，不存在时返回 nil。包含云盘私有歌曲，调用方按 OwnerID 判断可见性
+func (r *SongRepo) GetByID(ctx context.Context, id int64) (*domain.Song, error) {
+	var song domain.Song
+	err := r.db.WithContext(ctx).First(&song, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// GetByIDs 批量查询歌曲，结果顺序不保证。包含云盘私有歌曲，调用方按 OwnerID 判断可见性
+func (r *SongRepo) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Song, error) {
+	if len(ids) == 0 {
+		return []*domain.Song{}, nil
+	}
+	var songs []*domain.Song
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&songs).Error
+	return songs, err
+}
+
+// List 按条件分页查询歌曲，按发布时间倒序
+func (r *SongRepo) List(ctx context.Context, filter domain.SongFilter) ([]*domain.Song, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Song{}).Where("owner_id = ?", 0)
+	if filter.Tag != "" {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", filter.Tag)
+	}
+	if filter.ArtistID > 0 {
+		query = query.Where("id IN (?)", r.db.Model(&domain.SongArtist{}).Select("song_id").Where("artist_id = ?", filter.ArtistID))
+	}
+	if filter.PublishedAfter > 0 {
+		query = query.Where("publish_time >= ?", filter.PublishedAfter)
+	}
+	if filter.PublishedBefore > 0 {
+		query = query.Where("publish_time < ?", filter.PublishedBefore)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var songs []*domain.Song
+	err := query.
+		Order("publish_time DESC, id DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&songs).Error
+	return songs, total, err
+}
+
+// ListByName 按歌名精确查找曲库歌曲，不含云盘私有歌曲
+func (r *SongRepo) ListByName(ctx context.Context, name string, limit int) ([]*domain.Song, error) {
+	var songs []*domain.Song
+	err := r.db.WithContext(ctx).
+		Where("name = ? AND owner_id = ?", name, 0).
+		Order("play_count DESC, id").
+		Limit(limit).
+		Find(&songs).Error
+	return songs, err
+}
+
+// Create 创建歌曲
+func (r *SongRepo) Create(ctx context.Context, song *domain.Song) error {
+	return r.db.WithContext(ctx).Create(song).Error
+}
+
+// Update 更新歌曲
+func (r *SongRepo) Update(ctx context.Context, song *domain.Song) error {
+	return r.db.WithContext(ctx).Save(song).Error
+}
+
+// ListByAlbum 按碟片和曲目顺序返回专辑内的歌曲，不含云盘私有歌曲
+func (r *SongRepo) ListByAlbum(ctx context.Context, albumID int64) ([]*domain.Song, error) {
+	var songs []*domain.Song
+	err := r.db.WithContext(ctx).
+		Where("album_id = ? AND owner_id = ?", albumID, 0).
+		Order("disc_no, track_no, id").
+		Find(&songs).Error
+	return songs, err
+}
+
+// TopByArtist 返回歌手播放量最高的歌曲，不含云盘私有歌曲
+func (r *SongRepo) TopByArtist(ctx context.Context, artistID int64, limit int) ([]*domain.Song, error) {
+	var songs []*domain.Song
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&domain.SongArtist{}).Select("song_id").Where("artist_id = ?", artistID)).
+		Where("unavailable = ? AND owner_id = ?", false, 0).
+		Order("play_count DESC, id DESC").
+		Limit(limit).
+		Find(&songs).Error
+	return songs, err
+}
+
+// LoadArtists 为歌曲填充按署名顺序排列的歌手
+func (r *SongRepo) LoadArtists(ctx context.Context, songs []*domain.Song) error {
+	if len(songs) == 0 {
+		return nil
+	}
+	songIDs := make([]int64, 0, len(songs))
+	for _, song := range songs {
+		songIDs = append(songIDs, song.ID)
+	}
+	var links []*domain.SongArtist
+	if err := r.db.WithContext(ctx).Where("song_id IN ?", songIDs).Order("song_id, position").Find(&links).Error; err != nil {
+		return err
+	}
+	artistIDs := make([]int64, 0, len(links))
+	for _, link := range links {
+		artistIDs = append(artistIDs, link.ArtistID)
+	}
+	var artists []*domain.Artist
+	if len(artistIDs) > 0 {
+		if err := r.db.WithContext(ctx).Where("id IN ?", artistIDs).Find(&artists).Error; err != nil {
+			return err
+		}
+	}
+	artistMap := make(map[int64]*domain.Artist, len(artists))
+	for _, artist := range artists {
+		artistMap[artist.ID] = artist
+	}
+	songArtists := make(map[int64][]*domain.Artist, len(songs))
+	for _, link := range links {
+		if artist, ok := artistMap[link.ArtistID]; ok {
+			songArtists[link.SongID] = append(songArtists[link.SongID], artist)
+		}
+	}
+	for _, song := range songs {
+		song.Artists = songArtists[song.ID]
+	}
+	return nil
+}
+
+// SetArtists 按顺序重设歌曲的歌手，并同步歌手名展示文本
+func (r *SongRepo) SetArtists(ctx context.Context, songID int64, artistIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("song_id = ?", songID).Delete(&domain.SongArtist{}).Error; err != nil {
+			return err
+		}
+		if len(artistIDs) == 0 {
+			return tx.Model(&domain.Song{}).Where("id = ?", songID).Update("artist", "").Error
+		}
+		links := make([]*domain.SongArtist, 0, len(artistIDs))
+		for i, artistID := range artistIDs {
+			links = append(links, &domain.SongArtist{SongID: songID, ArtistID: artistID, Position: i})
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+		var artists []*domain.Artist
+		if err := tx.Where("id IN ?", artistIDs).Find(&artists).Error; err != nil {
+			return err
+		}
+		names := make(map[int64]string, len(artists))
+		for _, artist := range artists {
+			names[artist.ID] = artist.Name
+		}
+		display := make([]string, 0, len(artistIDs))
+		for _, artistID := range artistIDs {
+			if name, ok := names[artistID]; ok {
+				display = append(display, name)
+			}
+		}
+		return tx.Model(&domain.Song{}).Where("id = ?", songID).Update("artist", strings.Join(display, " / ")).Error
+	})
+}
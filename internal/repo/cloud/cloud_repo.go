@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrQuotaExceeded = errors.New("cloud quota exceeded")
+
+type CloudRepo struct {
+	db *gorm.DB
+}
+
+func NewCloudRepo(db *gorm.DB) *CloudRepo {
+	return &CloudRepo{db: db}
+}
+
+// GetByID 不存在时返回 nil, nil
+func (r *CloudRepo) GetByID(ctx context.Context, id int64) (*domain.CloudSong, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// GetByUserAndSong 用户云盘中对应某首歌曲的文件，不存在时返回 nil, nil
+func (r *CloudRepo) GetByUserAndSong(ctx context.Context, userID, songID int64) (*domain.CloudSong, error) {
+	return r.first(r.db.WithContext(ctx).Where("user_id = ? AND song_id = ?", userID, songID))
+}
+
+// GetByFingerprint 用户云盘中指纹相同的歌曲，不存在时返回 nil, nil
+func (r *CloudRepo) GetByFingerprint(ctx context.Context, userID int64, fingerprint string) (*domain.CloudSong, error) {
+	return r.first(r.db.WithContext(ctx).Where("user_id = ? AND fingerprint = ?", userID, fingerprint).Order("id"))
+}
+
+// List 按上传时间倒序分页
+func (r *CloudRepo) List(ctx context.Context, userID int64, page, pageSize int) ([]*domain.CloudSong, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.CloudSong{}).Where("user_id = ?", userID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var songs []*domain.CloudSong
+	err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&songs).Error
+	return songs, total, err
+}
+
+// Usage 用户云盘用量，没有记录时返回零值
+func (r *CloudRepo) Usage(ctx context.Context, userID int64) (*domain.CloudUsage, error) {
+	usage := domain.CloudUsage{UserID: userID}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Limit(1).Find(&usage).Error
+	return &usage, err
+}
+
+// Add 保存云盘歌曲并更新用量，超出 quota 时返回 ErrQuotaExceeded。
+// 用户云盘中已有同一首歌时替换，返回被替换的记录以便调用方清理文件。
+// private 不为 nil 时在同一事务中创建未匹配到曲库的私有歌曲，超额时不会留下歌曲记录
+func (r *CloudRepo) Add(ctx context.Context, song *domain.CloudSong, private *domain.Song, quota int64) (*domain.CloudSong, error) {
+	var replaced *domain.CloudSong
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		usage, err := lockUsage(tx, song.UserID)
+		if err != nil {
+			return err
+		}
+		var existing *domain.CloudSong
+		if private == nil {
+			if existing, err = r.first(tx.Where("user_id = ? AND song_id = ?", song.UserID, song.SongID)); err != nil {
+				return err
+			}
+		}
+		delta, count := song.Size, 1
+		if existing != nil {
+			delta, count = song.Size-existing.Size, 0
+		}
+		if delta > 0 && usage.UsedBytes+delta > quota {
+			return ErrQuotaExceeded
+		}
+		if private != nil {
+			if err := tx.Create(private).Error; err != nil {
+				return err
+			}
+			song.SongID = private.ID
+		}
+		if existing != nil {
+			song.ID = existing.ID
+			if err := tx.Save(song).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Create(song).Error; err != nil {
+			return err
+		}
+		replaced = existing
+		return updateUsage(tx, song.UserID, delta, count)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replaced, nil
+}
+
+// Delete 删除云盘歌曲并扣减用量
+func (r *CloudRepo) Delete(ctx context.Context, song *domain.CloudSong) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockUsage(tx, song.UserID); err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", song.ID).Delete(&domain.CloudSong{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return updateUsage(tx, song.UserID, -song.Size, -1)
+	})
+}
+
+func (r *CloudRepo) first(query *gorm.DB) (*domain.CloudSong, error) {
+	var song domain.CloudSong
+	err := query.First(&song).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// lockUsage 锁定用户用量行，不存在时先创建，串行化同一用户的并发上传
+func lockUsage(tx *gorm.DB, userID int64) (*domain.CloudUsage, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.CloudUsage{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+	var usage domain.CloudUsage
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&usage).Error
+	return &usage, err
+}
+
+func updateUsage(tx *gorm.DB, userID, deltaBytes int64, deltaCount int) error {
+	return tx.Model(&domain.CloudUsage{}).Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"used_bytes": gorm.Expr("used_bytes + ?", deltaBytes),
+			"song_count": gorm.Expr("song_count + ?", deltaCount),
+		}).Error
+}
@@ -0,0 +1,202 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 评论列表排序
+const (
+	SortHot    = "hot"    // 按点赞数
+	SortLatest = "latest" // 按发布时间
+)
+
+// Cursor 评论分页游标，Key 为排序字段（点赞数或发布时间）的值
+type Cursor struct {
+	Key int64
+	ID  int64
+}
+
+type CommentRepo struct {
+	db *gorm.DB
+}
+
+func NewCommentRepo(db *gorm.DB) *CommentRepo {
+	return &CommentRepo{db: db}
+}
+
+// GetByID 查询评论，不存在时返回 nil
+func (r *CommentRepo) GetByID(ctx context.Context, id int64) (*domain.Comment, error) {
+	var comment domain.Comment
+	err := r.db.WithContext(ctx).First(&comment, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Create 发布评论，同时累加目标评论数和顶层评论的回复数
+func (r *CommentRepo) Create(ctx context.Context, comment *domain.Comment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID > 0 {
+			if err := addReplyCount(tx, comment.ParentID, 1); err != nil {
+				return err
+			}
+		}
+		return addTargetCount(tx, comment.TargetType, comment.TargetID, 1)
+	})
+}
+
+// List 顶层评论列表，只返回正常状态的评论
+func (r *CommentRepo) List(ctx context.Context, targetType string, targetID int64, sort string, cursor *Cursor, limit int) ([]*domain.Comment, error) {
+	query := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ? AND parent_id = 0 AND status = ?", targetType, targetID, domain.CommentNormal)
+	key := "created_at"
+	if sort == SortHot {
+		key = "like_count"
+	}
+	if cursor != nil {
+		query = query.Where(key+" < ? OR ("+key+" = ? AND id < ?)", cursor.Key, cursor.Key, cursor.ID)
+	}
+	var comments []*domain.Comment
+	err := query.Order(key + " DESC, id DESC").Limit(limit).Find(&comments).Error
+	return comments, err
+}
+
+// ListReplies 顶层评论下的回复，按发布时间正序
+func (r *CommentRepo) ListReplies(ctx context.Context, parentID int64, cursor *Cursor, limit int) ([]*domain.Comment, error) {
+	query := r.db.WithContext(ctx).Where("parent_id = ? AND status = ?", parentID, domain.CommentNormal)
+	if cursor != nil {
+		query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", cursor.Key, cursor.Key, cursor.ID)
+	}
+	var comments []*domain.Comment
+	err := query.Order("created_at, id").Limit(limit).Find(&comments).Error
+	return comments, err
+}
+
+// SetStatus 修改评论状态并同步计数：隐藏顶层评论时其回复一并从目标评论数中扣除，恢复时加回。
+// 状态未变化时返回 false
+func (r *CommentRepo) SetStatus(ctx context.Context, id int64, status int8) (bool, error) {
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment domain.Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, id).Error; err != nil {
+			return err
+		}
+		wasVisible := comment.Status == domain.CommentNormal
+		visible := status == domain.CommentNormal
+		if comment.Status == status {
+			return nil
+		}
+		changed = true
+		if err := tx.Model(&comment).Update("status", status).Error; err != nil {
+			return err
+		}
+		if wasVisible == visible {
+			return nil
+		}
+		delta := 1
+		if !visible {
+			delta = -1
+		}
+
+		if comment.ParentID == 0 {
+			return addTargetCount(tx, comment.TargetType, comment.TargetID, int64(delta*(1+comment.ReplyCount)))
+		}
+		var parent domain.Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, comment.ParentID).Error; err != nil {
+			return err
+		}
+		if err := addReplyCount(tx, parent.ID, delta); err != nil {
+			return err
+		}
+		// 顶层评论已隐藏时，其回复已不计入目标评论数
+		if parent.Status != domain.CommentNormal {
+			return nil
+		}
+		return addTargetCount(tx, comment.TargetType, comment.TargetID, int64(delta))
+	})
+	return changed, err
+}
+
+// Like 点赞，已点赞时返回 false
+func (r *CommentRepo) Like(ctx context.Context, like *domain.CommentLike) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(like)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+		return tx.Model(&domain.Comment{}).Where("id = ?", like.CommentID).
+			UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+	})
+	return created, err
+}
+
+// Unlike 取消点赞，未点赞时返回 false
+func (r *CommentRepo) Unlike(ctx context.Context, commentID, userID int64) (bool, error) {
+	deleted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("comment_id = ? AND user_id = ?", commentID, userID).Delete(&domain.CommentLike{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Model(&domain.Comment{}).Where("id = ? AND like_count > 0", commentID).
+			UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error
+	})
+	return deleted, err
+}
+
+// LikedIDs 返回 commentIDs 中用户已点赞的评论
+func (r *CommentRepo) LikedIDs(ctx context.Context, userID int64, commentIDs []int64) (map[int64]bool, error) {
+	liked := make(map[int64]bool)
+	if userID == 0 || len(commentIDs) == 0 {
+		return liked, nil
+	}
+	var ids []int64
+	if err := r.db.WithContext(ctx).Model(&domain.CommentLike{}).
+		Where("user_id = ? AND comment_id IN ?", userID, commentIDs).
+		Pluck("comment_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// Count 目标的可见评论总数
+func (r *CommentRepo) Count(ctx context.Context, targetType string, targetID int64) (int64, error) {
+	var counts []*domain.CommentCount
+	err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Limit(1).
+		Find(&counts).Error
+	if err != nil || len(counts) == 0 {
+		return 0, err
+	}
+	return counts[0].Total, nil
+}
+
+func addReplyCount(tx *gorm.DB, parentID int64, delta int) error {
+	return tx.Model(&domain.Comment{}).Where("id = ?", parentID).
+		UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count + ?, 0)", delta)).Error
+}
+
+func addTargetCount(tx *gorm.DB, targetType string, targetID, delta int64) error {
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{"total": gorm.Expr("GREATEST(total + ?, 0)", delta)}),
+	}).Create(&domain.CommentCount{TargetType: targetType, TargetID: targetID, Total: max(delta, 0)}).Error
+}
@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// userActionRepo 基于 MySQL 的 UserActionRepo 实现
+type userActionRepo struct {
+	db *gorm.DB
+}
+
+func NewUserActionRepo(db *gorm.DB) UserActionRepo {
+	return &userActionRepo{db: db}
+}
+
+func (r *userActionRepo) GetRecentPlays(ctx context.Context, userID string, limit int) ([]*PlayRecord, error) {
+	var records []*PlayRecord
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("played_at DESC").
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
+
+func (r *userActionRepo) GetAllUserActions(ctx context.Context, userID string) ([]*UserAction, error) {
+	var actions []*UserAction
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&actions).Error
+	return actions, err
+}
+
+func (r *userActionRepo) BatchGetUserActions(ctx context.Context, userIDs []string) (map[string][]*UserAction, error) {
+	result := make(map[string][]*UserAction, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	var actions []*UserAction
+	if err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&actions).Error; err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		result[action.UserID] = append(result[action.UserID], action)
+	}
+	return result, nil
+}
+
+func (r *userActionRepo) InsertAction(ctx context.Context, action *UserAction) error {
+	return r.db.WithContext(ctx).Create(action).Error
+}
+
+func (r *userActionRepo) GetUserActionOnSong(ctx context.Context, userID, songID string) (*UserAction, error) {
+	var actions []*UserAction
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND song_id = ?", userID, songID).
+		Order("timestamp DESC").
+		Limit(1).
+		Find(&actions).Error
+	if err != nil || len(actions) == 0 {
+		return nil, err
+	}
+	return actions[0], nil
+}
+
+// songStatsRepo 从行为表聚合歌曲统计
+type songStatsRepo struct {
+	db *gorm.DB
+}
+
+func NewSongStatsRepo(db *gorm.DB) SongStatsRepo {
+	return &songStatsRepo{db: db}
+}
+
+func (r *songStatsRepo) GetSongStats(ctx context.Context, songIDs []string) (map[string]*SongStats, error) {
+	result := make(map[string]*SongStats, len(songIDs))
+	if len(songIDs) == 0 {
+		return result, nil
+	}
+	var rows []*SongStats
+	err := r.db.WithContext(ctx).Model(&UserAction{}).
+		Select("song_id, "+
+			"SUM(CASE WHEN action = 'play' THEN 1 ELSE 0 END) AS play_count, "+
+			"SUM(CASE WHEN action = 'like' THEN 1 ELSE 0 END) AS like_count, "+
+			"SUM(CASE WHEN action = 'skip' THEN 1 ELSE 0 END) AS skip_count").
+		Where("song_id IN ?", songIDs).
+		Group("song_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.SongID] = row
+	}
+	return result, nil
+}
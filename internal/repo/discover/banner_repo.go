@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// bannerRepo 基于 MySQL 的 Banner 存储
+type bannerRepo struct {
+	db *gorm.DB
+}
+
+func NewBannerRepo(db *gorm.DB) BannerRepo {
+	return &bannerRepo{db: db}
+}
+
+func (r *bannerRepo) ListBanners(ctx context.Context) ([]*Banner, error) {
+	var banners []*Banner
+	err := r.db.WithContext(ctx).Order("display_order, id").Find(&banners).Error
+	return banners, err
+}
+
+func (r *bannerRepo) ListUnexpired(ctx context.Context, now int64) ([]*Banner, error) {
+	var banners []*Banner
+	err := r.db.WithContext(ctx).
+		Where("end_at = 0 OR end_at > ?", now).
+		Order("display_order, id").
+		Find(&banners).Error
+	return banners, err
+}
+
+func (r *bannerRepo) GetBanner(ctx context.Context, id int64) (*Banner, error) {
+	var banner Banner
+	err := r.db.WithContext(ctx).First(&banner, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
+
+func (r *bannerRepo) CreateBanner(ctx context.Context, banner *Banner) error {
+	return r.db.WithContext(ctx).Create(banner).Error
+}
+
+func (r *bannerRepo) UpdateBanner(ctx context.Context, banner *Banner) error {
+	return r.db.WithContext(ctx).Save(banner).Error
+}
+
+func (r *bannerRepo) DeleteBanner(ctx context.Context, id int64) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&Banner{}, id)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"wyy/internal/cache"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// 热门歌曲统计窗口
+const hotSongsWindow = 7 * 24 * time.Hour
+
+// cacheRepo 热门/最新歌曲查询结果与相似用户列表缓存在进程内
+type cacheRepo struct {
+	db           *gorm.DB
+	songs        *cache.Memory[[]*Song]
+	similarUsers *cache.Memory[[]string]
+}
+
+func NewCacheRepo(db *gorm.DB) CacheRepo {
+	return &cacheRepo{
+		db:           db,
+		songs:        cache.NewMemory[[]*Song](10*time.Minute, 16),
+		similarUsers: cache.NewMemory[[]string](time.Hour, 100000),
+	}
+}
+
+func (r *cacheRepo) GetGlobalHotSongs(ctx context.Context, limit int) ([]*Song, error) {
+	return r.cachedSongs("hot", limit, func() ([]*Song, error) {
+		var songIDs []string
+		since := time.Now().Add(-hotSongsWindow).Unix()
+		err := r.db.WithContext(ctx).Model(&UserAction{}).
+			Where("timestamp >= ? AND action IN ?", since, []string{"play", "like"}).
+			Group("song_id").
+			Order("COUNT(*) DESC").
+			Limit(limit).
+			Pluck("song_id", &songIDs).Error
+		if err != nil {
+			return nil, err
+		}
+		songs, err := NewSongRepo(r.db).GetSongs(ctx, songIDs)
+		if err != nil {
+			return nil, err
+		}
+		// GetSongs 不保证顺序，按热度排名重排；云盘私有歌曲只有上传者能播放，不进入热门
+		byID := make(map[string]*Song, len(songs))
+		for _, song := range songs {
+			byID[song.ID] = song
+		}
+		public := make([]*Song, 0, len(songs))
+		for _, id := range songIDs {
+			if song, ok := byID[id]; ok && song.OwnerID == "" {
+				public = append(public, song)
+			}
+		}
+		return public, nil
+	})
+}
+
+func (r *cacheRepo) GetNewestSongs(ctx context.Context, limit int) ([]*Song, error) {
+	return r.cachedSongs("newest", limit, func() ([]*Song, error) {
+		var songs []*domain.Song
+		err := r.db.WithContext(ctx).
+			Where("unavailable = ? AND owner_id = ?", false, 0).
+			Order("publish_time DESC").
+			Limit(limit).
+			Find(&songs).Error
+		if err != nil {
+			return nil, err
+		}
+		if err := loadSongArtists(ctx, r.db, songs); err != nil {
+			return nil, err
+		}
+		return fromDomainSongs(songs), nil
+	})
+}
+
+func (r *cacheRepo) GetSimilarUsers(ctx context.Context, userID string) ([]string, error) {
+	users, _ := r.similarUsers.Get(userID)
+	return users, nil
+}
+
+func (r *cacheRepo) SetSimilarUsers(ctx context.Context, userID string, similarUsers []string, ttl int64) error {
+	r.similarUsers.SetWithTTL(userID, similarUsers, time.Duration(ttl)*time.Second)
+	return nil
+}
+
+// cachedSongs 缓存键包含 limit，不同长度的列表分别缓存
+func (r *cacheRepo) cachedSongs(name string, limit int, load func() ([]*Song, error)) ([]*Song, error) {
+	key := name + ":" + strconv.Itoa(limit)
+	if songs, ok := r.songs.Get(key); ok {
+		return songs, nil
+	}
+	songs, err := load()
+	if err != nil {
+		return nil, err
+	}
+	r.songs.Set(key, songs)
+	return songs, nil
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// chartRepo 基于 MySQL 的榜单统计与快照存储
+type chartRepo struct {
+	db *gorm.DB
+}
+
+func NewChartRepo(db *gorm.DB) ChartRepo {
+	return &chartRepo{db: db}
+}
+
+// actionCountSelect 按歌曲汇总播放和喜欢次数
+const actionCountSelect = "a.song_id, " +
+	"SUM(CASE WHEN a.action = 'play' THEN 1 ELSE 0 END) AS plays, " +
+	"SUM(CASE WHEN a.action = 'like' THEN 1 ELSE 0 END) AS likes"
+
+func (r *chartRepo) CountActions(ctx context.Context, query ChartQuery) ([]*SongActionCount, error) {
+	tx := r.db.WithContext(ctx).Table("user_actions AS a").
+		Select(actionCountSelect).
+		Joins("JOIN songs s ON s.id = a.song_id").
+		Where("a.timestamp >= ? AND a.timestamp < ? AND a.action IN ?", query.Since, query.Until, []string{"play", "like"}).
+		Where("s.unavailable = ? AND s.owner_id = ?", false, 0)
+	if query.Tag != "" {
+		tx = tx.Where("JSON_CONTAINS(s.tags, JSON_QUOTE(?))", query.Tag)
+	}
+	if query.PublishedAfter > 0 {
+		tx = tx.Where("s.publish_time >= ?", query.PublishedAfter)
+	}
+	var counts []*SongActionCount
+	err := tx.Group("a.song_id").
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "plays + likes * ? DESC, a.song_id", Vars: []any{query.LikeWeight}}}).
+		Limit(query.Limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *chartRepo) CountActionsBySongs(ctx context.Context, songIDs []string, since, until int64) (map[string]*SongActionCount, error) {
+	result := make(map[string]*SongActionCount, len(songIDs))
+	if len(songIDs) == 0 {
+		return result, nil
+	}
+	var counts []*SongActionCount
+	err := r.db.WithContext(ctx).Table("user_actions AS a").
+		Select(actionCountSelect).
+		Where("a.song_id IN ? AND a.timestamp >= ? AND a.timestamp < ? AND a.action IN ?", songIDs, since, until, []string{"play", "like"}).
+		Group("a.song_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		result[count.SongID] = count
+	}
+	return result, nil
+}
+
+func (r *chartRepo) GetSnapshot(ctx context.Context, chartID, period string) (*ChartSnapshot, error) {
+	return r.first(r.db.WithContext(ctx).Where("chart_id = ? AND period = ?", chartID, period))
+}
+
+func (r *chartRepo) GetPreviousSnapshot(ctx context.Context, chartID, period string) (*ChartSnapshot, error) {
+	return r.first(r.db.WithContext(ctx).Where("chart_id = ? AND period < ?", chartID, period).Order("period DESC"))
+}
+
+func (r *chartRepo) GetLatestSnapshot(ctx context.Context, chartID string) (*ChartSnapshot, error) {
+	return r.first(r.db.WithContext(ctx).Where("chart_id = ?", chartID).Order("period DESC"))
+}
+
+func (r *chartRepo) SaveSnapshot(ctx context.Context, snapshot *ChartSnapshot) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chart_id"}, {Name: "period"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entries", "updated_at"}),
+	}).Create(snapshot).Error
+}
+
+func (r *chartRepo) first(query *gorm.DB) (*ChartSnapshot, error) {
+	var snapshot ChartSnapshot
+	err := query.First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
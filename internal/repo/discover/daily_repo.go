@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dailyRepo 基于 MySQL 的每日推荐快照存储
+type dailyRepo struct {
+	db *gorm.DB
+}
+
+func NewDailyRepo(db *gorm.DB) DailyRepo {
+	return &dailyRepo{db: db}
+}
+
+func (r *dailyRepo) GetSnapshot(ctx context.Context, userID, date string) (*DailySnapshot, error) {
+	var snapshot DailySnapshot
+	err := r.db.WithContext(ctx).First(&snapshot, "user_id = ? AND date = ?", userID, date).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *dailyRepo) SaveSnapshot(ctx context.Context, snapshot *DailySnapshot) (bool, error) {
+	// 唯一索引冲突时忽略，保证同一天的列表不会被并发生成覆盖
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(snapshot)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *dailyRepo) ListActiveUsers(ctx context.Context, since int64) ([]string, error) {
+	var userIDs []string
+	err := r.db.WithContext(ctx).Model(&UserAction{}).
+		Where("timestamp >= ?", since).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
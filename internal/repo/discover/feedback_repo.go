@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// feedbackRepo 基于 MySQL 的负反馈存储
+type feedbackRepo struct {
+	db *gorm.DB
+}
+
+func NewFeedbackRepo(db *gorm.DB) FeedbackRepo {
+	return &feedbackRepo{db: db}
+}
+
+func (r *feedbackRepo) ListFeedback(ctx context.Context, userID string) ([]*Feedback, error) {
+	var feedback []*Feedback
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&feedback).Error
+	return feedback, err
+}
+
+func (r *feedbackRepo) AddFeedback(ctx context.Context, feedback *Feedback) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where(Feedback{UserID: feedback.UserID, TargetType: feedback.TargetType, TargetID: feedback.TargetID}).
+		FirstOrCreate(feedback)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *feedbackRepo) RemoveFeedback(ctx context.Context, userID, targetType, targetID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+		Delete(&Feedback{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// userSettingsRepo 基于 MySQL 的用户设置存储
+type userSettingsRepo struct {
+	db *gorm.DB
+}
+
+func NewUserSettingsRepo(db *gorm.DB) UserSettingsRepo {
+	return &userSettingsRepo{db: db}
+}
+
+func (r *userSettingsRepo) GetUserSettings(ctx context.Context, userID string) (*UserSettings, error) {
+	var settings UserSettings
+	err := r.db.WithContext(ctx).First(&settings, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// historyRepo 基于 MySQL 的播放历史存储
+type historyRepo struct {
+	db *gorm.DB
+}
+
+func NewHistoryRepo(db *gorm.DB) HistoryRepo {
+	return &historyRepo{db: db}
+}
+
+func (r *historyRepo) RecordPlay(ctx context.Context, record *PlayRecord, mergeWindow int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last []*PlayRecord
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", record.UserID).
+			Order("played_at DESC, id DESC").
+			Limit(1).
+			Find(&last).Error; err != nil {
+			return err
+		}
+		if len(last) > 0 && last[0].SongID == record.SongID && record.PlayedAt-last[0].PlayedAt <= mergeWindow {
+			err := tx.Model(last[0]).Updates(map[string]any{
+				"played_at": record.PlayedAt,
+				"duration":  gorm.Expr("duration + ?", record.Duration),
+				"plays":     gorm.Expr("plays + 1"),
+			}).Error
+			if err != nil {
+				return err
+			}
+			record.ID = last[0].ID
+		} else {
+			record.Plays = 1
+			if err := tx.Create(record).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&UserAction{
+			UserID:    record.UserID,
+			SongID:    record.SongID,
+			Action:    "play",
+			Value:     1,
+			Timestamp: record.PlayedAt,
+		}).Error; err != nil {
+			return err
+		}
+		// 播放量不影响歌曲更新时间，避免搜索索引频繁增量同步
+		return tx.Model(&domain.Song{}).Where("id = ?", record.SongID).
+			UpdateColumn("play_count", gorm.Expr("play_count + 1")).Error
+	})
+}
+
+func (r *historyRepo) ListPlays(ctx context.Context, userID string, cursor *PlayCursor, limit int) ([]*PlayRecord, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if cursor != nil {
+		query = query.Where("played_at < ? OR (played_at = ? AND id < ?)", cursor.PlayedAt, cursor.PlayedAt, cursor.ID)
+	}
+	var records []*PlayRecord
+	err := query.Order("played_at DESC, id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
+
+func (r *historyRepo) DeletePlay(ctx context.Context, userID string, id int64) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&PlayRecord{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *historyRepo) ClearPlays(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&PlayRecord{}).Error
+}
+
+func (r *historyRepo) TopSongs(ctx context.Context, userID string, since int64, limit int) ([]*SongPlayCount, error) {
+	query := r.db.WithContext(ctx).Model(&PlayRecord{}).
+		Select("song_id, SUM(plays) AS plays").
+		Where("user_id = ?", userID)
+	if since > 0 {
+		query = query.Where("played_at >= ?", since)
+	}
+	var counts []*SongPlayCount
+	err := query.Group("song_id").Order("plays DESC, MAX(played_at) DESC").Limit(limit).Scan(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// impressionRepo 基于 MySQL 的曝光日志存储
+type impressionRepo struct {
+	db *gorm.DB
+}
+
+func NewImpressionRepo(db *gorm.DB) ImpressionRepo {
+	return &impressionRepo{db: db}
+}
+
+func (r *impressionRepo) LogImpressions(ctx context.Context, impressions []*Impression) error {
+	if len(impressions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(impressions, 100).Error
+}
+
+func (r *impressionRepo) ListImpressions(ctx context.Context, since, until int64, limit int) ([]*Impression, error) {
+	var impressions []*Impression
+	err := r.db.WithContext(ctx).
+		Where("shown_at >= ? AND shown_at < ?", since, until).
+		Order("shown_at").
+		Limit(limit).
+		Find(&impressions).Error
+	return impressions, err
+}
+
+func (r *impressionRepo) CountUserImpressions(ctx context.Context, userID string, since int64) (map[string]int, error) {
+	var rows []struct {
+		SongID string
+		Count  int
+	}
+	err := r.db.WithContext(ctx).Model(&Impression{}).
+		Select("song_id, COUNT(*) AS count").
+		Where("user_id = ? AND shown_at >= ?", userID, since).
+		Group("song_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.SongID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,18 @@
+package repo
+
+import "gorm.io/gorm"
+
+// AutoMigrate 创建推荐模块使用的数据表（歌曲表由曲库模块维护）
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&UserAction{},
+		&PlayRecord{},
+		&UserProfile{},
+		&Impression{},
+		&Feedback{},
+		&UserSettings{},
+		&DailySnapshot{},
+		&Banner{},
+		&ChartSnapshot{},
+	)
+}
@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// playlistRepo 基于歌单模块数据表的歌单推荐查询，只返回公开且有歌曲的歌单
+type playlistRepo struct {
+	db *gorm.DB
+}
+
+func NewPlaylistRepo(db *gorm.DB) PlaylistRepo {
+	return &playlistRepo{db: db}
+}
+
+func (r *playlistRepo) GetPlaylists(ctx context.Context, playlistIDs []string) ([]*Playlist, error) {
+	ids := parseSongIDs(playlistIDs)
+	if len(ids) == 0 {
+		return []*Playlist{}, nil
+	}
+	var playlists []*domain.Playlist
+	err := r.public(ctx).Where("id IN ?", ids).Find(&playlists).Error
+	return fromDomainPlaylists(playlists), err
+}
+
+func (r *playlistRepo) GetPlaylistsByTags(ctx context.Context, tags []string, limit int) ([]*Playlist, error) {
+	if len(tags) == 0 {
+		return []*Playlist{}, nil
+	}
+	tagCond := r.db.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", tags[0])
+	for _, tag := range tags[1:] {
+		tagCond = tagCond.Or("JSON_CONTAINS(tags, JSON_QUOTE(?))", tag)
+	}
+	var playlists []*domain.Playlist
+	err := r.public(ctx).Where(tagCond).
+		Order("subscribe_count DESC, play_count DESC, id DESC").
+		Limit(limit).
+		Find(&playlists).Error
+	return fromDomainPlaylists(playlists), err
+}
+
+func (r *playlistRepo) GetPlaylistsBySongs(ctx context.Context, songIDs []string, limit int) ([]*PlaylistOverlap, error) {
+	ids := parseSongIDs(songIDs)
+	if len(ids) == 0 {
+		return []*PlaylistOverlap{}, nil
+	}
+	var rows []playlistSongRow
+	// 先取命中最多的歌单，再取这些歌单命中的具体歌曲
+	top := r.db.Model(&domain.PlaylistSong{}).
+		Select("playlist_songs.playlist_id").
+		Joins("JOIN playlists ON playlists.id = playlist_songs.playlist_id").
+		Where("playlist_songs.song_id IN ? AND playlists.public = ? AND playlists.song_count > 0", ids, true).
+		Group("playlist_songs.playlist_id").
+		Order("COUNT(*) DESC, playlist_songs.playlist_id DESC").
+		Limit(limit)
+	err := r.db.WithContext(ctx).Model(&domain.PlaylistSong{}).
+		Select("playlist_id, song_id").
+		Where("song_id IN ? AND playlist_id IN (?)", ids, r.db.Table("(?) AS top", top).Select("playlist_id")).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// 命中的歌曲按传入顺序排列，调用方通常传入最近喜欢的在前
+	order := make(map[int64]int, len(ids))
+	for i, id := range ids {
+		order[id] = i
+	}
+	slices.SortStableFunc(rows, func(a, b playlistSongRow) int { return cmp.Compare(order[a.SongID], order[b.SongID]) })
+	overlaps := make(map[int64]*PlaylistOverlap)
+	result := make([]*PlaylistOverlap, 0, limit)
+	for _, row := range rows {
+		overlap, ok := overlaps[row.PlaylistID]
+		if !ok {
+			overlap = &PlaylistOverlap{PlaylistID: strconv.FormatInt(row.PlaylistID, 10)}
+			overlaps[row.PlaylistID] = overlap
+			result = append(result, overlap)
+		}
+		overlap.Hits++
+		overlap.SongIDs = append(overlap.SongIDs, strconv.FormatInt(row.SongID, 10))
+	}
+	slices.SortStableFunc(result, func(a, b *PlaylistOverlap) int { return cmp.Compare(b.Hits, a.Hits) })
+	return result, nil
+}
+
+func (r *playlistRepo) GetPopularPlaylists(ctx context.Context, limit int) ([]*Playlist, error) {
+	var playlists []*domain.Playlist
+	err := r.public(ctx).
+		Order("subscribe_count DESC, play_count DESC, id DESC").
+		Limit(limit).
+		Find(&playlists).Error
+	return fromDomainPlaylists(playlists), err
+}
+
+func (r *playlistRepo) GetUserPlaylistIDs(ctx context.Context, userID string) ([]string, error) {
+	var owned, subscribed []int64
+	err := r.db.WithContext(ctx).Model(&domain.Playlist{}).Where("user_id = ?", userID).Pluck("id", &owned).Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.WithContext(ctx).Model(&domain.Subscription{}).
+		Where("user_id = ? AND target_type = ?", userID, domain.SubscribePlaylist).
+		Pluck("target_id", &subscribed).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(owned)+len(subscribed))
+	for _, id := range append(owned, subscribed...) {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	return ids, nil
+}
+
+func (r *playlistRepo) GetLikedSongIDs(ctx context.Context, userID string, limit int) ([]string, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Model(&domain.SongLike{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Pluck("song_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	songIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		songIDs = append(songIDs, strconv.FormatInt(id, 10))
+	}
+	return songIDs, nil
+}
+
+type playlistSongRow struct {
+	PlaylistID int64
+	SongID     int64
+}
+
+// public 公开且有歌曲的歌单
+func (r *playlistRepo) public(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Where("public = ? AND song_count > 0", true)
+}
+
+func fromDomainPlaylists(playlists []*domain.Playlist) []*Playlist {
+	result := make([]*Playlist, 0, len(playlists))
+	for _, playlist := range playlists {
+		result = append(result, &Playlist{
+			ID:             strconv.FormatInt(playlist.ID, 10),
+			Name:           playlist.Name,
+			CreatorID:      strconv.FormatInt(playlist.UserID, 10),
+			CoverURL:       playlist.CoverURL,
+			Tags:           playlist.Tags,
+			SongCount:      playlist.SongCount,
+			PlayCount:      playlist.PlayCount,
+			SubscribeCount: playlist.SubscribeCount,
+		})
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// userProfileRepo 基于 MySQL 的用户画像存储
+type userProfileRepo struct {
+	db *gorm.DB
+}
+
+func NewUserProfileRepo(db *gorm.DB) UserProfileRepo {
+	return &userProfileRepo{db: db}
+}
+
+// GetUserProfile 获取用户画像，不存在时返回 nil
+func (r *userProfileRepo) GetUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	var profile UserProfile
+	err := r.db.WithContext(ctx).First(&profile, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *userProfileRepo) UpdateUserProfile(ctx context.Context, profile *UserProfile) error {
+	return r.db.WithContext(ctx).Save(profile).Error
+}
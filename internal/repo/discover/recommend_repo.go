@@ -19,14 +19,17 @@ func NewRecommendRepo(db *gorm.DB) *RecommendRepo {
 // 推荐系统核心设计
 // PlayRecord 代表一次播放记录（可能包含时间、时长等）
 type PlayRecord struct {
-	UserID   string
+	ID       int64  `gorm:"primaryKey"`
+	UserID   string `gorm:"index:idx_play_user_time,priority:1"`
 	SongID   string
-	PlayedAt int64 // 时间戳
-	Duration int   // 播放时长（秒）
+	PlayedAt int64 `gorm:"index:idx_play_user_time,priority:2"` // 时间戳，连续重复播放时为最后一次
+	Duration int   // 播放时长（秒），连续重复播放时累加
+	Plays    int   // 连续重复播放合并后的播放次数
 }
 
 // UserAction 代表用户的完整行为（可用于评分计算）
 type UserAction struct {
+	ID        int64 `gorm:"primaryKey"`
 	UserID    string
 	SongID    string
 	Action    string  // "play", "like", "skip", "rate"
@@ -50,15 +53,54 @@ type UserActionRepo interface {
 	// 获取用户对特定歌曲的行为（用于判断是否已听过）
 	GetUserActionOnSong(ctx context.Context, userID, songID string) (*UserAction, error)
 }
+
+// PlayCursor 播放历史的分页游标，指向上一页最后一条记录
+type PlayCursor struct {
+	PlayedAt int64
+	ID       int64
+}
+
+// SongPlayCount 歌曲播放次数统计
+type SongPlayCount struct {
+	SongID string
+	Plays  int
+}
+
+type HistoryRepo interface {
+	// 记录一次播放：与用户最近一条记录是同一首歌且间隔不超过 mergeWindow 秒时合并为连续重复播放；
+	// 同时写入 play 行为并累加歌曲播放量
+	RecordPlay(ctx context.Context, record *PlayRecord, mergeWindow int64) error
+
+	// 按播放时间倒序获取播放历史，cursor 为 nil 时从最新开始
+	ListPlays(ctx context.Context, userID string, cursor *PlayCursor, limit int) ([]*PlayRecord, error)
+
+	// 删除一条播放历史，不存在时返回 false
+	DeletePlay(ctx context.Context, userID string, id int64) (bool, error)
+
+	// 清空播放历史
+	ClearPlays(ctx context.Context, userID string) error
+
+	// since 之后播放次数最多的歌曲，since 为 0 时统计全部历史
+	TopSongs(ctx context.Context, userID string, since int64, limit int) ([]*SongPlayCount, error)
+}
+
 type Song struct {
 	ID          string
 	Name        string
-	Artist      string
+	Artist      string   // 歌手名展示文本
+	ArtistIDs   []string // 按署名顺序的歌手 ID
+	ArtistNames []string // 与 ArtistIDs 一一对应
 	Album       string
+	CoverURL    string
 	Tags        []string // 风格标签
+	Language    string   // 语种，为空表示未知
 	Duration    int
 	PublishTime int64
 	Features    []float64 // 音频特征向量（可选）
+	Explicit    bool      // 是否含不适宜内容
+	Unavailable bool      // 下架或无版权
+	OwnerID     string    // 云盘私有歌曲的上传者，曲库歌曲为空，私有歌曲不参与推荐
+	Regions     []string  // 限定可播放的地区，为空表示不限
 }
 
 type SongRepo interface {
@@ -71,14 +113,14 @@ type SongRepo interface {
 	// 获取相似歌曲（基于音频特征或协同过滤结果）
 	GetSimilarSongs(ctx context.Context, songID string, limit int) ([]*Song, error)
 
-	// 获取艺人的代表歌曲
-	GetSongsByArtist(ctx context.Context, artist string, limit int) ([]*Song, error)
+	// 获取艺人的代表歌曲（按发布时间倒序）
+	GetSongsByArtist(ctx context.Context, artistID string, limit int) ([]*Song, error)
 }
 type UserProfile struct {
-	UserID           string
-	PreferredTags    map[string]float64 // 标签权重
-	PreferredArtists []string
-	Vector           []float64 // 隐向量（Embedding）
+	UserID           string             `gorm:"primaryKey"`
+	PreferredTags    map[string]float64 `gorm:"serializer:json"` // 标签权重
+	PreferredArtists []string           `gorm:"serializer:json"` // 偏好歌手 ID
+	Vector           []float64          `gorm:"serializer:json"` // 隐向量（Embedding）
 	UpdateTime       int64
 }
 
@@ -103,9 +145,303 @@ type CacheRepo interface {
 	// 设置相似用户列表
 	SetSimilarUsers(ctx context.Context, userID string, similarUsers []string, ttl int64) error
 }
+
+// SongStats 歌曲的全局统计数据（用于排序特征）
+type SongStats struct {
+	SongID    string
+	PlayCount int64
+	LikeCount int64
+	SkipCount int64
+}
+
+type SongStatsRepo interface {
+	// 批量获取歌曲统计，缺失的歌曲不出现在结果中
+	GetSongStats(ctx context.Context, songIDs []string) (map[string]*SongStats, error)
+}
+
+// Impression 一次推荐曝光记录，保存曝光时的特征用于离线训练
+type Impression struct {
+	ID          int64  `gorm:"primaryKey"`
+	UserID      string `gorm:"index:idx_impression_user_time"`
+	SongID      string
+	Source      string    // 召回来源
+	RecallScore float64   // 召回阶段原始得分
+	RankScore   float64   // 排序模型得分
+	Position    int       // 曝光位置
+	Features    []float64 `gorm:"serializer:json"`
+	ShownAt     int64     `gorm:"index:idx_impression_user_time"`
+}
+
+type ImpressionRepo interface {
+	// 批量写入曝光日志
+	LogImpressions(ctx context.Context, impressions []*Impression) error
+
+	// 按时间范围读取曝光日志（用于离线训练）
+	ListImpressions(ctx context.Context, since, until int64, limit int) ([]*Impression, error)
+
+	// 统计用户自 since 以来每首歌的曝光次数（用于曝光疲劳过滤）
+	CountUserImpressions(ctx context.Context, userID string, since int64) (map[string]int, error)
+}
+
+// 负反馈目标类型
+const (
+	FeedbackTargetSong   = "song"
+	FeedbackTargetArtist = "artist"
+	FeedbackTargetTag    = "tag"
+)
+
+// Feedback 用户的“不感兴趣”负反馈
+type Feedback struct {
+	ID         int64  `gorm:"primaryKey"`
+	UserID     string `gorm:"uniqueIndex:idx_feedback_target"`
+	TargetType string `gorm:"uniqueIndex:idx_feedback_target"` // song / artist / tag
+	TargetID   string `gorm:"uniqueIndex:idx_feedback_target"`
+	CreatedAt  int64
+}
+
+type FeedbackRepo interface {
+	// 获取用户的全部负反馈
+	ListFeedback(ctx context.Context, userID string) ([]*Feedback, error)
+
+	// 新增负反馈，已存在时返回 false
+	AddFeedback(ctx context.Context, feedback *Feedback) (bool, error)
+
+	// 撤销负反馈，不存在时返回 false
+	RemoveFeedback(ctx context.Context, userID, targetType, targetID string) (bool, error)
+}
+
+// UserSettings 与推荐相关的用户设置
+type UserSettings struct {
+	UserID       string `gorm:"primaryKey"`
+	HideExplicit bool   // 用户开启后过滤含不适宜内容的歌曲
+	Region       string // 用户所在地区，用于版权过滤
+}
+
+type UserSettingsRepo interface {
+	// 获取用户设置，未设置时返回 nil
+	GetUserSettings(ctx context.Context, userID string) (*UserSettings, error)
+}
+
+// 推荐理由类型
+const (
+	ReasonSimilarSong  = "similar_song"  // 与你喜欢的歌曲相似，Ref 为歌曲
+	ReasonSimilarUsers = "similar_users" // 口味相似的用户也在听
+	ReasonArtistNew    = "artist_new"    // 你喜欢的歌手的新歌，Ref 为歌手
+	ReasonTag          = "tag"           // 符合你偏好的风格，Ref 为标签
+	ReasonHot          = "hot"           // 热门歌曲
+	ReasonLikedSongs   = "liked_songs"   // 歌单收录了你喜欢的歌曲，Ref 为歌曲
+	ReasonHotPlaylist  = "hot_playlist"  // 热门歌单
+)
+
+// Reason 结构化的推荐理由，由召回器生成，文案在接口层本地化
+type Reason struct {
+	Type    string `json:"type"`
+	RefType string `json:"ref_type,omitempty"` // song / artist / tag
+	RefID   string `json:"ref_id,omitempty"`
+	RefName string `json:"ref_name,omitempty"`
+}
+
+// DailySnapshot 用户某一天的每日推荐快照
+type DailySnapshot struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    string    `gorm:"uniqueIndex:idx_daily_user_date"`
+	Date      string    `gorm:"uniqueIndex:idx_daily_user_date;size:10"` // 2006-01-02
+	SongIDs   []string  `gorm:"serializer:json"`
+	Reasons   []*Reason `gorm:"serializer:json"` // 与 SongIDs 一一对应
+	CreatedAt int64
+}
+
+type DailyRepo interface {
+	// 获取用户某天的快照，不存在时返回 nil
+	GetSnapshot(ctx context.Context, userID, date string) (*DailySnapshot, error)
+
+	// 保存快照，已存在时返回 false 且不覆盖
+	SaveSnapshot(ctx context.Context, snapshot *DailySnapshot) (bool, error)
+
+	// 获取 since 之后有行为的活跃用户
+	ListActiveUsers(ctx context.Context, since int64) ([]string, error)
+}
+
+// Banner 跳转目标类型
+const (
+	BannerTargetSong     = "song"
+	BannerTargetAlbum    = "album"
+	BannerTargetPlaylist = "playlist"
+	BannerTargetURL      = "url"
+)
+
+// Banner 发现页轮播图
+type Banner struct {
+	ID           int64 `gorm:"primaryKey"`
+	Title        string
+	ImageURL     string
+	TargetType   string   // song / album / playlist / url
+	TargetID     string   // 目标为 url 时存放链接
+	DisplayOrder int      // 越小越靠前
+	StartAt      int64    // 开始展示时间，0 表示立即
+	EndAt        int64    // 结束展示时间，0 表示不限
+	Platforms    []string `gorm:"serializer:json"` // web / ios / android，为空表示全平台
+	CreatedAt    int64
+	UpdatedAt    int64
+}
+
+type BannerRepo interface {
+	// 获取全部 Banner（管理后台）
+	ListBanners(ctx context.Context) ([]*Banner, error)
+
+	// 获取在 now 时尚未结束的 Banner，按展示顺序排列
+	ListUnexpired(ctx context.Context, now int64) ([]*Banner, error)
+
+	// 获取单个 Banner，不存在时返回 nil
+	GetBanner(ctx context.Context, id int64) (*Banner, error)
+
+	CreateBanner(ctx context.Context, banner *Banner) error
+
+	UpdateBanner(ctx context.Context, banner *Banner) error
+
+	// 删除 Banner，不存在时返回 false
+	DeleteBanner(ctx context.Context, id int64) (bool, error)
+}
+
+// ChartEntry 榜单中的一首歌，排名即在快照中的位置（从 1 开始）
+type ChartEntry struct {
+	SongID   string  `json:"song_id"`
+	Score    float64 `json:"score"`
+	LastRank int     `json:"last_rank"` // 上一期排名，0 表示新上榜
+}
+
+// ChartSnapshot 榜单某一期的结果，同一期内多次计算时覆盖
+type ChartSnapshot struct {
+	ID        int64         `gorm:"primaryKey"`
+	ChartID   string        `gorm:"uniqueIndex:idx_chart_period;size:64"`
+	Period    string        `gorm:"uniqueIndex:idx_chart_period;size:10"` // 2006-01-02
+	Entries   []*ChartEntry `gorm:"serializer:json"`
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// ChartQuery 榜单候选歌曲的统计条件，只统计公开且未下架的歌曲
+type ChartQuery struct {
+	Since          int64 // 统计窗口 [Since, Until)
+	Until          int64
+	Tag            string  // 只统计带该标签的歌曲，为空不限
+	PublishedAfter int64   // 只统计该时间之后发布的歌曲，0 不限
+	LikeWeight     float64 // 排序时一次喜欢折合的播放次数
+	Limit          int
+}
+
+// SongActionCount 歌曲在统计窗口内的播放和喜欢次数
+type SongActionCount struct {
+	SongID string
+	Plays  int
+	Likes  int
+}
+
+type ChartRepo interface {
+	// 按 Plays + Likes*LikeWeight 倒序统计符合条件的歌曲
+	CountActions(ctx context.Context, query ChartQuery) ([]*SongActionCount, error)
+
+	// 统计指定歌曲在 [since, until) 内的播放和喜欢次数，没有行为的歌曲不在结果中
+	CountActionsBySongs(ctx context.Context, songIDs []string, since, until int64) (map[string]*SongActionCount, error)
+
+	// 获取某一期快照，不存在时返回 nil
+	GetSnapshot(ctx context.Context, chartID, period string) (*ChartSnapshot, error)
+
+	// 获取 period 之前最近的一期快照，不存在时返回 nil
+	GetPreviousSnapshot(ctx context.Context, chartID, period string) (*ChartSnapshot, error)
+
+	// 获取最新一期快照，不存在时返回 nil
+	GetLatestSnapshot(ctx context.Context, chartID string) (*ChartSnapshot, error)
+
+	// 保存快照，同一期已存在时覆盖
+	SaveSnapshot(ctx context.Context, snapshot *ChartSnapshot) error
+}
+
+// Album 新碟上架使用的专辑信息
+type Album struct {
+	ID          string
+	Name        string
+	ArtistID    string
+	ArtistName  string
+	CoverURL    string
+	Language    string
+	ReleaseTime int64
+	SongCount   int // 公开且未下架的曲目数
+}
+
+// ReleaseQuery 新歌、新碟的查询条件，零值表示不限
+type ReleaseQuery struct {
+	Since     int64 // 发布时间窗口 [Since, Until)
+	Until     int64
+	Language  string   // 语种
+	Region    string   // 只返回在该地区可播放的歌曲，专辑至少有一首可播放
+	ArtistIDs []string // 只返回这些歌手的作品
+	Limit     int
+}
+
+type ReleaseRepo interface {
+	// 按发布时间倒序获取公开且未下架的新歌
+	ListNewSongs(ctx context.Context, query ReleaseQuery) ([]*Song, error)
+
+	// 按发行时间倒序获取有公开曲目的新专辑
+	ListNewAlbums(ctx context.Context, query ReleaseQuery) ([]*Album, error)
+
+	// 获取用户关注的歌手 ID
+	ListFollowedArtists(ctx context.Context, userID string) ([]string, error)
+}
+
+// Playlist 推荐使用的公开歌单信息
+type Playlist struct {
+	ID             string
+	Name           string
+	CreatorID      string
+	CoverURL       string
+	Tags           []string
+	SongCount      int
+	PlayCount      int64
+	SubscribeCount int64
+}
+
+// PlaylistOverlap 歌单包含指定歌曲的数量
+type PlaylistOverlap struct {
+	PlaylistID string
+	Hits       int      // 命中的歌曲数
+	SongIDs    []string // 命中的歌曲，按传入顺序
+}
+
+type PlaylistRepo interface {
+	// 根据 ID 批量获取公开歌单，不存在或非公开的歌单不在结果中
+	GetPlaylists(ctx context.Context, playlistIDs []string) ([]*Playlist, error)
+
+	// 获取带有任一标签的公开歌单，按收藏数倒序
+	GetPlaylistsByTags(ctx context.Context, tags []string, limit int) ([]*Playlist, error)
+
+	// 获取包含指定歌曲最多的公开歌单，按命中数倒序
+	GetPlaylistsBySongs(ctx context.Context, songIDs []string, limit int) ([]*PlaylistOverlap, error)
+
+	// 获取热门公开歌单，按收藏数和播放数倒序
+	GetPopularPlaylists(ctx context.Context, limit int) ([]*Playlist, error)
+
+	// 获取用户创建和收藏的歌单 ID，推荐时排除
+	GetUserPlaylistIDs(ctx context.Context, userID string) ([]string, error)
+
+	// 获取用户最近喜欢的歌曲 ID
+	GetLikedSongIDs(ctx context.Context, userID string, limit int) ([]string, error)
+}
+
 type Repository struct {
 	UserAction  UserActionRepo
 	Song        SongRepo
+	SongStats   SongStatsRepo
 	UserProfile UserProfileRepo
 	Cache       CacheRepo
+	Impression  ImpressionRepo
+	Feedback    FeedbackRepo
+	Settings    UserSettingsRepo
+	Daily       DailyRepo
+	Banner      BannerRepo
+	History     HistoryRepo
+	Chart       ChartRepo
+	Release     ReleaseRepo
+	Playlist    PlaylistRepo
 }
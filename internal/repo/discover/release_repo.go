@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"strconv"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// releaseRepo 基于曲库歌曲表和专辑表的新歌、新碟查询
+type releaseRepo struct {
+	db *gorm.DB
+}
+
+func NewReleaseRepo(db *gorm.DB) ReleaseRepo {
+	return &releaseRepo{db: db}
+}
+
+func (r *releaseRepo) ListNewSongs(ctx context.Context, query ReleaseQuery) ([]*Song, error) {
+	tx := r.db.WithContext(ctx).Table("songs AS s").
+		Where("s.unavailable = ? AND s.owner_id = ?", false, 0)
+	tx = releaseWindow(tx, "s.publish_time", query)
+	if query.Language != "" {
+		tx = tx.Where("s.language = ?", query.Language)
+	}
+	if query.Region != "" {
+		tx = tx.Where(regionCond("s.regions"), query.Region)
+	}
+	if len(query.ArtistIDs) > 0 {
+		tx = tx.Where("s.id IN (?)", r.db.Model(&domain.SongArtist{}).Select("song_id").Where("artist_id IN ?", query.ArtistIDs))
+	}
+	var songs []*domain.Song
+	if err := tx.Order("s.publish_time DESC, s.id DESC").Limit(query.Limit).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+	if err := loadSongArtists(ctx, r.db, songs); err != nil {
+		return nil, err
+	}
+	return fromDomainSongs(songs), nil
+}
+
+func (r *releaseRepo) ListNewAlbums(ctx context.Context, query ReleaseQuery) ([]*Album, error) {
+	// 只统计公开且未下架的曲目，没有这样曲目的专辑不上架
+	tx := r.db.WithContext(ctx).Table("albums AS al").
+		Select("al.id, al.name, al.artist_id, ar.name AS artist_name, al.cover_url, al.language, al.release_time, COUNT(s.id) AS song_count").
+		Joins("LEFT JOIN artists ar ON ar.id = al.artist_id").
+		Joins("JOIN songs s ON s.album_id = al.id AND s.unavailable = ? AND s.owner_id = ?", false, 0)
+	tx = releaseWindow(tx, "al.release_time", query)
+	if query.Language != "" {
+		tx = tx.Where("al.language = ?", query.Language)
+	}
+	if query.Region != "" {
+		tx = tx.Where(regionCond("s.regions"), query.Region)
+	}
+	if len(query.ArtistIDs) > 0 {
+		tx = tx.Where("al.artist_id IN ?", query.ArtistIDs)
+	}
+	var rows []struct {
+		ID          int64
+		Name        string
+		ArtistID    int64
+		ArtistName  *string
+		CoverURL    string
+		Language    string
+		ReleaseTime int64
+		SongCount   int
+	}
+	err := tx.Group("al.id, ar.name").
+		Order("al.release_time DESC, al.id DESC").
+		Limit(query.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	albums := make([]*Album, 0, len(rows))
+	for _, row := range rows {
+		album := &Album{
+			ID:          strconv.FormatInt(row.ID, 10),
+			Name:        row.Name,
+			CoverURL:    row.CoverURL,
+			Language:    row.Language,
+			ReleaseTime: row.ReleaseTime,
+			SongCount:   row.SongCount,
+		}
+		if row.ArtistID != 0 {
+			album.ArtistID = strconv.FormatInt(row.ArtistID, 10)
+		}
+		if row.ArtistName != nil {
+			album.ArtistName = *row.ArtistName
+		}
+		albums = append(albums, album)
+	}
+	return albums, nil
+}
+
+func (r *releaseRepo) ListFollowedArtists(ctx context.Context, userID string) ([]string, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Model(&domain.Subscription{}).
+		Where("user_id = ? AND target_type = ?", userID, domain.SubscribeArtist).
+		Pluck("target_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	artistIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		artistIDs = append(artistIDs, strconv.FormatInt(id, 10))
+	}
+	return artistIDs, nil
+}
+
+// releaseWindow 按发布时间窗口过滤，Since、Until 为 0 时不限
+func releaseWindow(tx *gorm.DB, column string, query ReleaseQuery) *gorm.DB {
+	if query.Since > 0 {
+		tx = tx.Where(column+" >= ?", query.Since)
+	}
+	if query.Until > 0 {
+		tx = tx.Where(column+" < ?", query.Until)
+	}
+	return tx
+}
+
+// regionCond 歌曲在某地区可播放：未限定地区，或限定的地区中包含该地区
+func regionCond(column string) string {
+	return "(" + column + " IS NULL OR JSON_TYPE(" + column + ") <> 'ARRAY' OR JSON_LENGTH(" + column + ") = 0 OR JSON_CONTAINS(" + column + ", JSON_QUOTE(?)))"
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"strconv"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// songRepo 基于曲库歌曲表的 SongRepo 实现
+type songRepo struct {
+	db *gorm.DB
+}
+
+func NewSongRepo(db *gorm.DB) SongRepo {
+	return &songRepo{db: db}
+}
+
+func (r *songRepo) GetSongs(ctx context.Context, songIDs []string) ([]*Song, error) {
+	ids := parseSongIDs(songIDs)
+	if len(ids) == 0 {
+		return []*Song{}, nil
+	}
+	var songs []*domain.Song
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&songs).Error
+	return r.withArtists(ctx, songs, err)
+}
+
+func (r *songRepo) GetTopSongsByTag(ctx context.Context, tag string, limit int) ([]*Song, error) {
+	var songs []*domain.Song
+	err := r.db.WithContext(ctx).
+		Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", tag).
+		Where("unavailable = ? AND owner_id = ?", false, 0).
+		Order("publish_time DESC").
+		Limit(limit).
+		Find(&songs).Error
+	return r.withArtists(ctx, songs, err)
+}
+
+func (r *songRepo) GetSimilarSongs(ctx context.Context, songID string, limit int) ([]*Song, error) {
+	var song domain.Song
+	if err := r.db.WithContext(ctx).First(&song, "id = ?", songID).Error; err != nil {
+		return nil, err
+	}
+	if len(song.Tags) == 0 {
+		return []*Song{}, nil
+	}
+
+	// 以标签重合作为相似度的近似
+	query := r.db.WithContext(ctx).Where("id <> ?", song.ID).Where("unavailable = ? AND owner_id = ?", false, 0)
+	tagCond := r.db.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", song.Tags[0])
+	for _, tag := range song.Tags[1:] {
+		tagCond = tagCond.Or("JSON_CONTAINS(tags, JSON_QUOTE(?))", tag)
+	}
+	var songs []*domain.Song
+	err := query.Where(tagCond).Order("publish_time DESC").Limit(limit).Find(&songs).Error
+	return r.withArtists(ctx, songs, err)
+}
+
+func (r *songRepo) GetSongsByArtist(ctx context.Context, artistID string, limit int) ([]*Song, error) {
+	var songs []*domain.Song
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&domain.SongArtist{}).Select("song_id").Where("artist_id = ?", artistID)).
+		Order("publish_time DESC").
+		Limit(limit).
+		Find(&songs).Error
+	return r.withArtists(ctx, songs, err)
+}
+
+// withArtists 转换为推荐模块的歌曲并填充歌手
+func (r *songRepo) withArtists(ctx context.Context, songs []*domain.Song, err error) ([]*Song, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := loadSongArtists(ctx, r.db, songs); err != nil {
+		return nil, err
+	}
+	return fromDomainSongs(songs), nil
+}
+
+// loadSongArtists 按署名顺序为歌曲填充歌手
+func loadSongArtists(ctx context.Context, db *gorm.DB, songs []*domain.Song) error {
+	if len(songs) == 0 {
+		return nil
+	}
+	songIDs := make([]int64, 0, len(songs))
+	for _, song := range songs {
+		songIDs = append(songIDs, song.ID)
+	}
+	var rows []struct {
+		SongID   int64
+		ArtistID int64
+		Name     string
+	}
+	err := db.WithContext(ctx).Model(&domain.SongArtist{}).
+		Select("song_artists.song_id, song_artists.artist_id, artists.name").
+		Joins("JOIN artists ON artists.id = song_artists.artist_id").
+		Where("song_artists.song_id IN ?", songIDs).
+		Order("song_artists.song_id, song_artists.position").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	songArtists := make(map[int64][]*domain.Artist, len(songs))
+	for _, row := range rows {
+		songArtists[row.SongID] = append(songArtists[row.SongID], &domain.Artist{ID: row.ArtistID, Name: row.Name})
+	}
+	for _, song := range songs {
+		song.Artists = songArtists[song.ID]
+	}
+	return nil
+}
+
+// parseSongIDs 推荐模块使用字符串 ID，非法 ID 直接忽略
+func parseSongIDs(songIDs []string) []int64 {
+	ids := make([]int64, 0, len(songIDs))
+	for _, songID := range songIDs {
+		if id, err := strconv.ParseInt(songID, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func fromDomainSong(song *domain.Song) *Song {
+	artistIDs := make([]string, 0, len(song.Artists))
+	artistNames := make([]string, 0, len(song.Artists))
+	for _, artist := range song.Artists {
+		artistIDs = append(artistIDs, strconv.FormatInt(artist.ID, 10))
+		artistNames = append(artistNames, artist.Name)
+	}
+	return &Song{
+		ID:          strconv.FormatInt(song.ID, 10),
+		Name:        song.Name,
+		Artist:      song.Artist,
+		ArtistIDs:   artistIDs,
+		ArtistNames: artistNames,
+		Album:       song.Album,
+		CoverURL:    song.CoverURL,
+		Tags:        song.Tags,
+		Language:    song.Language,
+		Duration:    song.Duration,
+		PublishTime: song.PublishTime,
+		Features:    song.Features,
+		Explicit:    song.Explicit,
+		Unavailable: song.Unavailable,
+		OwnerID:     ownerID(song.OwnerID),
+		Regions:     song.Regions,
+	}
+}
+
+func ownerID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func fromDomainSongs(songs []*domain.Song) []*Song {
+	result := make([]*Song, 0, len(songs))
+	for _, song := range songs {
+		result = append(result, fromDomainSong(song))
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"context"
+	"strconv"
+	"wyy/internal/domain"
+	discover "wyy/internal/repo/discover"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeAction 写入行为表的动作名，推荐模块据此读取喜欢
+const likeAction = "like"
+
+// LibraryRepo 喜欢的歌曲与收藏，计数与关系在同一事务内维护
+type LibraryRepo struct {
+	db *gorm.DB
+}
+
+func NewLibraryRepo(db *gorm.DB) *LibraryRepo {
+	return &LibraryRepo{db: db}
+}
+
+// LikeSong 喜欢歌曲，同时累加歌曲喜欢数并写入 like 行为；已喜欢时返回 false
+func (r *LibraryRepo) LikeSong(ctx context.Context, like *domain.SongLike) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(like)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+		if err := tx.Model(&domain.Song{}).Where("id = ?", like.SongID).
+			UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Create(&discover.UserAction{
+			UserID:    strconv.FormatInt(like.UserID, 10),
+			SongID:    strconv.FormatInt(like.SongID, 10),
+			Action:    likeAction,
+			Value:     1,
+			Timestamp: like.CreatedAt,
+		}).Error
+	})
+	return created, err
+}
+
+// UnlikeSong 取消喜欢，同时扣减喜欢数并删除对应的 like 行为；未喜欢时返回 false
+func (r *LibraryRepo) UnlikeSong(ctx context.Context, userID, songID int64) (bool, error) {
+	deleted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND song_id = ?", userID, songID).Delete(&domain.SongLike{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		if err := tx.Model(&domain.Song{}).Where("id = ? AND like_count > 0", songID).
+			UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND song_id = ? AND action = ?",
+			strconv.FormatInt(userID, 10), strconv.FormatInt(songID, 10), likeAction).
+			Delete(&discover.UserAction{}).Error
+	})
+	return deleted, err
+}
+
+// ListLikes 分页获取喜欢的歌曲，最近喜欢的在前
+func (r *LibraryRepo) ListLikes(ctx context.Context, userID int64, page, pageSize int) ([]*domain.SongLike, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.SongLike{}).Where("user_id = ?", userID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var likes []*domain.SongLike
+	err := query.Order("created_at DESC, song_id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&likes).Error
+	return likes, total, err
+}
+
+// LikedSongIDs 用户喜欢的全部歌曲 ID
+func (r *LibraryRepo) LikedSongIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Model(&domain.SongLike{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, song_id DESC").
+		Pluck("song_id", &ids).Error
+	return ids, err
+}
+
+// Subscribe 收藏，歌单同时累加收藏数；已收藏时返回 false
+func (r *LibraryRepo) Subscribe(ctx context.Context, sub *domain.Subscription) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(sub)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+		if sub.TargetType != domain.SubscribePlaylist {
+			return nil
+		}
+		return tx.Model(&domain.Playlist{}).Where("id = ?", sub.TargetID).
+			UpdateColumn("subscribe_count", gorm.Expr("subscribe_count + 1")).Error
+	})
+	return created, err
+}
+
+// Unsubscribe 取消收藏，歌单同时扣减收藏数；未收藏时返回 false
+func (r *LibraryRepo) Unsubscribe(ctx context.Context, userID int64, targetType string, targetID int64) (bool, error) {
+	deleted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+			Delete(&domain.Subscription{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		if targetType != domain.SubscribePlaylist {
+			return nil
+		}
+		return tx.Model(&domain.Playlist{}).Where("id = ? AND subscribe_count > 0", targetID).
+			UpdateColumn("subscribe_count", gorm.Expr("subscribe_count - 1")).Error
+	})
+	return deleted, err
+}
+
+// ListSubscriptions 分页获取某类收藏，最近收藏的在前
+func (r *LibraryRepo) ListSubscriptions(ctx context.Context, userID int64, targetType string, page, pageSize int) ([]*domain.Subscription, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Subscription{}).
+		Where("user_id = ? AND target_type = ?", userID, targetType)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var subs []*domain.Subscription
+	err := query.Order("created_at DESC, target_id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&subs).Error
+	return subs, total, err
+}
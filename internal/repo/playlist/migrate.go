@@ -0,0 +1,12 @@
+package repo
+
+import (
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// AutoMigrate 创建歌单相关数据表
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(&domain.Playlist{}, &domain.PlaylistSong{})
+}
@@ -0,0 +1,239 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// positionGap 相邻歌曲 Position 的初始间隔
+const positionGap int64 = 1 << 16
+
+var ErrSongNotInPlaylist = errors.New("song not in playlist")
+
+type PlaylistRepo struct {
+	db *gorm.DB
+}
+
+func NewPlaylistRepo(db *gorm.DB) *PlaylistRepo {
+	return &PlaylistRepo{db: db}
+}
+
+// GetByID 查询歌单，不存在时返回 nil
+func (r *PlaylistRepo) GetByID(ctx context.Context, id int64) (*domain.Playlist, error) {
+	var playlist domain.Playlist
+	err := r.db.WithContext(ctx).First(&playlist, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+// GetByIDs 批量查询歌单，结果顺序不保证
+func (r *PlaylistRepo) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Playlist, error) {
+	if len(ids) == 0 {
+		return []*domain.Playlist{}, nil
+	}
+	var playlists []*domain.Playlist
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&playlists).Error
+	return playlists, err
+}
+
+// ListByUser 用户创建的歌单，按创建时间倒序；includePrivate 为 false 时只返回公开歌单
+func (r *PlaylistRepo) ListByUser(ctx context.Context, userID int64, includePrivate bool, page, pageSize int) ([]*domain.Playlist, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Playlist{}).Where("user_id = ?", userID)
+	if !includePrivate {
+		query = query.Where("public = ?", true)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var playlists []*domain.Playlist
+	err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&playlists).Error
+	return playlists, total, err
+}
+
+func (r *PlaylistRepo) Create(ctx context.Context, playlist *domain.Playlist) error {
+	return r.db.WithContext(ctx).Create(playlist).Error
+}
+
+// UpdateInfo 更新歌单的名称、描述、封面、标签和公开状态
+func (r *PlaylistRepo) UpdateInfo(ctx context.Context, playlist *domain.Playlist) error {
+	return r.db.WithContext(ctx).Model(playlist).
+		Select("name", "description", "cover_url", "cover_color", "tags", "public").
+		Updates(playlist).Error
+}
+
+// Delete 删除歌单及其歌曲
+func (r *PlaylistRepo) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("playlist_id = ?", id).Delete(&domain.PlaylistSong{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Playlist{}, id).Error
+	})
+}
+
+// ListSongIDs 按歌单顺序返回歌曲 ID
+func (r *PlaylistRepo) ListSongIDs(ctx context.Context, playlistID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Model(&domain.PlaylistSong{}).
+		Where("playlist_id = ?", playlistID).
+		Order("position, added_at, song_id").
+		Pluck("song_id", &ids).Error
+	return ids, err
+}
+
+// AddSongs 将歌曲按顺序追加到歌单末尾，已在歌单中的歌曲被忽略，返回实际添加的数量
+func (r *PlaylistRepo) AddSongs(ctx context.Context, playlistID int64, songIDs []int64) (int, error) {
+	added := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPlaylist(tx, playlistID); err != nil {
+			return err
+		}
+		var existing []int64
+		if err := tx.Model(&domain.PlaylistSong{}).
+			Where("playlist_id = ? AND song_id IN ?", playlistID, songIDs).
+			Pluck("song_id", &existing).Error; err != nil {
+			return err
+		}
+		skip := make(map[int64]bool, len(existing))
+		for _, id := range existing {
+			skip[id] = true
+		}
+
+		var last int64
+		if err := tx.Model(&domain.PlaylistSong{}).
+			Where("playlist_id = ?", playlistID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		now := time.Now().Unix()
+		rows := make([]*domain.PlaylistSong, 0, len(songIDs))
+		for _, id := range songIDs {
+			if skip[id] {
+				continue
+			}
+			skip[id] = true
+			last += positionGap
+			rows = append(rows, &domain.PlaylistSong{PlaylistID: playlistID, SongID: id, Position: last, AddedAt: now})
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		added = len(rows)
+		return updateSongCount(tx, playlistID, added)
+	})
+	return added, err
+}
+
+// RemoveSongs 从歌单移除歌曲，返回实际移除的数量
+func (r *PlaylistRepo) RemoveSongs(ctx context.Context, playlistID int64, songIDs []int64) (int, error) {
+	removed := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPlaylist(tx, playlistID); err != nil {
+			return err
+		}
+		result := tx.Where("playlist_id = ? AND song_id IN ?", playlistID, songIDs).Delete(&domain.PlaylistSong{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = int(result.RowsAffected)
+		if removed == 0 {
+			return nil
+		}
+		return updateSongCount(tx, playlistID, -removed)
+	})
+	return removed, err
+}
+
+// MoveSong 将歌曲移动到 afterSongID 之后，afterSongID 为 0 时移到最前
+func (r *PlaylistRepo) MoveSong(ctx context.Context, playlistID, songID, afterSongID int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPlaylist(tx, playlistID); err != nil {
+			return err
+		}
+		var rows []*domain.PlaylistSong
+		if err := tx.Where("playlist_id = ?", playlistID).
+			Order("position, added_at, song_id").
+			Find(&rows).Error; err != nil {
+			return err
+		}
+
+		// 去掉被移动的歌曲后，在 afterSongID 后面找插入位置
+		from, to := -1, -1
+		if afterSongID == 0 {
+			to = 0
+		}
+		rest := make([]*domain.PlaylistSong, 0, len(rows))
+		for i, row := range rows {
+			if row.SongID == songID {
+				from = i
+				continue
+			}
+			rest = append(rest, row)
+			if row.SongID == afterSongID {
+				to = len(rest)
+			}
+		}
+		if from < 0 || to < 0 {
+			return ErrSongNotInPlaylist
+		}
+
+		prev, next := int64(0), rows[len(rows)-1].Position+2*positionGap
+		if to > 0 {
+			prev = rest[to-1].Position
+		}
+		if to < len(rest) {
+			next = rest[to].Position
+		}
+		if next-prev > 1 {
+			return tx.Model(&domain.PlaylistSong{}).
+				Where("playlist_id = ? AND song_id = ?", playlistID, songID).
+				Update("position", prev+(next-prev)/2).Error
+		}
+
+		// 间隔用尽，按新顺序整单重新编号
+		ordered := make([]*domain.PlaylistSong, 0, len(rows))
+		ordered = append(ordered, rest[:to]...)
+		ordered = append(ordered, rows[from])
+		ordered = append(ordered, rest[to:]...)
+		for i, row := range ordered {
+			if err := tx.Model(&domain.PlaylistSong{}).
+				Where("playlist_id = ? AND song_id = ?", playlistID, row.SongID).
+				Update("position", int64(i+1)*positionGap).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lockPlaylist 锁住歌单行，串行化同一歌单的并发修改
+func lockPlaylist(tx *gorm.DB, playlistID int64) error {
+	var playlist domain.Playlist
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&playlist, playlistID).Error
+}
+
+func updateSongCount(tx *gorm.DB, playlistID int64, delta int) error {
+	return tx.Model(&domain.Playlist{}).Where("id = ?", playlistID).
+		Updates(map[string]any{
+			"song_count": gorm.Expr("song_count + ?", delta),
+			"updated_at": time.Now().Unix(),
+		}).Error
+}
@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// SearchLog 一次搜索请求，用于计算热搜
+type SearchLog struct {
+	ID        int64  `gorm:"primaryKey"`
+	UserID    int64  `gorm:"index"` // 游客为 0
+	Query     string `gorm:"size:64;index"`
+	CreatedAt int64  `gorm:"index"`
+}
+
+// BlockedTerm 热搜屏蔽词，包含该词的搜索词不会进入热搜榜
+type BlockedTerm struct {
+	ID        int64  `gorm:"primaryKey"`
+	Term      string `gorm:"size:64;uniqueIndex"`
+	CreatedAt int64
+}
+
+// QueryCount 某个搜索词在某小时内的搜索次数
+type QueryCount struct {
+	Query string
+	Hour  int64 // 小时序号，即 unix 秒 / 3600
+	Count int64
+}
+
+// HotSearchRepo 搜索日志与屏蔽词存储
+type HotSearchRepo struct {
+	db *gorm.DB
+}
+
+func NewHotSearchRepo(db *gorm.DB) *HotSearchRepo {
+	return &HotSearchRepo{db: db}
+}
+
+func (r *HotSearchRepo) LogSearch(ctx context.Context, log *SearchLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// CountByHour 按搜索词和小时聚合 since 之后的搜索次数
+func (r *HotSearchRepo) CountByHour(ctx context.Context, since int64) ([]*QueryCount, error) {
+	var counts []*QueryCount
+	err := r.db.WithContext(ctx).Model(&SearchLog{}).
+		Select("query, FLOOR(created_at / 3600) AS hour, COUNT(*) AS count").
+		Where("created_at >= ?", since).
+		Group("query, hour").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *HotSearchRepo) ListBlockedTerms(ctx context.Context) ([]*BlockedTerm, error) {
+	var terms []*BlockedTerm
+	err := r.db.WithContext(ctx).Order("id").Find(&terms).Error
+	return terms, err
+}
+
+// AddBlockedTerm 已存在时返回已有记录
+func (r *HotSearchRepo) AddBlockedTerm(ctx context.Context, term *BlockedTerm) (*BlockedTerm, error) {
+	err := r.db.WithContext(ctx).Where(BlockedTerm{Term: term.Term}).FirstOrCreate(term).Error
+	return term, err
+}
+
+// DeleteBlockedTerm 删除屏蔽词，返回是否存在
+func (r *HotSearchRepo) DeleteBlockedTerm(ctx context.Context, id int64) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&BlockedTerm{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
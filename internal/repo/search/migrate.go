@@ -0,0 +1,11 @@
+package repo
+
+import "gorm.io/gorm"
+
+// AutoMigrate 创建搜索模块使用的数据表
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&SearchLog{},
+		&BlockedTerm{},
+	)
+}
@@ -0,0 +1,93 @@
+package repo
+
+import (
+	"context"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// SearchRepo 为搜索索引读取曲库数据
+type SearchRepo struct {
+	db *gorm.DB
+}
+
+func NewSearchRepo(db *gorm.DB) *SearchRepo {
+	return &SearchRepo{db: db}
+}
+
+// ListSongsUpdatedSince 按 ID 分批读取 updated_at >= since 的歌曲
+func (r *SearchRepo) ListSongsUpdatedSince(ctx context.Context, since, afterID int64, limit int) ([]*domain.Song, error) {
+	return listUpdatedSince[domain.Song](ctx, r.db, since, afterID, limit)
+}
+
+// ListArtistsUpdatedSince 按 ID 分批读取 updated_at >= since 的歌手
+func (r *SearchRepo) ListArtistsUpdatedSince(ctx context.Context, since, afterID int64, limit int) ([]*domain.Artist, error) {
+	return listUpdatedSince[domain.Artist](ctx, r.db, since, afterID, limit)
+}
+
+// ListAlbumsUpdatedSince 按 ID 分批读取 updated_at >= since 的专辑
+func (r *SearchRepo) ListAlbumsUpdatedSince(ctx context.Context, since, afterID int64, limit int) ([]*domain.Album, error) {
+	return listUpdatedSince[domain.Album](ctx, r.db, since, afterID, limit)
+}
+
+// ListPlaylistsUpdatedSince 按 ID 分批读取 updated_at >= since 的歌单
+func (r *SearchRepo) ListPlaylistsUpdatedSince(ctx context.Context, since, afterID int64, limit int) ([]*domain.Playlist, error) {
+	return listUpdatedSince[domain.Playlist](ctx, r.db, since, afterID, limit)
+}
+
+// ArtistNames 批量获取歌手名
+func (r *SearchRepo) ArtistNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	var artists []*domain.Artist
+	if len(ids) > 0 {
+		if err := r.db.WithContext(ctx).Select("id, name").Where("id IN ?", ids).Find(&artists).Error; err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[int64]string, len(artists))
+	for _, artist := range artists {
+		names[artist.ID] = artist.Name
+	}
+	return names, nil
+}
+
+// ArtistPopularity 歌手热度：名下歌曲播放量之和
+func (r *SearchRepo) ArtistPopularity(ctx context.Context) (map[int64]float64, error) {
+	return r.sumPlayCount(ctx, r.db.WithContext(ctx).Model(&domain.SongArtist{}).
+		Select("song_artists.artist_id AS id, SUM(songs.play_count) AS total").
+		Joins("JOIN songs ON songs.id = song_artists.song_id").
+		Group("song_artists.artist_id"))
+}
+
+// AlbumPopularity 专辑热度：曲目播放量之和
+func (r *SearchRepo) AlbumPopularity(ctx context.Context) (map[int64]float64, error) {
+	return r.sumPlayCount(ctx, r.db.WithContext(ctx).Model(&domain.Song{}).
+		Select("album_id AS id, SUM(play_count) AS total").
+		Where("album_id > 0").
+		Group("album_id"))
+}
+
+func (r *SearchRepo) sumPlayCount(ctx context.Context, query *gorm.DB) (map[int64]float64, error) {
+	var rows []struct {
+		ID    int64
+		Total float64
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int64]float64, len(rows))
+	for _, row := range rows {
+		result[row.ID] = row.Total
+	}
+	return result, nil
+}
+
+func listUpdatedSince[T any](ctx context.Context, db *gorm.DB, since, afterID int64, limit int) ([]*T, error) {
+	var rows []*T
+	err := db.WithContext(ctx).
+		Where("updated_at >= ? AND id > ?", since, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UploadRepo struct {
+	db *gorm.DB
+}
+
+func NewUploadRepo(db *gorm.DB) *UploadRepo {
+	return &UploadRepo{db: db}
+}
+
+// GetSession 会话不存在时返回 nil, nil
+func (r *UploadRepo) GetSession(ctx context.Context, id string) (*domain.UploadSession, error) {
+	var session domain.UploadSession
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *UploadRepo) CreateSession(ctx context.Context, session *domain.UploadSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// DeleteSession 删除会话及其分片记录
+func (r *UploadRepo) DeleteSession(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("session_id = ?", id).Delete(&domain.UploadChunk{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.UploadSession{}).Error
+	})
+}
+
+// ListExpired 已过期的会话
+func (r *UploadRepo) ListExpired(ctx context.Context, now int64, limit int) ([]*domain.UploadSession, error) {
+	var sessions []*domain.UploadSession
+	err := r.db.WithContext(ctx).Where("expires_at <= ?", now).Order("expires_at").Limit(limit).Find(&sessions).Error
+	return sessions, err
+}
+
+// SaveChunk 记录已接收的分片，重传同一分片时覆盖
+func (r *UploadRepo) SaveChunk(ctx context.Context, chunk *domain.UploadChunk) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}, {Name: "chunk_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"size", "created_at"}),
+	}).Create(chunk).Error
+}
+
+// ListChunks 按序号返回已接收的分片
+func (r *UploadRepo) ListChunks(ctx context.Context, sessionID string) ([]*domain.UploadChunk, error) {
+	var chunks []*domain.UploadChunk
+	err := r.db.WithContext(ctx).Where("session_id = ?", sessionID).Order("chunk_index").Find(&chunks).Error
+	return chunks, err
+}
@@ -0,0 +1,279 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 文档类型
+const (
+	TypeSong     = "song"
+	TypeArtist   = "artist"
+	TypeAlbum    = "album"
+	TypePlaylist = "playlist"
+)
+
+// BM25 参数与排序调节
+const (
+	bm25K1           = 1.2
+	bm25B            = 0.75
+	prefixPenalty    = 0.7  // 前缀匹配相对精确匹配的折扣
+	maxPrefixTerms   = 64   // 单个查询词最多展开的前缀词数
+	popularityWeight = 0.15 // 热度在最终得分中的权重
+)
+
+// Field 参与检索的文本字段，Weight 为字段权重
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Doc 待索引的文档
+type Doc struct {
+	Type       string
+	ID         int64
+	Fields     []Field
+	Popularity float64 // 热度（如播放量），与相关度混合排序
+}
+
+// Hit 检索结果
+type Hit struct {
+	ID    int64
+	Score float64
+}
+
+type docEntry struct {
+	terms      map[string]float64 // 词 -> 加权词频
+	length     float64
+	popularity float64
+}
+
+// typeIndex 单一文档类型的倒排索引
+type typeIndex struct {
+	docs     map[int64]*docEntry
+	postings map[string]map[int64]float64
+	terms    []string // 排序后的词典，用于前缀匹配
+	dirty    bool     // 词典需要重建
+	totalLen float64
+}
+
+func newTypeIndex() *typeIndex {
+	return &typeIndex{
+		docs:     make(map[int64]*docEntry),
+		postings: make(map[string]map[int64]float64),
+	}
+}
+
+// Index 内存倒排索引，支持增量更新，并发安全
+type Index struct {
+	mu    sync.RWMutex
+	types map[string]*typeIndex
+}
+
+func NewIndex() *Index {
+	return &Index{types: make(map[string]*typeIndex)}
+}
+
+// Upsert 新增或替换文档
+func (ix *Index) Upsert(doc Doc) {
+	entry := &docEntry{terms: make(map[string]float64), popularity: doc.Popularity}
+	for _, field := range doc.Fields {
+		for _, term := range IndexTerms(field.Text) {
+			entry.terms[term] += field.Weight
+			entry.length += field.Weight
+		}
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ti := ix.types[doc.Type]
+	if ti == nil {
+		ti = newTypeIndex()
+		ix.types[doc.Type] = ti
+	}
+	ti.remove(doc.ID)
+	ti.docs[doc.ID] = entry
+	ti.totalLen += entry.length
+	for term, tf := range entry.terms {
+		posting := ti.postings[term]
+		if posting == nil {
+			posting = make(map[int64]float64)
+			ti.postings[term] = posting
+			ti.dirty = true
+		}
+		posting[doc.ID] = tf
+	}
+}
+
+// Delete 删除文档
+func (ix *Index) Delete(docType string, id int64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ti := ix.types[docType]; ti != nil {
+		ti.remove(id)
+	}
+}
+
+// Has 文档是否在索引中
+func (ix *Index) Has(docType string, id int64) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if ti := ix.types[docType]; ti != nil {
+		_, ok := ti.docs[id]
+		return ok
+	}
+	return false
+}
+
+// Len 返回某类型的文档数
+func (ix *Index) Len(docType string) int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if ti := ix.types[docType]; ti != nil {
+		return len(ti.docs)
+	}
+	return 0
+}
+
+// Search 检索并按相关度与热度混合排序，返回 [offset, offset+limit) 的结果及总命中数
+func (ix *Index) Search(docType, query string, offset, limit int) ([]Hit, int) {
+	queryTerms := QueryTerms(query)
+	if len(queryTerms) == 0 {
+		return nil, 0
+	}
+
+	ix.mu.Lock()
+	ti := ix.types[docType]
+	if ti != nil && ti.dirty {
+		ti.rebuildTerms()
+	}
+	ix.mu.Unlock()
+	if ti == nil {
+		return nil, 0
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	// 所有查询词都命中的文档优先；没有时依次退化为拆成单字全部命中、任一查询词命中
+	scores := ti.score(queryTerms, true)
+	if len(scores) == 0 {
+		scores = ti.score(looseQueryTerms(query), true)
+	}
+	if len(scores) == 0 {
+		scores = ti.score(queryTerms, false)
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{
+			ID:    id,
+			Score: score * (1 + popularityWeight*math.Log1p(ti.docs[id].popularity)),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	total := len(hits)
+	if offset >= total {
+		return []Hit{}, total
+	}
+	end := min(offset+limit, total)
+	return hits[offset:end], total
+}
+
+// score 计算每个文档的 BM25 得分；requireAll 为 true 时只保留命中全部查询词的文档
+func (ti *typeIndex) score(queryTerms []string, requireAll bool) map[int64]float64 {
+	n := float64(len(ti.docs))
+	if n == 0 {
+		return nil
+	}
+	avgLen := ti.totalLen / n
+
+	var scores map[int64]float64
+	for i, qt := range queryTerms {
+		termScores := make(map[int64]float64)
+		for term, factor := range ti.expand(qt) {
+			posting := ti.postings[term]
+			df := float64(len(posting))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for id, tf := range posting {
+				docLen := ti.docs[id].length
+				s := factor * idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*docLen/avgLen))
+				// 同一查询词的多个展开词取最高分
+				if s > termScores[id] {
+					termScores[id] = s
+				}
+			}
+		}
+
+		if i == 0 {
+			scores = termScores
+			continue
+		}
+		if requireAll {
+			for id := range scores {
+				if _, ok := termScores[id]; !ok {
+					delete(scores, id)
+				}
+			}
+		}
+		for id, s := range termScores {
+			if _, ok := scores[id]; ok || !requireAll {
+				scores[id] += s
+			}
+		}
+	}
+	return scores
+}
+
+// expand 查询词展开为索引中的词：精确匹配加前缀匹配，返回词及其得分系数
+func (ti *typeIndex) expand(queryTerm string) map[string]float64 {
+	expanded := make(map[string]float64)
+	if _, ok := ti.postings[queryTerm]; ok {
+		expanded[queryTerm] = 1
+	}
+	start := sort.SearchStrings(ti.terms, queryTerm)
+	for i := start; i < len(ti.terms) && len(expanded) < maxPrefixTerms; i++ {
+		term := ti.terms[i]
+		if !strings.HasPrefix(term, queryTerm) {
+			break
+		}
+		if term != queryTerm {
+			expanded[term] = prefixPenalty
+		}
+	}
+	return expanded
+}
+
+func (ti *typeIndex) remove(id int64) {
+	entry, ok := ti.docs[id]
+	if !ok {
+		return
+	}
+	for term := range entry.terms {
+		posting := ti.postings[term]
+		delete(posting, id)
+		if len(posting) == 0 {
+			delete(ti.postings, term)
+			ti.dirty = true
+		}
+	}
+	ti.totalLen -= entry.length
+	delete(ti.docs, id)
+}
+
+func (ti *typeIndex) rebuildTerms() {
+	ti.terms = ti.terms[:0]
+	for term := range ti.postings {
+		ti.terms = append(ti.terms, term)
+	}
+	sort.Strings(ti.terms)
+	ti.dirty = false
+}
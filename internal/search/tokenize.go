@@ -0,0 +1,146 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+var pinyinArgs = pinyin.NewArgs()
+
+// Normalize 统一大小写和全角字符
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		// 全角 ASCII 转半角
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		} else if r == 0x3000 {
+			r = ' '
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// segment 连续的汉字或连续的字母数字
+type segment struct {
+	text string
+	han  bool
+}
+
+func segments(s string) []segment {
+	var segs []segment
+	var cur []rune
+	curHan := false
+	flush := func() {
+		if len(cur) > 0 {
+			segs = append(segs, segment{text: string(cur), han: curHan})
+			cur = cur[:0]
+		}
+	}
+	for _, r := range Normalize(s) {
+		switch {
+		case isCJK(r):
+			if !curHan {
+				flush()
+			}
+			curHan = true
+			cur = append(cur, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if curHan {
+				flush()
+			}
+			curHan = false
+			cur = append(cur, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return segs
+}
+
+// IndexTerms 生成索引词：汉字的单字与二元组、字母数字单词，以及汉字的全拼、逐字拼音和首字母
+func IndexTerms(s string) []string {
+	var terms []string
+	for _, seg := range segments(s) {
+		if !seg.han {
+			terms = append(terms, seg.text)
+			continue
+		}
+		runes := []rune(seg.text)
+		for i, r := range runes {
+			terms = append(terms, string(r))
+			if i+1 < len(runes) {
+				terms = append(terms, string(runes[i:i+2]))
+			}
+		}
+		terms = append(terms, pinyinTerms(seg.text)...)
+	}
+	return terms
+}
+
+// QueryTerms 生成查询词：多字汉字串只用二元组，单字用单字
+func QueryTerms(s string) []string {
+	var terms []string
+	for _, seg := range segments(s) {
+		if !seg.han {
+			terms = append(terms, seg.text)
+			continue
+		}
+		runes := []rune(seg.text)
+		if len(runes) == 1 {
+			terms = append(terms, seg.text)
+			continue
+		}
+		for i := 0; i+1 < len(runes); i++ {
+			terms = append(terms, string(runes[i:i+2]))
+		}
+	}
+	return terms
+}
+
+// pinyinTerms 汉字串的全拼、逐字拼音和首字母，例如 周杰伦 -> zhoujielun, zhou, jie, lun, zjl
+func pinyinTerms(han string) []string {
+	syllables := pinyinSyllables(han)
+	if len(syllables) == 0 {
+		return nil
+	}
+	terms := make([]string, 0, len(syllables)+2)
+	var initials strings.Builder
+	for _, syl := range syllables {
+		terms = append(terms, syl)
+		initials.WriteByte(syl[0])
+	}
+	if len(syllables) > 1 {
+		terms = append(terms, strings.Join(syllables, ""), initials.String())
+	}
+	return terms
+}
+
+// pinyinSyllables 逐字拼音，无法转换的字被跳过
+func pinyinSyllables(han string) []string {
+	return pinyin.LazyPinyin(han, pinyinArgs)
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// looseQueryTerms 宽松查询词：汉字拆为单字，用于二元组无结果时兜底
+func looseQueryTerms(s string) []string {
+	var terms []string
+	for _, seg := range segments(s) {
+		if !seg.han {
+			terms = append(terms, seg.text)
+			continue
+		}
+		for _, r := range seg.text {
+			terms = append(terms, string(r))
+		}
+	}
+	return terms
+}
@@ -0,0 +1,140 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Suggestion 联想词条
+type Suggestion struct {
+	Type   string
+	ID     int64
+	Text   string
+	Weight float64
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	top      []*Suggestion // 以该节点为前缀的权重最高的若干词条
+}
+
+// Trie 前缀树，每个节点预存 topK 个词条，查询只需沿前缀走到对应节点。
+// 词条只增不删，改名后的旧前缀在下次全量重建时清除。
+type Trie struct {
+	mu   sync.RWMutex
+	root *trieNode
+	topK int
+}
+
+func NewTrie(topK int) *Trie {
+	return &Trie{root: &trieNode{}, topK: topK}
+}
+
+// Insert 按词条文本、拼音全拼和首字母插入，同一类型同一 ID 重复插入时覆盖
+func (t *Trie) Insert(s Suggestion) {
+	keys := SuggestKeys(s.Text)
+	if len(keys) == 0 {
+		return
+	}
+	entry := &s
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, key := range keys {
+		node := t.root
+		for _, r := range key {
+			child := node.children[r]
+			if child == nil {
+				if node.children == nil {
+					node.children = make(map[rune]*trieNode)
+				}
+				child = &trieNode{}
+				node.children[r] = child
+			}
+			node = child
+			node.top = t.offer(node.top, entry)
+		}
+	}
+}
+
+// offer 将词条放入按权重降序的 top 列表
+func (t *Trie) offer(top []*Suggestion, entry *Suggestion) []*Suggestion {
+	for i, cur := range top {
+		if cur.Type == entry.Type && cur.ID == entry.ID {
+			top = append(top[:i], top[i+1:]...)
+			break
+		}
+	}
+	i := sort.Search(len(top), func(i int) bool { return top[i].Weight < entry.Weight })
+	if i >= t.topK {
+		return top
+	}
+	top = append(top, nil)
+	copy(top[i+1:], top[i:])
+	top[i] = entry
+	if len(top) > t.topK {
+		top = top[:t.topK]
+	}
+	return top
+}
+
+// Suggest 返回以 prefix 开头的词条，最多 limit 个
+func (t *Trie) Suggest(prefix string, limit int) []Suggestion {
+	key := suggestKey(prefix)
+	if key == "" {
+		return nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	node := t.root
+	for _, r := range key {
+		node = node.children[r]
+		if node == nil {
+			return nil
+		}
+	}
+	n := min(limit, len(node.top))
+	result := make([]Suggestion, n)
+	for i := range n {
+		result[i] = *node.top[i]
+	}
+	return result
+}
+
+// SuggestKeys 联想使用的前缀键：原文、全拼和首字母，均去掉空白与标点，
+// 例如 晴天 -> 晴天, qingtian, qt
+func SuggestKeys(text string) []string {
+	segs := segments(text)
+	if len(segs) == 0 {
+		return nil
+	}
+	var plain, full, initials strings.Builder
+	hasHan := false
+	for _, seg := range segs {
+		plain.WriteString(seg.text)
+		if !seg.han {
+			full.WriteString(seg.text)
+			initials.WriteString(seg.text)
+			continue
+		}
+		hasHan = true
+		for _, syl := range pinyinSyllables(seg.text) {
+			full.WriteString(syl)
+			initials.WriteByte(syl[0])
+		}
+	}
+	keys := []string{plain.String()}
+	if hasHan {
+		keys = append(keys, full.String(), initials.String())
+	}
+	return keys
+}
+
+// suggestKey 用户输入的前缀统一为与 SuggestKeys 相同的形式
+func suggestKey(prefix string) string {
+	var b strings.Builder
+	for _, seg := range segments(prefix) {
+		b.WriteString(seg.text)
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+	"wyy/internal/repo/catalog"
+)
+
+var ErrAlbumNotFound = errors.New("album not found")
+
+// AlbumDetail 专辑页：专辑信息、主歌手和曲目列表
+type AlbumDetail struct {
+	Album  *domain.Album
+	Artist *domain.Artist // 主歌手，可能为空
+	Tracks []*domain.Song
+}
+
+type AlbumService struct {
+	albumRepo  *repo.AlbumRepo
+	artistRepo *repo.ArtistRepo
+	songRepo   *repo.SongRepo
+}
+
+func NewAlbumService(albumRepo *repo.AlbumRepo, artistRepo *repo.ArtistRepo, songRepo *repo.SongRepo) *AlbumService {
+	return &AlbumService{
+		albumRepo:  albumRepo,
+		artistRepo: artistRepo,
+		songRepo:   songRepo,
+	}
+}
+
+// GetAlbumDetail 获取专辑详情，曲目按碟片和曲目序号排列
+func (s *AlbumService) GetAlbumDetail(ctx context.Context, id int64) (*AlbumDetail, error) {
+	album, err := s.albumRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if album == nil {
+		return nil, ErrAlbumNotFound
+	}
+	artist, err := s.artistRepo.GetByID(ctx, album.ArtistID)
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := s.songRepo.ListByAlbum(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, tracks); err != nil {
+		return nil, err
+	}
+	return &AlbumDetail{Album: album, Artist: artist, Tracks: tracks}, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+	"wyy/internal/repo/catalog"
+)
+
+var ErrArtistNotFound = errors.New("artist not found")
+
+// 歌手页展示的热门歌曲和专辑数量
+const (
+	artistTopSongs = 50
+	artistAlbums   = 50
+)
+
+// ArtistDetail 歌手页：歌手信息、热门歌曲和专辑
+type ArtistDetail struct {
+	Artist   *domain.Artist
+	TopSongs []*domain.Song
+	Albums   []*domain.Album
+}
+
+type ArtistService struct {
+	artistRepo *repo.ArtistRepo
+	albumRepo  *repo.AlbumRepo
+	songRepo   *repo.SongRepo
+}
+
+func NewArtistService(artistRepo *repo.ArtistRepo, albumRepo *repo.AlbumRepo, songRepo *repo.SongRepo) *ArtistService {
+	return &ArtistService{
+		artistRepo: artistRepo,
+		albumRepo:  albumRepo,
+		songRepo:   songRepo,
+	}
+}
+
+// GetArtistDetail 获取歌手详情
+func (s *ArtistService) GetArtistDetail(ctx context.Context, id int64) (*ArtistDetail, error) {
+	artist, err := s.artistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if artist == nil {
+		return nil, ErrArtistNotFound
+	}
+	topSongs, err := s.songRepo.TopByArtist(ctx, id, artistTopSongs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, topSongs); err != nil {
+		return nil, err
+	}
+	albums, err := s.albumRepo.ListByArtist(ctx, id, artistAlbums)
+	if err != nil {
+		return nil, err
+	}
+	return &ArtistDetail{Artist: artist, TopSongs: topSongs, Albums: albums}, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wyy/internal/domain"
+	"wyy/internal/lrc"
+	"wyy/internal/repo/catalog"
+)
+
+var (
+	ErrLyricNotFound = errors.New("lyric not found")
+	ErrInvalidLyric  = errors.New("invalid lyric")
+)
+
+// maxLyricSize 单份歌词文本的上限（字节）
+const maxLyricSize = 256 << 10
+
+// LyricLine 一行带时间轴的歌词，Time 为毫秒
+type LyricLine struct {
+	Time        int64
+	Text        string
+	Translation string
+}
+
+// LyricResult 播放器使用的歌词。Synced 为 true 时 Lines 有值，否则只有 Plain
+type LyricResult struct {
+	SongID         int64
+	Synced         bool
+	Lines          []LyricLine
+	Plain          string
+	HasTranslation bool
+	Contributor    string
+	UpdatedAt      int64
+}
+
+// LyricInput 保存歌词的参数，nil 字段保留原值
+type LyricInput struct {
+	Plain         *string
+	LRC           *string
+	TranslatedLRC *string
+	Contributor   *string
+}
+
+type LyricService struct {
+	lyricRepo *repo.LyricRepo
+	songRepo  *repo.SongRepo
+}
+
+func NewLyricService(lyricRepo *repo.LyricRepo, songRepo *repo.SongRepo) *LyricService {
+	return &LyricService{lyricRepo: lyricRepo, songRepo: songRepo}
+}
+
+// GetLyric 解析后的歌词，翻译按时间对齐到原文各行
+func (s *LyricService) GetLyric(ctx context.Context, songID int64) (*LyricResult, error) {
+	lyric, err := s.GetRaw(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+	result := &LyricResult{
+		SongID:      songID,
+		Plain:       lyric.Plain,
+		Contributor: lyric.Contributor,
+		UpdatedAt:   lyric.UpdatedAt,
+	}
+	if lyric.LRC == "" {
+		return result, nil
+	}
+	// 保存时已校验，解析失败说明数据被直接改过，退化为纯文本
+	parsed, err := lrc.Parse(lyric.LRC)
+	if err != nil {
+		if result.Plain == "" {
+			result.Plain = lyric.LRC
+		}
+		return result, nil
+	}
+	result.Synced = true
+	result.Lines = make([]LyricLine, len(parsed.Lines))
+	for i, line := range parsed.Lines {
+		result.Lines[i] = LyricLine{Time: line.Time, Text: line.Text}
+	}
+	if lyric.TranslatedLRC != "" {
+		if translated, err := lrc.Parse(lyric.TranslatedLRC); err == nil {
+			result.HasTranslation = alignTranslation(result.Lines, translated.Lines)
+		}
+	}
+	if result.Plain == "" {
+		texts := make([]string, 0, len(parsed.Lines))
+		for _, line := range parsed.Lines {
+			texts = append(texts, line.Text)
+		}
+		result.Plain = strings.Join(texts, "\n")
+	}
+	return result, nil
+}
+
+// GetRaw 原始歌词文本，供管理后台编辑
+func (s *LyricService) GetRaw(ctx context.Context, songID int64) (*domain.Lyric, error) {
+	lyric, err := s.lyricRepo.GetBySongID(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+	if lyric == nil {
+		return nil, ErrLyricNotFound
+	}
+	return lyric, nil
+}
+
+// SaveLyric 校验后保存歌词，LRC 和翻译不合法时返回 ErrInvalidLyric；只能为曲库歌曲保存歌词
+func (s *LyricService) SaveLyric(ctx context.Context, songID int64, input LyricInput) (*domain.Lyric, error) {
+	song, err := s.songRepo.GetByID(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+	if song == nil || song.OwnerID != 0 {
+		return nil, ErrSongNotFound
+	}
+	lyric, err := s.lyricRepo.GetBySongID(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if lyric == nil {
+		lyric = &domain.Lyric{SongID: songID, CreatedAt: now}
+	}
+	setIfPresent(&lyric.Plain, input.Plain)
+	setIfPresent(&lyric.LRC, input.LRC)
+	setIfPresent(&lyric.TranslatedLRC, input.TranslatedLRC)
+	setIfPresent(&lyric.Contributor, input.Contributor)
+
+	if lyric.Plain == "" && lyric.LRC == "" {
+		return nil, fmt.Errorf("%w: plain or lrc is required", ErrInvalidLyric)
+	}
+	if lyric.TranslatedLRC != "" && lyric.LRC == "" {
+		return nil, fmt.Errorf("%w: translation requires lrc", ErrInvalidLyric)
+	}
+	if max(len(lyric.Plain), len(lyric.LRC), len(lyric.TranslatedLRC)) > maxLyricSize {
+		return nil, fmt.Errorf("%w: lyric too large", ErrInvalidLyric)
+	}
+	if lyric.LRC != "" {
+		if err := lrc.Validate(lyric.LRC); err != nil {
+			return nil, fmt.Errorf("%w: lrc: %v", ErrInvalidLyric, err)
+		}
+	}
+	if lyric.TranslatedLRC != "" {
+		if err := lrc.Validate(lyric.TranslatedLRC); err != nil {
+			return nil, fmt.Errorf("%w: translation: %v", ErrInvalidLyric, err)
+		}
+	}
+	lyric.UpdatedAt = now
+	if err := s.lyricRepo.Save(ctx, lyric); err != nil {
+		return nil, err
+	}
+	return lyric, nil
+}
+
+// DeleteLyric 删除歌词
+func (s *LyricService) DeleteLyric(ctx context.Context, songID int64) error {
+	ok, err := s.lyricRepo.Delete(ctx, songID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLyricNotFound
+	}
+	return nil
+}
+
+// alignTranslation 把翻译填入时间相同的原文行；一行都对不上但行数相同时按顺序对应。返回是否有翻译被填入
+func alignTranslation(lines []LyricLine, translated []lrc.Line) bool {
+	byTime := make(map[int64]string, len(translated))
+	for _, line := range translated {
+		if _, ok := byTime[line.Time]; !ok {
+			byTime[line.Time] = line.Text
+		}
+	}
+	matched := false
+	for i := range lines {
+		if text, ok := byTime[lines[i].Time]; ok && text != "" {
+			lines[i].Translation = text
+			matched = true
+		}
+	}
+	if !matched && len(lines) == len(translated) {
+		for i := range lines {
+			lines[i].Translation = translated[i].Text
+			matched = matched || translated[i].Text != ""
+		}
+	}
+	return matched
+}
+
+func setIfPresent(dst *string, src *string) {
+	if src != nil {
+		*dst = strings.TrimSpace(*src)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"wyy/internal/domain"
+	"wyy/internal/repo/catalog"
+)
+
+var ErrSongNotFound = errors.New("song not found")
+
+// MaxBatchSongs 批量查询的上限
+const MaxBatchSongs = 100
+
+type SongService struct {
+	songRepo *repo.SongRepo
+}
+
+func NewSongService(songRepo *repo.SongRepo) *SongService {
+	return &SongService{songRepo: songRepo}
+}
+
+// GetSong 获取歌曲详情；云盘私有歌曲只有上传者可见，viewerID 为 0 表示未登录
+func (s *SongService) GetSong(ctx context.Context, viewerID, id int64) (*domain.Song, error) {
+	song, err := s.songRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if song == nil || (song.OwnerID != 0 && song.OwnerID != viewerID) {
+		return nil, ErrSongNotFound
+	}
+	if err := s.songRepo.LoadArtists(ctx, []*domain.Song{song}); err != nil {
+		return nil, err
+	}
+	return song, nil
+}
+
+// GetSongs 批量获取歌曲详情，按 ids 顺序返回，不存在或对 viewerID 不可见的 ID 被忽略
+func (s *SongService) GetSongs(ctx context.Context, viewerID int64, ids []int64) ([]*domain.Song, error) {
+	if len(ids) > MaxBatchSongs {
+		ids = ids[:MaxBatchSongs]
+	}
+	songs, err := s.songRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, songs); err != nil {
+		return nil, err
+	}
+	songMap := make(map[int64]*domain.Song, len(songs))
+	for _, song := range songs {
+		if song.OwnerID == 0 || song.OwnerID == viewerID {
+			songMap[song.ID] = song
+		}
+	}
+	ordered := make([]*domain.Song, 0, len(ids))
+	for _, id := range ids {
+		if song, ok := songMap[id]; ok {
+			ordered = append(ordered, song)
+			delete(songMap, id) // 重复的 ID 只返回一次
+		}
+	}
+	return ordered, nil
+}
+
+// ListSongs 分页查询歌曲，分页参数由调用方校验
+func (s *SongService) ListSongs(ctx context.Context, filter domain.SongFilter) ([]*domain.Song, int64, error) {
+	songs, total, err := s.songRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, songs); err != nil {
+		return nil, 0, err
+	}
+	return songs, total, nil
+}
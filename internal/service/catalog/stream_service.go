@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"wyy/internal/domain"
+	"wyy/internal/repo/catalog"
+	cloud "wyy/internal/repo/cloud"
+	"wyy/internal/storage"
+)
+
+var (
+	ErrSongUnavailable = errors.New("song unavailable")
+	ErrNoAudio         = errors.New("no audio file for song")
+	ErrInvalidQuality  = errors.New("invalid quality")
+)
+
+// Stream 打开的音频流，调用方负责关闭 Reader
+type Stream struct {
+	File   *domain.SongFile
+	Object *storage.Object
+	Reader io.ReadSeekCloser
+}
+
+// StreamService 按音质选择歌曲的音频文件并从存储读取。曲库没有可播放的文件时，使用用户云盘中的同一首歌
+type StreamService struct {
+	songRepo     *repo.SongRepo
+	songFileRepo *repo.SongFileRepo
+	cloudRepo    *cloud.CloudRepo
+	storage      storage.Storage
+}
+
+func NewStreamService(songRepo *repo.SongRepo, songFileRepo *repo.SongFileRepo, cloudRepo *cloud.CloudRepo, store storage.Storage) *StreamService {
+	return &StreamService{songRepo: songRepo, songFileRepo: songFileRepo, cloudRepo: cloudRepo, storage: store}
+}
+
+// Open 打开歌曲音频：优先使用请求的音质，没有时依次降级，仍没有时使用更高音质；userID 为 0 表示游客
+func (s *StreamService) Open(ctx context.Context, songID int64, quality string, userID int64) (*Stream, error) {
+	file, err := s.SelectFile(ctx, songID, quality, userID)
+	if err != nil {
+		return nil, err
+	}
+	reader, obj, err := s.storage.Open(ctx, file.Key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, ErrNoAudio
+	}
+	if err != nil {
+		return nil, err
+	}
+	if file.MimeType != "" {
+		obj.ContentType = file.MimeType
+	}
+	return &Stream{File: file, Object: obj, Reader: reader}, nil
+}
+
+// ListFiles 返回歌曲的全部音频文件，按音质从低到高排列
+func (s *StreamService) ListFiles(ctx context.Context, songID, userID int64) ([]*domain.SongFile, error) {
+	files, err := s.files(ctx, songID, userID)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(files, func(a, b *domain.SongFile) int {
+		return slices.Index(domain.Qualities, a.Quality) - slices.Index(domain.Qualities, b.Quality)
+	})
+	return files, nil
+}
+
+// SelectFile 选择歌曲在指定音质下实际使用的音频文件
+func (s *StreamService) SelectFile(ctx context.Context, songID int64, quality string, userID int64) (*domain.SongFile, error) {
+	want := slices.Index(domain.Qualities, quality)
+	if want < 0 {
+		return nil, ErrInvalidQuality
+	}
+	files, err := s.files(ctx, songID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pickQuality(files, want), nil
+}
+
+// files 曲库歌曲可播放时返回曲库音频文件，否则返回 userID 云盘中的文件；
+// 其他用户的云盘私有歌曲视为不存在
+func (s *StreamService) files(ctx context.Context, songID, userID int64) ([]*domain.SongFile, error) {
+	song, err := s.songRepo.GetByID(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+	if song == nil || (song.OwnerID != 0 && song.OwnerID != userID) {
+		return nil, ErrSongNotFound
+	}
+	unplayable := ErrNoAudio
+	if song.Unavailable {
+		unplayable = ErrSongUnavailable
+	} else if song.OwnerID == 0 {
+		files, err := s.songFileRepo.ListBySong(ctx, songID)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) > 0 {
+			return files, nil
+		}
+	}
+	if userID == 0 {
+		return nil, unplayable
+	}
+	cloudSong, err := s.cloudRepo.GetByUserAndSong(ctx, userID, songID)
+	if err != nil {
+		return nil, err
+	}
+	if cloudSong == nil {
+		return nil, unplayable
+	}
+	return []*domain.SongFile{{
+		SongID:   songID,
+		Quality:  cloudSong.Quality,
+		Key:      cloudSong.Key,
+		Format:   cloudSong.Format,
+		MimeType: cloudSong.MimeType,
+		Bitrate:  cloudSong.Bitrate,
+		Size:     cloudSong.Size,
+	}}, nil
+}
+
+// pickQuality 选择不高于 want 的最高音质，都没有时选择高于 want 的最低音质
+func pickQuality(files []*domain.SongFile, want int) *domain.SongFile {
+	byQuality := make(map[string]*domain.SongFile, len(files))
+	for _, f := range files {
+		byQuality[f.Quality] = f
+	}
+	for i := want; i >= 0; i-- {
+		if f, ok := byQuality[domain.Qualities[i]]; ok {
+			return f
+		}
+	}
+	for i := want + 1; i < len(domain.Qualities); i++ {
+		if f, ok := byQuality[domain.Qualities[i]]; ok {
+			return f
+		}
+	}
+	return files[0]
+}
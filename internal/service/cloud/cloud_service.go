@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"wyy/internal/audiotag"
+	"wyy/internal/domain"
+	catalog "wyy/internal/repo/catalog"
+	repo "wyy/internal/repo/cloud"
+	media "wyy/internal/service/media"
+	upload "wyy/internal/service/upload"
+	"wyy/internal/storage"
+)
+
+var (
+	ErrCloudSongNotFound = errors.New("cloud song not found")
+	ErrQuotaExceeded     = repo.ErrQuotaExceeded
+)
+
+const (
+	matchCandidates   = 20 // 按歌名取出的曲库候选数
+	matchDurationDiff = 5  // 与曲库歌曲时长相差不超过该秒数才认为是同一首
+)
+
+// CloudSong 云盘歌曲及其对应的曲库或私有歌曲
+type CloudSong struct {
+	*domain.CloudSong
+	Song *domain.Song
+}
+
+// CloudUsage 云盘用量
+type CloudUsage struct {
+	UsedBytes  int64
+	QuotaBytes int64
+	SongCount  int
+}
+
+// CloudService 用户云盘：上传的歌曲按指纹匹配曲库，匹配不到时创建只对上传者可见的私有歌曲
+type CloudService struct {
+	cloudRepo    *repo.CloudRepo
+	songRepo     *catalog.SongRepo
+	imageService *media.ImageService
+	storage      storage.Storage
+	quota        int64
+}
+
+func NewCloudService(cloudRepo *repo.CloudRepo, songRepo *catalog.SongRepo, imageService *media.ImageService, store storage.Storage, quota int64) *CloudService {
+	if quota <= 0 {
+		quota = 10 << 30
+	}
+	return &CloudService{cloudRepo: cloudRepo, songRepo: songRepo, imageService: imageService, storage: store, quota: quota}
+}
+
+// Usage 用户云盘用量
+func (s *CloudService) Usage(ctx context.Context, userID int64) (*CloudUsage, error) {
+	usage, err := s.cloudRepo.Usage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudUsage{UsedBytes: usage.UsedBytes, QuotaBytes: s.quota, SongCount: usage.SongCount}, nil
+}
+
+// List 按上传时间倒序分页
+func (s *CloudService) List(ctx context.Context, userID int64, page, pageSize int) ([]*CloudSong, int64, error) {
+	rows, total, err := s.cloudRepo.List(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	songIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		songIDs = append(songIDs, row.SongID)
+	}
+	songs, err := s.songRepo.GetByIDs(ctx, songIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, songs); err != nil {
+		return nil, 0, err
+	}
+	songMap := make(map[int64]*domain.Song, len(songs))
+	for _, song := range songs {
+		songMap[song.ID] = song
+	}
+	result := make([]*CloudSong, 0, len(rows))
+	for _, row := range rows {
+		if song, ok := songMap[row.SongID]; ok {
+			result = append(result, &CloudSong{CloudSong: row, Song: song})
+		}
+	}
+	return result, total, nil
+}
+
+// Upload 上传一首歌到云盘。同一用户再次上传同一首歌时替换原文件
+func (s *CloudService) Upload(ctx context.Context, userID int64, r io.ReadSeeker, size int64, fileName string) (*CloudSong, error) {
+	usage, err := s.cloudRepo.Usage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// 先粗略检查，避免写入存储后才发现超额；准确的检查在保存时加锁进行
+	if usage.UsedBytes+size > s.quota {
+		return nil, ErrQuotaExceeded
+	}
+	meta, err := upload.ReadAudio(r)
+	if err != nil {
+		return nil, err
+	}
+	name := firstNonEmpty(meta.Title, strings.TrimSuffix(fileName, path.Ext(fileName)))
+	artists := upload.SplitArtists(firstNonEmpty(meta.Artist, meta.AlbumArtist))
+	fingerprint := Fingerprint(name, artists)
+	duration := int(meta.Duration.Round(time.Second) / time.Second)
+
+	song, err := s.match(ctx, name, fingerprint, duration)
+	if err != nil {
+		return nil, err
+	}
+	matched := song != nil
+	if !matched {
+		// 未匹配时复用此前上传同一首歌创建的私有歌曲
+		if song, err = s.privateSong(ctx, userID, fingerprint); err != nil {
+			return nil, err
+		}
+	}
+
+	obj, err := upload.StoreAudio(ctx, s.storage, fmt.Sprintf("cloud/%d/", userID), r, size, meta)
+	if err != nil {
+		return nil, err
+	}
+	// 新的私有歌曲与云盘记录在同一事务中创建，超额时不留下歌曲记录
+	var private *domain.Song
+	if song == nil {
+		private = &domain.Song{
+			Name:     name,
+			Artist:   strings.Join(artists, " / "),
+			Album:    meta.Album,
+			DiscNo:   max(meta.DiscNo, 1),
+			TrackNo:  meta.TrackNo,
+			Duration: duration,
+			OwnerID:  userID,
+		}
+		song = private
+	}
+
+	cloudSong := &domain.CloudSong{
+		UserID:      userID,
+		SongID:      song.ID,
+		Matched:     matched,
+		Fingerprint: fingerprint,
+		FileName:    fileName,
+		Key:         obj.Key,
+		Format:      meta.Format,
+		MimeType:    obj.ContentType,
+		Quality:     upload.QualityOf(meta),
+		Bitrate:     meta.Bitrate,
+		Size:        size,
+		CreatedAt:   time.Now().Unix(),
+	}
+	replaced, err := s.cloudRepo.Add(ctx, cloudSong, private, s.quota)
+	if err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			s.storage.Delete(ctx, obj.Key)
+		}
+		return nil, err
+	}
+	if private != nil {
+		// 封面在保存成功后再上传，超额的上传不会留下封面
+		if private.CoverURL, err = s.saveCover(ctx, meta.Picture); err != nil {
+			return nil, err
+		}
+		if private.CoverColor, err = s.imageService.CoverColor(ctx, private.CoverURL); err != nil {
+			return nil, err
+		}
+		if private.CoverURL != "" {
+			if err := s.songRepo.Update(ctx, private); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if replaced != nil && replaced.Key != cloudSong.Key {
+		if err := s.storage.Delete(ctx, replaced.Key); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.songRepo.LoadArtists(ctx, []*domain.Song{song}); err != nil {
+		return nil, err
+	}
+	return &CloudSong{CloudSong: cloudSong, Song: song}, nil
+}
+
+// Delete 从云盘删除歌曲。私有歌曲同时下架，已加入歌单和播放历史的记录保留为不可播放
+func (s *CloudService) Delete(ctx context.Context, userID, id int64) error {
+	cloudSong, err := s.cloudRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if cloudSong == nil || cloudSong.UserID != userID {
+		return ErrCloudSongNotFound
+	}
+	if err := s.cloudRepo.Delete(ctx, cloudSong); err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, cloudSong.Key); err != nil {
+		return err
+	}
+	if cloudSong.Matched {
+		return nil
+	}
+	song, err := s.songRepo.GetByID(ctx, cloudSong.SongID)
+	if err != nil || song == nil {
+		return err
+	}
+	song.Unavailable = true
+	return s.songRepo.Update(ctx, song)
+}
+
+// match 在曲库中查找歌名相同、指纹相同且时长相近的歌曲，找不到时返回 nil
+func (s *CloudService) match(ctx context.Context, name, fingerprint string, duration int) (*domain.Song, error) {
+	candidates, err := s.songRepo.ListByName(ctx, name, matchCandidates)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, candidates); err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		names := make([]string, 0, len(candidate.Artists))
+		for _, artist := range candidate.Artists {
+			names = append(names, artist.Name)
+		}
+		if Fingerprint(candidate.Name, names) != fingerprint {
+			continue
+		}
+		if candidate.Duration > 0 && abs(candidate.Duration-duration) > matchDurationDiff {
+			continue
+		}
+		return candidate, nil
+	}
+	return nil, nil
+}
+
+// privateSong 用户此前上传同一首歌时创建、仍未下架的私有歌曲
+func (s *CloudService) privateSong(ctx context.Context, userID int64, fingerprint string) (*domain.Song, error) {
+	existing, err := s.cloudRepo.GetByFingerprint(ctx, userID, fingerprint)
+	if err != nil || existing == nil || existing.Matched {
+		return nil, err
+	}
+	song, err := s.songRepo.GetByID(ctx, existing.SongID)
+	if err != nil || song == nil || song.Unavailable {
+		return nil, err
+	}
+	return song, nil
+}
+
+// saveCover 保存内嵌封面，图片无法识别时忽略
+func (s *CloudService) saveCover(ctx context.Context, pic *audiotag.Picture) (string, error) {
+	if pic == nil {
+		return "", nil
+	}
+	obj, err := s.imageService.Upload(ctx, bytes.NewReader(pic.Data))
+	if errors.Is(err, media.ErrInvalidImage) || errors.Is(err, media.ErrImageTooLarge) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return media.ImageURL(obj.Key), nil
+}
+
+// Fingerprint 由歌名和第一位歌手生成指纹，忽略大小写、空白和标点，用于匹配曲库
+func Fingerprint(name string, artists []string) string {
+	artist := ""
+	if len(artists) > 0 {
+		artist = artists[0]
+	}
+	sum := sha256.Sum256([]byte(normalize(name) + "\x00" + normalize(artist)))
+	return hex.EncodeToString(sum[:16])
+}
+
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		// 全角字符转半角
+		if r >= '！' && r <= '～' {
+			r -= '！' - '!'
+		}
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"wyy/internal/domain"
+	catalog "wyy/internal/repo/catalog"
+	repo "wyy/internal/repo/comment"
+	playlist "wyy/internal/repo/playlist"
+)
+
+var (
+	ErrInvalidComment   = errors.New("invalid comment")
+	ErrInvalidTarget    = errors.New("invalid comment target type")
+	ErrTargetNotFound   = errors.New("comment target not found")
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentForbidden = errors.New("comment belongs to another user")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSort      = errors.New("invalid comment sort")
+)
+
+const maxCommentLength = 1000
+
+// CommentView 评论及当前用户是否已点赞
+type CommentView struct {
+	*domain.Comment
+	Liked bool
+}
+
+// CommentPage 一页评论
+type CommentPage struct {
+	Total      int64 // 目标的可见评论总数（含回复），仅顶层评论列表返回
+	Comments   []*CommentView
+	NextCursor string // 为空表示没有更多
+}
+
+type CommentService struct {
+	commentRepo  *repo.CommentRepo
+	songRepo     *catalog.SongRepo
+	albumRepo    *catalog.AlbumRepo
+	playlistRepo *playlist.PlaylistRepo
+}
+
+func NewCommentService(commentRepo *repo.CommentRepo, songRepo *catalog.SongRepo, albumRepo *catalog.AlbumRepo, playlistRepo *playlist.PlaylistRepo) *CommentService {
+	return &CommentService{
+		commentRepo:  commentRepo,
+		songRepo:     songRepo,
+		albumRepo:    albumRepo,
+		playlistRepo: playlistRepo,
+	}
+}
+
+// Post 发布评论；replyToID 不为 0 时作为回复挂到对应的顶层评论下
+func (s *CommentService) Post(ctx context.Context, userID int64, targetType string, targetID int64, content string, replyToID int64) (*domain.Comment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > maxCommentLength {
+		return nil, fmt.Errorf("%w: content must be 1-%d characters", ErrInvalidComment, maxCommentLength)
+	}
+	comment := &domain.Comment{
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     userID,
+		Content:    content,
+		CreatedAt:  time.Now().Unix(),
+	}
+
+	if err := s.checkTarget(ctx, userID, targetType, targetID); err != nil {
+		return nil, err
+	}
+	if replyToID > 0 {
+		replyTo, err := s.visible(ctx, replyToID)
+		if err != nil {
+			return nil, err
+		}
+		if replyTo.TargetType != targetType || replyTo.TargetID != targetID {
+			return nil, fmt.Errorf("%w: reply target belongs to another %s", ErrInvalidComment, replyTo.TargetType)
+		}
+		comment.ParentID = replyTo.ID
+		if replyTo.ParentID > 0 {
+			// 回复楼中楼时仍挂在顶层评论下，顶层评论必须可见
+			if _, err := s.visible(ctx, replyTo.ParentID); err != nil {
+				return nil, err
+			}
+			comment.ParentID = replyTo.ParentID
+		}
+		comment.ReplyToID = replyTo.ID
+		comment.ReplyToUserID = replyTo.UserID
+	}
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// List 顶层评论列表，sort 为 hot（按点赞数）或 latest（按时间）
+func (s *CommentService) List(ctx context.Context, userID int64, targetType string, targetID int64, sort, cursor string, limit int) (*CommentPage, error) {
+	if sort != repo.SortHot && sort != repo.SortLatest {
+		return nil, ErrInvalidSort
+	}
+	if err := s.checkTarget(ctx, userID, targetType, targetID); err != nil {
+		return nil, err
+	}
+	after, err := parseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.commentRepo.List(ctx, targetType, targetID, sort, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.commentRepo.Count(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.page(ctx, userID, comments, limit, func(c *domain.Comment) int64 {
+		if sort == repo.SortHot {
+			return c.LikeCount
+		}
+		return c.CreatedAt
+	})
+	if err != nil {
+		return nil, err
+	}
+	page.Total = total
+	return page, nil
+}
+
+// Replies 顶层评论下的回复，按时间正序
+func (s *CommentService) Replies(ctx context.Context, userID, commentID int64, cursor string, limit int) (*CommentPage, error) {
+	parent, err := s.visible(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.ParentID > 0 {
+		return nil, fmt.Errorf("%w: replies are listed under the top-level comment", ErrInvalidComment)
+	}
+	after, err := parseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := s.commentRepo.ListReplies(ctx, commentID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.page(ctx, userID, replies, limit, func(c *domain.Comment) int64 { return c.CreatedAt })
+}
+
+// Delete 作者删除自己的评论
+func (s *CommentService) Delete(ctx context.Context, userID, commentID int64) error {
+	comment, err := s.visible(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return ErrCommentForbidden
+	}
+	_, err = s.commentRepo.SetStatus(ctx, commentID, domain.CommentDeleted)
+	return err
+}
+
+// Moderate 管理员屏蔽（removed 为 true）或恢复评论；作者已删除的评论不能恢复
+func (s *CommentService) Moderate(ctx context.Context, commentID int64, removed bool) error {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment == nil || comment.Status == domain.CommentDeleted {
+		return ErrCommentNotFound
+	}
+	status := int8(domain.CommentNormal)
+	if removed {
+		status = domain.CommentRemoved
+	}
+	_, err = s.commentRepo.SetStatus(ctx, commentID, status)
+	return err
+}
+
+// Like 点赞评论，重复点赞不报错
+func (s *CommentService) Like(ctx context.Context, userID, commentID int64) error {
+	if _, err := s.visible(ctx, commentID); err != nil {
+		return err
+	}
+	_, err := s.commentRepo.Like(ctx, &domain.CommentLike{CommentID: commentID, UserID: userID, CreatedAt: time.Now().Unix()})
+	return err
+}
+
+// Unlike 取消点赞，未点赞时不报错
+func (s *CommentService) Unlike(ctx context.Context, userID, commentID int64) error {
+	_, err := s.commentRepo.Unlike(ctx, commentID, userID)
+	return err
+}
+
+// page 组装分页结果并标记当前用户已点赞的评论
+func (s *CommentService) page(ctx context.Context, userID int64, comments []*domain.Comment, limit int, keyOf func(*domain.Comment) int64) (*CommentPage, error) {
+	ids := make([]int64, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+	liked, err := s.commentRepo.LikedIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	page := &CommentPage{Comments: make([]*CommentView, 0, len(comments))}
+	for _, c := range comments {
+		page.Comments = append(page.Comments, &CommentView{Comment: c, Liked: liked[c.ID]})
+	}
+	if len(comments) == limit {
+		last := comments[len(comments)-1]
+		page.NextCursor = fmt.Sprintf("%d_%d", keyOf(last), last.ID)
+	}
+	return page, nil
+}
+
+// visible 查询正常状态的评论
+func (s *CommentService) visible(ctx context.Context, id int64) (*domain.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.Status != domain.CommentNormal {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// checkTarget 校验评论目标存在；非公开歌单和云盘私有歌曲只有创建者可以查看和评论
+func (s *CommentService) checkTarget(ctx context.Context, userID int64, targetType string, targetID int64) error {
+	var exists bool
+	switch targetType {
+	case domain.CommentTargetSong:
+		song, err := s.songRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		exists = song != nil && (song.OwnerID == 0 || song.OwnerID == userID)
+	case domain.CommentTargetAlbum:
+		album, err := s.albumRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		exists = album != nil
+	case domain.CommentTargetPlaylist:
+		pl, err := s.playlistRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		exists = pl != nil && (pl.Public || pl.UserID == userID)
+	default:
+		return ErrInvalidTarget
+	}
+	if !exists {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+// parseCursor 解析 "key_id" 形式的游标，空字符串表示第一页
+func parseCursor(cursor string) (*repo.Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	key, id, ok := strings.Cut(cursor, "_")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	c := &repo.Cursor{}
+	var err1, err2 error
+	c.Key, err1 = strconv.ParseInt(key, 10, 64)
+	c.ID, err2 = strconv.ParseInt(id, 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"wyy/internal/cache"
+	"wyy/internal/repo/discover"
+)
+
+var (
+	ErrBannerNotFound = errors.New("banner not found")
+	ErrInvalidBanner  = errors.New("invalid banner")
+)
+
+// Banner 支持的平台
+var bannerPlatforms = []string{"web", "ios", "android"}
+
+const bannerCacheKey = "unexpired"
+
+// BannerService Banner 管理与展示，前台读取走进程内缓存，后台修改后立即失效
+type BannerService struct {
+	bannerRepo repo.BannerRepo
+	cache      *cache.Memory[[]*repo.Banner]
+}
+
+func NewBannerService(bannerRepo repo.BannerRepo) *BannerService {
+	return &BannerService{
+		bannerRepo: bannerRepo,
+		cache:      cache.NewMemory[[]*repo.Banner](5*time.Minute, 1),
+	}
+}
+
+// ListActive 返回当前处于展示期、且面向 platform 的 Banner；platform 为空时不按平台过滤
+func (s *BannerService) ListActive(ctx context.Context, platform string) ([]*repo.Banner, error) {
+	now := time.Now().Unix()
+	banners, ok := s.cache.Get(bannerCacheKey)
+	if !ok {
+		var err error
+		// 缓存尚未结束的全部 Banner，展示期和平台在读取时判断，定时上下线不依赖缓存过期
+		banners, err = s.bannerRepo.ListUnexpired(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(bannerCacheKey, banners)
+	}
+
+	active := make([]*repo.Banner, 0, len(banners))
+	for _, banner := range banners {
+		if banner.StartAt > now || (banner.EndAt != 0 && banner.EndAt <= now) {
+			continue
+		}
+		if platform != "" && len(banner.Platforms) > 0 && !slices.Contains(banner.Platforms, platform) {
+			continue
+		}
+		active = append(active, banner)
+	}
+	return active, nil
+}
+
+// List 返回全部 Banner（管理后台）
+func (s *BannerService) List(ctx context.Context) ([]*repo.Banner, error) {
+	return s.bannerRepo.ListBanners(ctx)
+}
+
+func (s *BannerService) Get(ctx context.Context, id int64) (*repo.Banner, error) {
+	banner, err := s.bannerRepo.GetBanner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if banner == nil {
+		return nil, ErrBannerNotFound
+	}
+	return banner, nil
+}
+
+func (s *BannerService) Create(ctx context.Context, banner *repo.Banner) error {
+	if err := validateBanner(banner); err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	banner.ID = 0
+	banner.CreatedAt = now
+	banner.UpdatedAt = now
+	if err := s.bannerRepo.CreateBanner(ctx, banner); err != nil {
+		return err
+	}
+	s.cache.Clear()
+	return nil
+}
+
+func (s *BannerService) Update(ctx context.Context, banner *repo.Banner) error {
+	if err := validateBanner(banner); err != nil {
+		return err
+	}
+	existing, err := s.Get(ctx, banner.ID)
+	if err != nil {
+		return err
+	}
+	banner.CreatedAt = existing.CreatedAt
+	banner.UpdatedAt = time.Now().Unix()
+	if err := s.bannerRepo.UpdateBanner(ctx, banner); err != nil {
+		return err
+	}
+	s.cache.Clear()
+	return nil
+}
+
+func (s *BannerService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.bannerRepo.DeleteBanner(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBannerNotFound
+	}
+	s.cache.Clear()
+	return nil
+}
+
+func validateBanner(banner *repo.Banner) error {
+	if banner.ImageURL == "" {
+		return fmt.Errorf("%w: image_url is required", ErrInvalidBanner)
+	}
+	switch banner.TargetType {
+	case repo.BannerTargetSong, repo.BannerTargetAlbum, repo.BannerTargetPlaylist:
+		if banner.TargetID == "" {
+			return fmt.Errorf("%w: target_id is required", ErrInvalidBanner)
+		}
+	case repo.BannerTargetURL:
+		if u, err := url.Parse(banner.TargetID); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("%w: target url must be http(s)", ErrInvalidBanner)
+		}
+	default:
+		return fmt.Errorf("%w: unknown target_type", ErrInvalidBanner)
+	}
+	if banner.EndAt != 0 && banner.EndAt <= banner.StartAt {
+		return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidBanner)
+	}
+	for _, platform := range banner.Platforms {
+		if !slices.Contains(bannerPlatforms, platform) {
+			return fmt.Errorf("%w: unknown platform %s", ErrInvalidBanner, platform)
+		}
+	}
+	return nil
+}
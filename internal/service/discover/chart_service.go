@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"wyy/internal/repo/discover"
+)
+
+var (
+	ErrToplistNotFound = errors.New("toplist not found")
+	ErrInvalidPeriod   = errors.New("invalid period")
+)
+
+const (
+	chartLikeWeight    = 3                   // 一次喜欢折合的播放次数
+	chartWindow        = 7 * 24 * time.Hour  // 热歌、新歌、原创、曲风榜的统计窗口
+	surgeWindow        = 24 * time.Hour      // 飙升榜比较最近一天与前一天
+	newSongAge         = 30 * 24 * time.Hour // 新歌榜只统计该时长内发布的歌曲
+	surgeCandidates    = 5                   // 飙升榜从 Size 倍的热门歌曲中挑选
+	surgeSmoothing     = 10                  // 飙升得分的平滑项，避免冷门歌曲偶然几次播放就冲上榜首
+	toplistPreviewSize = 3                   // 榜单列表中每个榜单预览的歌曲数
+)
+
+// ChartOptions 榜单配置
+type ChartOptions struct {
+	RefreshInterval time.Duration  // 重新计算间隔
+	Size            int            // 每个榜单的歌曲数
+	Location        *time.Location // 按该时区的自然日划分期数
+	OriginalTag     string         // 原创榜统计的标签
+	Tags            []string       // 曲风榜标签
+}
+
+// chartDef 榜单定义
+type chartDef struct {
+	id          string
+	name        string
+	description string
+	tag         string        // 只统计带该标签的歌曲
+	newOnly     bool          // 只统计近期发布的歌曲
+	surge       bool          // 按增长幅度而不是总量排名
+	window      time.Duration // 统计窗口
+}
+
+// Toplist 某一期榜单
+type Toplist struct {
+	ID          string
+	Name        string
+	Description string
+	Period      string // 期数，即计算日期 2006-01-02，尚未生成时为空
+	UpdatedAt   int64
+	Songs       []*ToplistSong
+}
+
+// ToplistSong 榜单中的歌曲，LastRank 为 0 表示新上榜
+type ToplistSong struct {
+	Song     *repo.Song
+	Rank     int
+	LastRank int
+	Score    float64
+}
+
+// ChartService 榜单：定时按播放和喜欢统计各榜单并保存为当天一期的快照，读取时直接返回快照
+type ChartService struct {
+	chartRepo repo.ChartRepo
+	songRepo  repo.SongRepo
+	opts      ChartOptions
+	charts    []*chartDef
+}
+
+func NewChartService(chartRepo repo.ChartRepo, songRepo repo.SongRepo, opts ChartOptions) *ChartService {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Hour
+	}
+	if opts.Size <= 0 {
+		opts.Size = 100
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.OriginalTag == "" {
+		opts.OriginalTag = "原创"
+	}
+	charts := []*chartDef{
+		{id: "hot", name: "热歌榜", description: "最近 7 天播放和收藏最多的歌曲", window: chartWindow},
+		{id: "surge", name: "飙升榜", description: "最近一天热度增长最快的歌曲", surge: true, window: surgeWindow},
+		{id: "new", name: "新歌榜", description: "最近 30 天发布的新歌中最热门的歌曲", newOnly: true, window: chartWindow},
+		{id: "original", name: "原创榜", description: "最近 7 天最热门的原创歌曲", tag: opts.OriginalTag, window: chartWindow},
+	}
+	for _, tag := range opts.Tags {
+		charts = append(charts, &chartDef{
+			id:          "tag-" + tag,
+			name:        tag + "榜",
+			description: "最近 7 天最热门的" + tag + "歌曲",
+			tag:         tag,
+			window:      chartWindow,
+		})
+	}
+	return &ChartService{chartRepo: chartRepo, songRepo: songRepo, opts: opts, charts: charts}
+}
+
+// Start 启动时计算一次，之后每隔 RefreshInterval 重新计算，直到 ctx 取消
+func (s *ChartService) Start(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("chart refresh: %v", err)
+	}
+	ticker := time.NewTicker(s.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Printf("chart refresh: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh 重新计算全部榜单，覆盖当天一期的快照；单个榜单失败不影响其他榜单
+func (s *ChartService) Refresh(ctx context.Context) error {
+	now := time.Now()
+	period := now.In(s.opts.Location).Format(time.DateOnly)
+	var errs []error
+	for _, chart := range s.charts {
+		if err := s.refreshChart(ctx, chart, period, now); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", chart.id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *ChartService) refreshChart(ctx context.Context, chart *chartDef, period string, now time.Time) error {
+	var entries []*repo.ChartEntry
+	var err error
+	if chart.surge {
+		entries, err = s.computeSurge(ctx, chart, now)
+	} else {
+		entries, err = s.computeTotal(ctx, chart, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 排名变化与上一期比较
+	previous, err := s.chartRepo.GetPreviousSnapshot(ctx, chart.id, period)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		lastRanks := make(map[string]int, len(previous.Entries))
+		for i, entry := range previous.Entries {
+			lastRanks[entry.SongID] = i + 1
+		}
+		for _, entry := range entries {
+			entry.LastRank = lastRanks[entry.SongID]
+		}
+	}
+	return s.chartRepo.SaveSnapshot(ctx, &repo.ChartSnapshot{
+		ChartID:   chart.id,
+		Period:    period,
+		Entries:   entries,
+		CreatedAt: now.Unix(),
+		UpdatedAt: now.Unix(),
+	})
+}
+
+// computeTotal 按窗口内播放与喜欢的加权总数排名
+func (s *ChartService) computeTotal(ctx context.Context, chart *chartDef, now time.Time) ([]*repo.ChartEntry, error) {
+	query := repo.ChartQuery{
+		Since:      now.Add(-chart.window).Unix(),
+		Until:      now.Unix(),
+		Tag:        chart.tag,
+		LikeWeight: chartLikeWeight,
+		Limit:      s.opts.Size,
+	}
+	if chart.newOnly {
+		query.PublishedAfter = now.Add(-newSongAge).Unix()
+	}
+	counts, err := s.chartRepo.CountActions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*repo.ChartEntry, 0, len(counts))
+	for _, count := range counts {
+		entries = append(entries, &repo.ChartEntry{SongID: count.SongID, Score: chartScore(count)})
+	}
+	return entries, nil
+}
+
+// computeSurge 比较最近一个窗口与前一个窗口的热度，按 (当前 - 之前) / sqrt(之前 + 平滑项) 排名，只保留热度上升的歌曲
+func (s *ChartService) computeSurge(ctx context.Context, chart *chartDef, now time.Time) ([]*repo.ChartEntry, error) {
+	since := now.Add(-chart.window)
+	counts, err := s.chartRepo.CountActions(ctx, repo.ChartQuery{
+		Since:      since.Unix(),
+		Until:      now.Unix(),
+		LikeWeight: chartLikeWeight,
+		Limit:      s.opts.Size * surgeCandidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+	songIDs := make([]string, 0, len(counts))
+	for _, count := range counts {
+		songIDs = append(songIDs, count.SongID)
+	}
+	previous, err := s.chartRepo.CountActionsBySongs(ctx, songIDs, since.Add(-chart.window).Unix(), since.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*repo.ChartEntry, 0, len(counts))
+	for _, count := range counts {
+		var before float64
+		if prev := previous[count.SongID]; prev != nil {
+			before = chartScore(prev)
+		}
+		growth := chartScore(count) - before
+		if growth <= 0 {
+			continue
+		}
+		entries = append(entries, &repo.ChartEntry{
+			SongID: count.SongID,
+			Score:  growth / math.Sqrt(before+surgeSmoothing),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Score > entries[j].Score })
+	if len(entries) > s.opts.Size {
+		entries = entries[:s.opts.Size]
+	}
+	return entries, nil
+}
+
+// ListToplists 全部榜单的最新一期，每个榜单只带前几名预览
+func (s *ChartService) ListToplists(ctx context.Context) ([]*Toplist, error) {
+	toplists := make([]*Toplist, 0, len(s.charts))
+	var songIDs []string
+	snapshots := make([]*repo.ChartSnapshot, 0, len(s.charts))
+	for _, chart := range s.charts {
+		snapshot, err := s.chartRepo.GetLatestSnapshot(ctx, chart.id)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil && len(snapshot.Entries) > toplistPreviewSize {
+			snapshot.Entries = snapshot.Entries[:toplistPreviewSize]
+		}
+		snapshots = append(snapshots, snapshot)
+		if snapshot != nil {
+			for _, entry := range snapshot.Entries {
+				songIDs = append(songIDs, entry.SongID)
+			}
+		}
+	}
+	songs, err := s.loadSongs(ctx, songIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, chart := range s.charts {
+		toplists = append(toplists, newToplist(chart, snapshots[i], songs))
+	}
+	return toplists, nil
+}
+
+// GetToplist 榜单详情，period 为空时返回最新一期
+func (s *ChartService) GetToplist(ctx context.Context, id, period string) (*Toplist, error) {
+	chart := s.chart(id)
+	if chart == nil {
+		return nil, fmt.Errorf("%w: %s", ErrToplistNotFound, id)
+	}
+	var snapshot *repo.ChartSnapshot
+	var err error
+	if period == "" {
+		snapshot, err = s.chartRepo.GetLatestSnapshot(ctx, id)
+	} else {
+		if _, perr := time.Parse(time.DateOnly, period); perr != nil {
+			return nil, fmt.Errorf("%w: %q, expected 2006-01-02", ErrInvalidPeriod, period)
+		}
+		snapshot, err = s.chartRepo.GetSnapshot(ctx, id, period)
+		if err == nil && snapshot == nil {
+			return nil, fmt.Errorf("%w: %s has no period %s", ErrToplistNotFound, id, period)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var songIDs []string
+	if snapshot != nil {
+		songIDs = make([]string, 0, len(snapshot.Entries))
+		for _, entry := range snapshot.Entries {
+			songIDs = append(songIDs, entry.SongID)
+		}
+	}
+	songs, err := s.loadSongs(ctx, songIDs)
+	if err != nil {
+		return nil, err
+	}
+	return newToplist(chart, snapshot, songs), nil
+}
+
+func (s *ChartService) chart(id string) *chartDef {
+	for _, chart := range s.charts {
+		if chart.id == id {
+			return chart
+		}
+	}
+	return nil
+}
+
+// loadSongs 批量加载歌曲，按 ID 索引
+func (s *ChartService) loadSongs(ctx context.Context, songIDs []string) (map[string]*repo.Song, error) {
+	songMap := make(map[string]*repo.Song, len(songIDs))
+	if len(songIDs) == 0 {
+		return songMap, nil
+	}
+	songs, err := s.songRepo.GetSongs(ctx, songIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, song := range songs {
+		songMap[song.ID] = song
+	}
+	return songMap, nil
+}
+
+// newToplist 组装榜单，名次沿用计算时的排名，之后下架的歌曲直接跳过
+func newToplist(chart *chartDef, snapshot *repo.ChartSnapshot, songs map[string]*repo.Song) *Toplist {
+	toplist := &Toplist{
+		ID:          chart.id,
+		Name:        chart.name,
+		Description: chart.description,
+		Songs:       []*ToplistSong{},
+	}
+	if snapshot == nil {
+		return toplist
+	}
+	toplist.Period = snapshot.Period
+	toplist.UpdatedAt = snapshot.UpdatedAt
+	for i, entry := range snapshot.Entries {
+		song, ok := songs[entry.SongID]
+		if !ok || song.Unavailable {
+			continue
+		}
+		toplist.Songs = append(toplist.Songs, &ToplistSong{
+			Song:     song,
+			Rank:     i + 1,
+			LastRank: entry.LastRank,
+			Score:    entry.Score,
+		})
+	}
+	return toplist
+}
+
+func chartScore(count *repo.SongActionCount) float64 {
+	return float64(count.Plays) + float64(count.Likes)*chartLikeWeight
+}
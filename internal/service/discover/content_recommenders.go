@@ -0,0 +1,128 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"wyy/internal/repo/discover"
+)
+
+// SimilarSongRecommender 以用户最近喜欢的歌曲为种子，召回相似歌曲
+type SimilarSongRecommender struct {
+	userActionRepo repo.UserActionRepo
+	songRepo       repo.SongRepo
+	seeds          int // 种子歌曲数量
+}
+
+func NewSimilarSongRecommender(userActionRepo repo.UserActionRepo, songRepo repo.SongRepo, seeds int) *SimilarSongRecommender {
+	return &SimilarSongRecommender{
+		userActionRepo: userActionRepo,
+		songRepo:       songRepo,
+		seeds:          seeds,
+	}
+}
+
+func (r *SimilarSongRecommender) Recommend(ctx context.Context, req *RecommendRequest) ([]*RecommendItem, error) {
+	actions, err := r.userActionRepo.GetAllUserActions(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	likes := slices.DeleteFunc(actions, func(a *repo.UserAction) bool { return a.Action != "like" })
+	slices.SortFunc(likes, func(a, b *repo.UserAction) int { return cmp.Compare(b.Timestamp, a.Timestamp) })
+	seedIDs := make([]string, 0, r.seeds)
+	for _, like := range likes {
+		if len(seedIDs) == r.seeds {
+			break
+		}
+		if !slices.Contains(seedIDs, like.SongID) {
+			seedIDs = append(seedIDs, like.SongID)
+		}
+	}
+	if len(seedIDs) == 0 {
+		return []*RecommendItem{}, nil
+	}
+	seeds, err := r.songRepo.GetSongs(ctx, seedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	perSeed := req.Size/len(seeds) + 1
+	items := make([]*RecommendItem, 0, req.Size)
+	for _, seed := range seeds {
+		similar, err := r.songRepo.GetSimilarSongs(ctx, seed.ID, perSeed)
+		if err != nil {
+			return nil, err
+		}
+		for i, song := range similar {
+			items = append(items, &RecommendItem{
+				SongID: song.ID,
+				Score:  1 / float64(i+1),
+				Source: SourceSimilarSong,
+				Reason: &repo.Reason{Type: repo.ReasonSimilarSong, RefType: repo.FeedbackTargetSong, RefID: seed.ID, RefName: seed.Name},
+			})
+		}
+	}
+	return items, nil
+}
+
+// ArtistNewRecommender 召回用户偏好歌手近期发布的新歌
+type ArtistNewRecommender struct {
+	profileRepo repo.UserProfileRepo
+	songRepo    repo.SongRepo
+	window      time.Duration // 多久以内发布的算新歌
+	maxArtists  int
+}
+
+func NewArtistNewRecommender(profileRepo repo.UserProfileRepo, songRepo repo.SongRepo, window time.Duration, maxArtists int) *ArtistNewRecommender {
+	return &ArtistNewRecommender{
+		profileRepo: profileRepo,
+		songRepo:    songRepo,
+		window:      window,
+		maxArtists:  maxArtists,
+	}
+}
+
+func (r *ArtistNewRecommender) Recommend(ctx context.Context, req *RecommendRequest) ([]*RecommendItem, error) {
+	profile, err := r.profileRepo.GetUserProfile(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil || len(profile.PreferredArtists) == 0 {
+		return []*RecommendItem{}, nil
+	}
+	artists := profile.PreferredArtists
+	if len(artists) > r.maxArtists {
+		artists = artists[:r.maxArtists]
+	}
+
+	now := time.Now()
+	since := now.Add(-r.window).Unix()
+	items := make([]*RecommendItem, 0, req.Size)
+	for _, artist := range artists {
+		songs, err := r.songRepo.GetSongsByArtist(ctx, artist, req.Size)
+		if err != nil {
+			return nil, err
+		}
+		for _, song := range songs {
+			if song.PublishTime < since {
+				continue
+			}
+			// 越新得分越高
+			age := now.Sub(time.Unix(song.PublishTime, 0)).Hours() / 24
+			name := ""
+			if i := slices.Index(song.ArtistIDs, artist); i >= 0 && i < len(song.ArtistNames) {
+				name = song.ArtistNames[i]
+			}
+			items = append(items, &RecommendItem{
+				SongID: song.ID,
+				Score:  math.Exp(-age / 30),
+				Source: SourceArtistNew,
+				Reason: &repo.Reason{Type: repo.ReasonArtistNew, RefType: repo.FeedbackTargetArtist, RefID: artist, RefName: name},
+			})
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+
+	"wyy/internal/repo/discover"
+)
+
+// DailyOptions 每日推荐配置
+type DailyOptions struct {
+	Size         int            // 每日推荐歌曲数
+	RunAt        string         // 每天生成的本地时间，格式 15:04，同时也是列表的切换时间
+	Location     *time.Location // 时区
+	ActiveWindow time.Duration  // 批量任务只为该时间窗口内活跃的用户生成
+	Workers      int            // 批量任务并发数
+}
+
+// DailyService 每日推荐：按天为用户生成固定的推荐列表
+type DailyService struct {
+	recommender *RecommendationService
+	dailyRepo   repo.DailyRepo
+	songRepo    repo.SongRepo
+	opts        DailyOptions
+	runAt       time.Duration // 距本地零点的偏移
+	group       singleflight.Group
+}
+
+func NewDailyService(recommender *RecommendationService, dailyRepo repo.DailyRepo, songRepo repo.SongRepo, opts DailyOptions) (*DailyService, error) {
+	if opts.Size <= 0 {
+		opts.Size = 30
+	}
+	if opts.RunAt == "" {
+		opts.RunAt = "06:00"
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.ActiveWindow <= 0 {
+		opts.ActiveWindow = 30 * 24 * time.Hour
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	t, err := time.Parse("15:04", opts.RunAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daily run_at %q: %w", opts.RunAt, err)
+	}
+	return &DailyService{
+		recommender: recommender,
+		dailyRepo:   dailyRepo,
+		songRepo:    songRepo,
+		opts:        opts,
+		runAt:       time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute,
+	}, nil
+}
+
+// GetDaily 返回用户当天的每日推荐，批量任务遗漏的用户在首次请求时生成
+func (s *DailyService) GetDaily(ctx context.Context, userID string) ([]*RecommendedSong, error) {
+	date := s.dateOf(time.Now())
+	snapshot, err := s.dailyRepo.GetSnapshot(ctx, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		// 同一用户的并发请求只生成一次，生成结果被所有等待者共享，不随首个请求取消
+		v, err, _ := s.group.Do(userID+":"+date, func() (interface{}, error) {
+			return s.generate(context.WithoutCancel(ctx), userID, date)
+		})
+		if err != nil {
+			return nil, err
+		}
+		snapshot = v.(*repo.DailySnapshot)
+	}
+	return s.loadSongs(ctx, snapshot)
+}
+
+// generate 生成并保存快照；并发生成时以先写入的为准
+func (s *DailyService) generate(ctx context.Context, userID, date string) (*repo.DailySnapshot, error) {
+	songs, err := s.recommender.GetRecommendations(ctx, userID, s.opts.Size)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &repo.DailySnapshot{
+		UserID:    userID,
+		Date:      date,
+		SongIDs:   make([]string, 0, len(songs)),
+		Reasons:   make([]*repo.Reason, 0, len(songs)),
+		CreatedAt: time.Now().Unix(),
+	}
+	for _, song := range songs {
+		snapshot.SongIDs = append(snapshot.SongIDs, song.Song.ID)
+		snapshot.Reasons = append(snapshot.Reasons, song.Reason)
+	}
+	// 推荐为空时不落库，避免用户当天都拿到空列表
+	if len(snapshot.SongIDs) == 0 {
+		return snapshot, nil
+	}
+
+	created, err := s.dailyRepo.SaveSnapshot(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		existing, err := s.dailyRepo.GetSnapshot(ctx, userID, date)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+	return snapshot, nil
+}
+
+// RunBatch 为活跃用户生成当天的每日推荐，已有快照的用户跳过
+func (s *DailyService) RunBatch(ctx context.Context) error {
+	date := s.dateOf(time.Now())
+	userIDs, err := s.dailyRepo.ListActiveUsers(ctx, time.Now().Add(-s.opts.ActiveWindow).Unix())
+	if err != nil {
+		return err
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(s.opts.Workers)
+	for _, userID := range userIDs {
+		eg.Go(func() error {
+			existing, err := s.dailyRepo.GetSnapshot(ctx, userID, date)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				return nil
+			}
+			// 单个用户失败不影响其他用户，首次请求时会再次尝试
+			if _, err := s.generate(ctx, userID, date); err != nil {
+				log.Printf("daily recommend for user %s: %v", userID, err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	log.Printf("daily recommend %s generated for %d active users", date, len(userIDs))
+	return nil
+}
+
+// Start 每天在 RunAt 时刻执行批量任务，直到 ctx 取消
+func (s *DailyService) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(s.nextRun(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := s.RunBatch(ctx); err != nil {
+				log.Printf("daily recommend batch: %v", err)
+			}
+		}
+	}
+}
+
+// dateOf 返回 t 所属的推荐日期，RunAt 之前仍属于前一天
+func (s *DailyService) dateOf(t time.Time) string {
+	return t.In(s.opts.Location).Add(-s.runAt).Format(time.DateOnly)
+}
+
+// nextRun 计算 now 之后的下一次执行时间
+func (s *DailyService) nextRun(now time.Time) time.Time {
+	local := now.In(s.opts.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.opts.Location)
+	next := midnight.Add(s.runAt)
+	if !next.After(local) {
+		next = midnight.AddDate(0, 0, 1).Add(s.runAt)
+	}
+	return next
+}
+
+// loadSongs 按快照中的顺序返回歌曲详情和推荐理由，已下架的歌曲会被跳过
+func (s *DailyService) loadSongs(ctx context.Context, snapshot *repo.DailySnapshot) ([]*RecommendedSong, error) {
+	songs, err := s.songRepo.GetSongs(ctx, snapshot.SongIDs)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*RecommendItem, 0, len(snapshot.SongIDs))
+	for i, id := range snapshot.SongIDs {
+		item := &RecommendItem{SongID: id}
+		if i < len(snapshot.Reasons) {
+			item.Reason = snapshot.Reasons[i]
+		}
+		items = append(items, item)
+	}
+	ordered := withReasons(songs, items)
+	available := ordered[:0]
+	for _, song := range ordered {
+		if !song.Song.Unavailable {
+			available = append(available, song)
+		}
+	}
+	return available, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"wyy/internal/repo/discover"
+)
+
+// 排序特征的固定顺序，模型文件中的 FeatureNames 必须与之一致
+var FeatureNames = buildFeatureNames()
+
+// rankSources 参与 one-hot 编码的召回来源
+var rankSources = []string{SourceUserCF, SourceSimilarSong, SourceArtistNew}
+
+const freshnessHalfLifeDays = 30.0
+
+func buildFeatureNames() []string {
+	names := []string{
+		"recall_score",
+		"tag_affinity",
+		"artist_affinity",
+		"log_play_count",
+		"log_like_count",
+		"like_rate",
+		"skip_rate",
+		"freshness",
+	}
+	for _, source := range rankSources {
+		names = append(names, "source_"+source)
+	}
+	return names
+}
+
+// FeatureExtractor 为候选歌曲构造排序特征
+type FeatureExtractor struct {
+	profileRepo repo.UserProfileRepo
+	songRepo    repo.SongRepo
+	statsRepo   repo.SongStatsRepo
+	now         func() time.Time
+}
+
+func NewFeatureExtractor(profileRepo repo.UserProfileRepo, songRepo repo.SongRepo, statsRepo repo.SongStatsRepo) *FeatureExtractor {
+	return &FeatureExtractor{
+		profileRepo: profileRepo,
+		songRepo:    songRepo,
+		statsRepo:   statsRepo,
+		now:         time.Now,
+	}
+}
+
+// Extract 返回与 items 一一对应的特征向量
+func (e *FeatureExtractor) Extract(ctx context.Context, userID string, items []*RecommendItem) ([][]float64, error) {
+	profile, err := e.profileRepo.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	songMap, err := loadSongMap(ctx, e.songRepo, items)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := e.statsRepo.GetSongStats(ctx, extractSongIDs(items))
+	if err != nil {
+		return nil, err
+	}
+
+	artistSet := make(map[string]bool)
+	if profile != nil {
+		for _, artist := range profile.PreferredArtists {
+			artistSet[artist] = true
+		}
+	}
+
+	now := e.now().Unix()
+	vectors := make([][]float64, len(items))
+	for i, item := range items {
+		vectors[i] = e.featuresFor(item, songMap[item.SongID], stats[item.SongID], profile, artistSet, now)
+	}
+	return vectors, nil
+}
+
+func (e *FeatureExtractor) featuresFor(item *RecommendItem, song *repo.Song, stats *repo.SongStats, profile *repo.UserProfile, artistSet map[string]bool, now int64) []float64 {
+	f := make([]float64, 0, len(FeatureNames))
+	f = append(f, math.Log1p(math.Max(item.RecallScore, 0)))
+
+	// 用户画像相关
+	tagAffinity, artistAffinity := 0.0, 0.0
+	if song != nil {
+		if profile != nil && len(song.Tags) > 0 {
+			for _, tag := range song.Tags {
+				tagAffinity += profile.PreferredTags[tag]
+			}
+			tagAffinity /= float64(len(song.Tags))
+		}
+		if slices.ContainsFunc(song.ArtistIDs, func(id string) bool { return artistSet[id] }) {
+			artistAffinity = 1
+		}
+	}
+	f = append(f, tagAffinity, artistAffinity)
+
+	// 歌曲统计
+	var plays, likes, skips float64
+	if stats != nil {
+		plays, likes, skips = float64(stats.PlayCount), float64(stats.LikeCount), float64(stats.SkipCount)
+	}
+	likeRate, skipRate := 0.0, 0.0
+	if plays > 0 {
+		likeRate = likes / plays
+		skipRate = skips / plays
+	}
+	f = append(f, math.Log1p(plays), math.Log1p(likes), likeRate, skipRate)
+
+	// 新鲜度：按半衰期指数衰减
+	freshness := 0.0
+	if song != nil && song.PublishTime > 0 {
+		ageDays := math.Max(float64(now-song.PublishTime)/86400, 0)
+		freshness = math.Exp(-ageDays * math.Ln2 / freshnessHalfLifeDays)
+	}
+	f = append(f, freshness)
+
+	for _, source := range rankSources {
+		if item.Source == source {
+			f = append(f, 1)
+		} else {
+			f = append(f, 0)
+		}
+	}
+	return f
+}
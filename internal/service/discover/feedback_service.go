@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"wyy/internal/repo/discover"
+)
+
+var (
+	ErrInvalidFeedbackType = errors.New("invalid feedback type")
+	ErrFeedbackTarget      = errors.New("feedback target not found")
+	ErrFeedbackNotFound    = errors.New("feedback not found")
+)
+
+// 负反馈对用户画像标签权重的惩罚
+const (
+	tagFeedbackPenalty  = 1.0 // 直接对标签不感兴趣
+	songFeedbackPenalty = 0.3 // 对歌曲不感兴趣，惩罚分摊到歌曲的每个标签
+)
+
+// FeedbackService 处理“不感兴趣”负反馈：持久化后由 BlocklistFilter 硬过滤，同时降低画像中对应标签的权重
+type FeedbackService struct {
+	feedbackRepo repo.FeedbackRepo
+	profileRepo  repo.UserProfileRepo
+	songRepo     repo.SongRepo
+}
+
+func NewFeedbackService(feedbackRepo repo.FeedbackRepo, profileRepo repo.UserProfileRepo, songRepo repo.SongRepo) *FeedbackService {
+	return &FeedbackService{
+		feedbackRepo: feedbackRepo,
+		profileRepo:  profileRepo,
+		songRepo:     songRepo,
+	}
+}
+
+// Submit 提交负反馈，重复提交不会重复惩罚
+func (s *FeedbackService) Submit(ctx context.Context, userID, targetType, targetID string) error {
+	if err := validateFeedbackType(targetType); err != nil {
+		return err
+	}
+	tags, err := s.penaltyTags(ctx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	created, err := s.feedbackRepo.AddFeedback(ctx, &repo.Feedback{
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		CreatedAt:  time.Now().Unix(),
+	})
+	if err != nil || !created {
+		return err
+	}
+	return s.adjustProfile(ctx, userID, targetType, targetID, tags, -1)
+}
+
+// Undo 撤销负反馈并恢复标签权重
+func (s *FeedbackService) Undo(ctx context.Context, userID, targetType, targetID string) error {
+	if err := validateFeedbackType(targetType); err != nil {
+		return err
+	}
+	removed, err := s.feedbackRepo.RemoveFeedback(ctx, userID, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrFeedbackNotFound
+	}
+	tags, err := s.penaltyTags(ctx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	return s.adjustProfile(ctx, userID, targetType, targetID, tags, 1)
+}
+
+// List 获取用户的负反馈列表
+func (s *FeedbackService) List(ctx context.Context, userID string) ([]*repo.Feedback, error) {
+	return s.feedbackRepo.ListFeedback(ctx, userID)
+}
+
+// penaltyTags 返回需要调整权重的标签及每个标签的惩罚值
+func (s *FeedbackService) penaltyTags(ctx context.Context, targetType, targetID string) (map[string]float64, error) {
+	switch targetType {
+	case repo.FeedbackTargetTag:
+		return map[string]float64{targetID: tagFeedbackPenalty}, nil
+	case repo.FeedbackTargetSong:
+		songs, err := s.songRepo.GetSongs(ctx, []string{targetID})
+		if err != nil {
+			return nil, err
+		}
+		if len(songs) == 0 {
+			return nil, ErrFeedbackTarget
+		}
+		tags := make(map[string]float64, len(songs[0].Tags))
+		for _, tag := range songs[0].Tags {
+			tags[tag] = songFeedbackPenalty
+		}
+		return tags, nil
+	}
+	return nil, nil
+}
+
+// adjustProfile sign 为 -1 时施加惩罚，为 1 时恢复
+func (s *FeedbackService) adjustProfile(ctx context.Context, userID, targetType, targetID string, tags map[string]float64, sign float64) error {
+	profile, err := s.profileRepo.GetUserProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		profile = &repo.UserProfile{UserID: userID}
+	}
+	if profile.PreferredTags == nil {
+		profile.PreferredTags = make(map[string]float64)
+	}
+	for tag, penalty := range tags {
+		profile.PreferredTags[tag] += sign * penalty
+	}
+	// 对歌手不感兴趣时移出偏好歌手；撤销时不恢复，等待离线画像任务重新计算
+	if targetType == repo.FeedbackTargetArtist && sign < 0 {
+		profile.PreferredArtists = slices.DeleteFunc(profile.PreferredArtists, func(a string) bool { return a == targetID })
+	}
+	profile.UpdateTime = time.Now().Unix()
+	return s.profileRepo.UpdateUserProfile(ctx, profile)
+}
+
+func validateFeedbackType(targetType string) error {
+	switch targetType {
+	case repo.FeedbackTargetSong, repo.FeedbackTargetArtist, repo.FeedbackTargetTag:
+		return nil
+	}
+	return ErrInvalidFeedbackType
+}
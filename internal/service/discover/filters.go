@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"wyy/internal/cache"
+	"wyy/internal/repo/discover"
+)
+
+// 过滤器名称，用于配置过滤链
+const (
+	FilterListened     = "listened"
+	FilterBlocklist    = "blocklist"
+	FilterFatigue      = "fatigue"
+	FilterExplicit     = "explicit"
+	FilterAvailability = "availability"
+)
+
+// DefaultFilterChain 未配置时使用的过滤链
+var DefaultFilterChain = []string{FilterAvailability, FilterBlocklist, FilterExplicit, FilterListened, FilterFatigue}
+
+// FilterDeps 构建过滤链所需的依赖
+type FilterDeps struct {
+	UserActionRepo repo.UserActionRepo
+	SongRepo       repo.SongRepo
+	ImpressionRepo repo.ImpressionRepo
+	FeedbackRepo   repo.FeedbackRepo
+	SettingsRepo   repo.UserSettingsRepo
+	Listened       *ListenedFilter // 与播放、喜欢等入口共享的已听过滤器，为空时新建
+
+	FatigueWindow         time.Duration // 曝光疲劳统计窗口
+	FatigueMaxImpressions int           // 窗口内最多曝光次数
+}
+
+// BuildFilterChain 按名称顺序构建过滤链，names 为空时使用 DefaultFilterChain
+func BuildFilterChain(names []string, deps FilterDeps) ([]Filter, error) {
+	if len(names) == 0 {
+		names = DefaultFilterChain
+	}
+	filters := make([]Filter, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case FilterListened:
+			listened := deps.Listened
+			if listened == nil {
+				listened = NewListenedFilter(deps.UserActionRepo)
+			}
+			filters = append(filters, listened)
+		case FilterBlocklist:
+			filters = append(filters, NewBlocklistFilter(deps.FeedbackRepo, deps.SongRepo))
+		case FilterFatigue:
+			filters = append(filters, NewImpressionFatigueFilter(deps.ImpressionRepo, deps.FatigueWindow, deps.FatigueMaxImpressions))
+		case FilterExplicit:
+			filters = append(filters, NewExplicitFilter(deps.SettingsRepo, deps.SongRepo))
+		case FilterAvailability:
+			filters = append(filters, NewAvailabilityFilter(deps.SettingsRepo, deps.SongRepo))
+		default:
+			return nil, fmt.Errorf("unknown recommend filter %q", name)
+		}
+	}
+	return filters, nil
+}
+
+// 已听过滤实现
+type ListenedFilter struct {
+	userActionRepo repo.UserActionRepo
+	played         *cache.Memory[*bloomFilter] // 用户已听歌曲的布隆过滤器
+}
+
+func NewListenedFilter(userActionRepo repo.UserActionRepo) *ListenedFilter {
+	return &ListenedFilter{
+		userActionRepo: userActionRepo,
+		played:         cache.NewMemory[*bloomFilter](30*time.Minute, 10000),
+	}
+}
+
+func (f *ListenedFilter) Filter(ctx context.Context, userID string, items []*RecommendItem) ([]*RecommendItem, error) {
+	// 获取用户已听歌曲集合
+	playedSet, err := f.getPlayedSet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*RecommendItem, 0, len(items))
+	for _, item := range items {
+		if !playedSet.Test(item.SongID) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// MarkPlayed 用户产生新的播放、喜欢或跳过时更新缓存，避免等待缓存过期
+func (f *ListenedFilter) MarkPlayed(userID, songID string) {
+	if playedSet, ok := f.played.Get(userID); ok {
+		playedSet.Add(songID)
+	}
+}
+
+// getPlayedSet 获取用户已听歌曲集合，缓存未命中时才加载全部行为
+func (f *ListenedFilter) getPlayedSet(ctx context.Context, userID string) (*bloomFilter, error) {
+	if playedSet, ok := f.played.Get(userID); ok {
+		return playedSet, nil
+	}
+	actions, err := f.userActionRepo.GetAllUserActions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 预留增长空间，缓存期内的新播放通过 MarkPlayed 写入
+	playedSet := newBloomFilter(len(actions)*2+1000, 0.01)
+	for _, action := range actions {
+		playedSet.Add(action.SongID)
+	}
+	f.played.Set(userID, playedSet)
+	return playedSet, nil
+}
+
+// BlocklistFilter 过滤用户标记“不感兴趣”的歌曲、歌手和标签
+type BlocklistFilter struct {
+	feedbackRepo repo.FeedbackRepo
+	songRepo     repo.SongRepo
+}
+
+func NewBlocklistFilter(feedbackRepo repo.FeedbackRepo, songRepo repo.SongRepo) *BlocklistFilter {
+	return &BlocklistFilter{feedbackRepo: feedbackRepo, songRepo: songRepo}
+}
+
+func (f *BlocklistFilter) Filter(ctx context.Context, userID string, items []*RecommendItem) ([]*RecommendItem, error) {
+	feedback, err := f.feedbackRepo.ListFeedback(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(feedback) == 0 {
+		return items, nil
+	}
+
+	blocked := make(map[string]map[string]bool)
+	for _, fb := range feedback {
+		if blocked[fb.TargetType] == nil {
+			blocked[fb.TargetType] = make(map[string]bool)
+		}
+		blocked[fb.TargetType][fb.TargetID] = true
+	}
+
+	// 只有存在歌手或标签反馈时才需要歌曲详情
+	var songMap map[string]*repo.Song
+	if len(blocked[repo.FeedbackTargetArtist]) > 0 || len(blocked[repo.FeedbackTargetTag]) > 0 {
+		songMap, err = loadSongMap(ctx, f.songRepo, items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filtered := make([]*RecommendItem, 0, len(items))
+	for _, item := range items {
+		if blocked[repo.FeedbackTargetSong][item.SongID] {
+			continue
+		}
+		if song := songMap[item.SongID]; song != nil {
+			if slices.ContainsFunc(song.ArtistIDs, func(id string) bool { return blocked[repo.FeedbackTargetArtist][id] }) ||
+				slices.ContainsFunc(song.Tags, func(tag string) bool { return blocked[repo.FeedbackTargetTag][tag] }) {
+				continue
+			}
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered, nil
+}
+
+// ImpressionFatigueFilter 过滤近期已多次曝光的歌曲
+type ImpressionFatigueFilter struct {
+	impressionRepo repo.ImpressionRepo
+	window         time.Duration
+	maxImpressions int
+}
+
+func NewImpressionFatigueFilter(impressionRepo repo.ImpressionRepo, window time.Duration, maxImpressions int) *ImpressionFatigueFilter {
+	if window <= 0 {
+		window = 72 * time.Hour
+	}
+	if maxImpressions <= 0 {
+		maxImpressions = 3
+	}
+	return &ImpressionFatigueFilter{
+		impressionRepo: impressionRepo,
+		window:         window,
+		maxImpressions: maxImpressions,
+	}
+}
+
+func (f *ImpressionFatigueFilter) Filter(ctx context.Context, userID string, items []*RecommendItem) ([]*RecommendItem, error) {
+	counts, err := f.impressionRepo.CountUserImpressions(ctx, userID, time.Now().Add(-f.window).Unix())
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*RecommendItem, 0, len(items))
+	for _, item := range items {
+		if counts[item.SongID] < f.maxImpressions {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// ExplicitFilter 用户开启过滤后去掉含不适宜内容的歌曲
+type ExplicitFilter struct {
+	settingsRepo repo.UserSettingsRepo
+	songRepo     repo.SongRepo
+}
+
+func NewExplicitFilter(settingsRepo repo.UserSettingsRepo, songRepo repo.SongRepo) *ExplicitFilter {
+	return &ExplicitFilter{settingsRepo: settingsRepo, songRepo: songRepo}
+}
+
+func (f *ExplicitFilter) Filter(ctx context.Context, userID string, items []*RecommendItem) ([]*RecommendItem, error) {
+	settings, err := f.settingsRepo.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil || !settings.HideExplicit {
+		return items, nil
+	}
+	songMap, err := loadSongMap(ctx, f.songRepo, items)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*RecommendItem, 0, len(items))
+	for _, item := range items {
+		if song := songMap[item.SongID]; song != nil && song.Explicit {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered, nil
+}
+
+// AvailabilityFilter 过滤下架歌曲以及用户所在地区无版权的歌曲
+type AvailabilityFilter struct {
+	settingsRepo repo.UserSettingsRepo
+	songRepo     repo.SongRepo
+}
+
+func NewAvailabilityFilter(settingsRepo repo.UserSettingsRepo, songRepo repo.SongRepo) *AvailabilityFilter {
+	return &AvailabilityFilter{settingsRepo: settingsRepo, songRepo: songRepo}
+}
+
+func (f *AvailabilityFilter) Filter(ctx context.Context, userID string, items []*RecommendItem) ([]*RecommendItem, error) {
+	settings, err := f.settingsRepo.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	region := ""
+	if settings != nil {
+		region = settings.Region
+	}
+	songMap, err := loadSongMap(ctx, f.songRepo, items)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*RecommendItem, 0, len(items))
+	for _, item := range items {
+		song := songMap[item.SongID]
+		if song == nil || song.Unavailable || song.OwnerID != "" {
+			continue
+		}
+		// 有地区限制的歌曲，用户地区未知时同样不推荐
+		if len(song.Regions) > 0 && !slices.Contains(song.Regions, region) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered, nil
+}
+
+// loadSongMap 批量获取候选歌曲详情
+func loadSongMap(ctx context.Context, songRepo repo.SongRepo, items []*RecommendItem) (map[string]*repo.Song, error) {
+	songs, err := songRepo.GetSongs(ctx, extractSongIDs(items))
+	if err != nil {
+		return nil, err
+	}
+	songMap := make(map[string]*repo.Song, len(songs))
+	for _, song := range songs {
+		songMap[song.ID] = song
+	}
+	return songMap, nil
+}
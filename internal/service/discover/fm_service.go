@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"wyy/internal/cache"
+	"wyy/internal/repo/discover"
+)
+
+// 私人 FM 会话内反馈
+const (
+	FMActionLike  = "like"
+	FMActionSkip  = "skip"
+	FMActionTrash = "trash" // 不再播放，同时记为不感兴趣
+)
+
+const SourceFMSession = "fm_session"
+
+var ErrInvalidFMAction = errors.New("invalid fm action")
+
+// SongLiker 将喜欢写入用户曲库（喜欢记录、歌曲喜欢数和行为日志），由曲库模块提供
+type SongLiker func(ctx context.Context, userID, songID string) error
+
+const (
+	fmSessionTTL     = 30 * time.Minute // 会话空闲超时
+	fmCandidateRatio = 5                // 每次取歌时召回数量相对返回数量的倍数
+	fmDriftDecay     = 0.8              // 每次反馈前旧偏好的衰减系数
+	fmMaxServed      = 1000             // 会话内记录的已播歌曲上限
+	fmSessionTags    = 2                // 按会话偏好召回时使用的标签数
+	fmMaxRecall      = 4000             // 候选不足时逐步扩大召回的数量上限
+)
+
+// 会话内反馈对标签和歌手偏好的调整
+var fmActionWeights = map[string]float64{
+	FMActionLike:  1.0,
+	FMActionSkip:  -0.5,
+	FMActionTrash: -1.0,
+}
+
+// fmSession 私人 FM 会话状态：已下发歌曲和短期偏好漂移
+type fmSession struct {
+	mu          sync.Mutex
+	served      map[string]bool
+	tagDrift    map[string]float64
+	artistDrift map[string]float64
+}
+
+func newFMSession() *fmSession {
+	return &fmSession{
+		served:      make(map[string]bool),
+		tagDrift:    make(map[string]float64),
+		artistDrift: make(map[string]float64),
+	}
+}
+
+// FMService 私人 FM：基于召回器和过滤器的无限推荐流，实时响应会话内反馈
+type FMService struct {
+	recallers       []Recommender
+	filters         []Filter
+	songRepo        repo.SongRepo
+	userActionRepo  repo.UserActionRepo
+	feedbackService *FeedbackService
+	sessions        *cache.Memory[*fmSession]
+	listened        *ListenedFilter // 可选，反馈后立即更新推荐的已听过滤
+	songLiker       SongLiker       // 可选，设置后喜欢经由曲库模块记录
+}
+
+func NewFMService(recallers []Recommender, filters []Filter, songRepo repo.SongRepo, userActionRepo repo.UserActionRepo, feedbackService *FeedbackService) *FMService {
+	return &FMService{
+		recallers:       recallers,
+		filters:         filters,
+		songRepo:        songRepo,
+		userActionRepo:  userActionRepo,
+		feedbackService: feedbackService,
+		sessions:        cache.NewMemory[*fmSession](fmSessionTTL, 100000),
+	}
+}
+
+// Next 返回会话中接下来的 count 首歌
+func (s *FMService) Next(ctx context.Context, userID string, count int) ([]*RecommendedSong, error) {
+	session := s.session(userID)
+	session.mu.Lock()
+	served := maps.Clone(session.served)
+	session.mu.Unlock()
+
+	// 召回结果是各来源的前 N 首，已下发的歌曲会占满前几页，
+	// 因此召回时排除已下发歌曲，候选不足时扩大召回数量直到凑够或来源耗尽
+	var candidates []*RecommendItem
+	var songMap map[string]*repo.Song
+	for size := count*fmCandidateRatio + len(served); ; size *= 2 {
+		size = min(size, fmMaxRecall)
+		items, more, err := s.recall(ctx, userID, session, served, size)
+		if err != nil {
+			return nil, err
+		}
+		for _, filter := range s.filters {
+			items, err = filter.Filter(ctx, userID, items)
+			if err != nil {
+				return nil, err
+			}
+		}
+		candidates = items
+		songMap, err = loadSongMap(ctx, s.songRepo, candidates)
+		if err != nil {
+			return nil, err
+		}
+		if len(songMap) >= count || !more || size >= fmMaxRecall {
+			break
+		}
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	// 召回得分叠加会话内的短期偏好
+	picked := make([]*RecommendItem, 0, len(candidates))
+	for _, item := range candidates {
+		song := songMap[item.SongID]
+		if song == nil || session.served[item.SongID] {
+			continue
+		}
+		for _, tag := range song.Tags {
+			item.Score += session.tagDrift[tag]
+		}
+		for _, artistID := range song.ArtistIDs {
+			item.Score += session.artistDrift[artistID]
+		}
+		picked = append(picked, item)
+	}
+	sortByScore(picked)
+	if len(picked) > count {
+		picked = picked[:count]
+	}
+
+	if len(session.served)+len(picked) > fmMaxServed {
+		clear(session.served)
+	}
+	songs := make([]*RecommendedSong, 0, len(picked))
+	for _, item := range picked {
+		session.served[item.SongID] = true
+		songs = append(songs, &RecommendedSong{Song: songMap[item.SongID], Reason: item.Reason})
+	}
+	return songs, nil
+}
+
+// Feedback 处理会话内的喜欢/跳过/不再播放，下一次取歌立即生效
+func (s *FMService) Feedback(ctx context.Context, userID, songID, action string) error {
+	weight, ok := fmActionWeights[action]
+	if !ok {
+		return ErrInvalidFMAction
+	}
+	songs, err := s.songRepo.GetSongs(ctx, []string{songID})
+	if err != nil {
+		return err
+	}
+	if len(songs) == 0 {
+		return ErrFeedbackTarget
+	}
+	song := songs[0]
+
+	session := s.session(userID)
+	session.mu.Lock()
+	for tag := range session.tagDrift {
+		session.tagDrift[tag] *= fmDriftDecay
+	}
+	for artist := range session.artistDrift {
+		session.artistDrift[artist] *= fmDriftDecay
+	}
+	for _, tag := range song.Tags {
+		session.tagDrift[tag] += weight
+	}
+	for _, artistID := range song.ArtistIDs {
+		session.artistDrift[artistID] += weight
+	}
+	session.served[songID] = true
+	session.mu.Unlock()
+
+	if action == FMActionTrash {
+		if err := s.feedbackService.Submit(ctx, userID, repo.FeedbackTargetSong, songID); err != nil {
+			return err
+		}
+	}
+	if action == FMActionLike && s.songLiker != nil {
+		// 与曲库中的喜欢一致，同时写入喜欢记录和行为日志
+		err = s.songLiker(ctx, userID, songID)
+	} else {
+		// trash 同样记为跳过，供协同过滤和排序模型使用
+		actionName := action
+		if action == FMActionTrash {
+			actionName = FMActionSkip
+		}
+		err = s.userActionRepo.InsertAction(ctx, &repo.UserAction{
+			UserID:    userID,
+			SongID:    songID,
+			Action:    actionName,
+			Value:     1,
+			Timestamp: time.Now().Unix(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if s.listened != nil {
+		s.listened.MarkPlayed(userID, songID)
+	}
+	return nil
+}
+
+// SetListenedFilter 设置推荐使用的已听过滤器，会话内反馈后立即生效
+func (s *FMService) SetListenedFilter(listened *ListenedFilter) {
+	s.listened = listened
+}
+
+// SetSongLiker 设置喜欢的写入方式，未设置时只记录行为日志
+func (s *FMService) SetSongLiker(liker SongLiker) {
+	s.songLiker = liker
+}
+
+// session 获取或创建会话，每次访问刷新空闲超时
+func (s *FMService) session(userID string) *fmSession {
+	session, ok := s.sessions.Get(userID)
+	if !ok {
+		session = newFMSession()
+	}
+	s.sessions.Set(userID, session)
+	return session
+}
+
+// recall 多路召回，并按会话中偏好最强的标签补充候选，跳过会话内已下发的歌曲。
+// more 表示有来源返回满了 size 条，扩大召回数量可能得到更多候选
+func (s *FMService) recall(ctx context.Context, userID string, session *fmSession, served map[string]bool, size int) (candidates []*RecommendItem, more bool, err error) {
+	seen := make(map[string]bool)
+	candidates = make([]*RecommendItem, 0, size)
+	add := func(items []*RecommendItem) {
+		if len(items) >= size {
+			more = true
+		}
+		for _, item := range items {
+			if !seen[item.SongID] && !served[item.SongID] {
+				seen[item.SongID] = true
+				candidates = append(candidates, item)
+			}
+		}
+	}
+
+	for _, recaller := range s.recallers {
+		items, err := recaller.Recommend(ctx, &RecommendRequest{UserID: userID, Size: size})
+		if err != nil {
+			return nil, false, err
+		}
+		add(items)
+	}
+
+	for _, tag := range s.topDriftTags(session) {
+		songs, err := s.songRepo.GetTopSongsByTag(ctx, tag, size)
+		if err != nil {
+			return nil, false, err
+		}
+		items := make([]*RecommendItem, 0, len(songs))
+		for _, song := range songs {
+			items = append(items, &RecommendItem{
+				SongID: song.ID,
+				Source: SourceFMSession,
+				Reason: &repo.Reason{Type: repo.ReasonTag, RefType: repo.FeedbackTargetTag, RefID: tag, RefName: tag},
+			})
+		}
+		add(items)
+	}
+	return candidates, more, nil
+}
+
+// topDriftTags 返回会话中正向偏好最强的若干标签
+func (s *FMService) topDriftTags(session *fmSession) []string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	tags := slices.Collect(maps.Keys(session.tagDrift))
+	tags = slices.DeleteFunc(tags, func(tag string) bool { return session.tagDrift[tag] <= 0 })
+	slices.SortFunc(tags, func(a, b string) int {
+		switch {
+		case session.tagDrift[a] > session.tagDrift[b]:
+			return -1
+		case session.tagDrift[a] < session.tagDrift[b]:
+			return 1
+		}
+		return 0
+	})
+	if len(tags) > fmSessionTags {
+		tags = tags[:fmSessionTags]
+	}
+	return tags
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"wyy/internal/repo/discover"
+)
+
+var (
+	ErrHistorySong       = errors.New("song not found")
+	ErrHistoryNotFound   = errors.New("history entry not found")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidRankRange  = errors.New("invalid ranking range")
+	ErrInvalidPlayLength = errors.New("invalid play duration")
+)
+
+// 听歌排行的统计范围
+const (
+	RankRangeWeek = "week"
+	RankRangeAll  = "all"
+)
+
+const (
+	maxRankingSongs = 100
+	maxPlayDuration = 24 * 60 * 60
+)
+
+// HistoryOptions 播放历史配置
+type HistoryOptions struct {
+	Location    *time.Location // 按天分组使用的时区
+	MergeWindow time.Duration  // 同一首歌在该间隔内连续播放时合并为一条
+}
+
+// HistoryItem 一条播放历史
+type HistoryItem struct {
+	ID       int64
+	Song     *repo.Song
+	PlayedAt int64
+	Plays    int
+}
+
+// HistoryDay 同一天的播放历史
+type HistoryDay struct {
+	Date  string // 2006-01-02
+	Items []*HistoryItem
+}
+
+// HistoryPage 一页播放历史，按天分组；跨页的同一天由客户端合并
+type HistoryPage struct {
+	Days       []*HistoryDay
+	NextCursor string // 为空表示没有更多
+}
+
+// RankedSong 听歌排行中的歌曲
+type RankedSong struct {
+	Song  *repo.Song
+	Plays int
+}
+
+// HistoryService 最近播放与听歌排行
+type HistoryService struct {
+	historyRepo repo.HistoryRepo
+	songRepo    repo.SongRepo
+	opts        HistoryOptions
+	listened    *ListenedFilter // 可选，播放后立即更新推荐的已听过滤
+}
+
+func NewHistoryService(historyRepo repo.HistoryRepo, songRepo repo.SongRepo, opts HistoryOptions) *HistoryService {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.MergeWindow <= 0 {
+		opts.MergeWindow = 30 * time.Minute
+	}
+	return &HistoryService{historyRepo: historyRepo, songRepo: songRepo, opts: opts}
+}
+
+// Record 上报一次播放，duration 为实际播放秒数
+func (s *HistoryService) Record(ctx context.Context, userID, songID string, duration int) error {
+	if duration < 0 || duration > maxPlayDuration {
+		return ErrInvalidPlayLength
+	}
+	songs, err := s.songRepo.GetSongs(ctx, []string{songID})
+	if err != nil {
+		return err
+	}
+	// 其他用户的云盘私有歌曲视为不存在
+	if len(songs) == 0 || (songs[0].OwnerID != "" && songs[0].OwnerID != userID) {
+		return ErrHistorySong
+	}
+	err = s.historyRepo.RecordPlay(ctx, &repo.PlayRecord{
+		UserID:   userID,
+		SongID:   songID,
+		PlayedAt: time.Now().Unix(),
+		Duration: duration,
+	}, int64(s.opts.MergeWindow/time.Second))
+	if err != nil {
+		return err
+	}
+	if s.listened != nil {
+		s.listened.MarkPlayed(userID, songID)
+	}
+	return nil
+}
+
+// SetListenedFilter 设置推荐使用的已听过滤器，播放上报后立即生效
+func (s *HistoryService) SetListenedFilter(listened *ListenedFilter) {
+	s.listened = listened
+}
+
+// History 按播放时间倒序分页获取播放历史，cursor 为空时从最新开始
+func (s *HistoryService) History(ctx context.Context, userID, cursor string, limit int) (*HistoryPage, error) {
+	var after *repo.PlayCursor
+	if cursor != "" {
+		parsed, err := parseCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = parsed
+	}
+	records, err := s.historyRepo.ListPlays(ctx, userID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	songMap, err := songMapOf(ctx, s.songRepo, records, func(r *repo.PlayRecord) string { return r.SongID })
+	if err != nil {
+		return nil, err
+	}
+
+	page := &HistoryPage{Days: []*HistoryDay{}}
+	for _, record := range records {
+		song, ok := songMap[record.SongID]
+		if !ok {
+			continue
+		}
+		date := time.Unix(record.PlayedAt, 0).In(s.opts.Location).Format(time.DateOnly)
+		if n := len(page.Days); n == 0 || page.Days[n-1].Date != date {
+			page.Days = append(page.Days, &HistoryDay{Date: date})
+		}
+		day := page.Days[len(page.Days)-1]
+		day.Items = append(day.Items, &HistoryItem{
+			ID:       record.ID,
+			Song:     song,
+			PlayedAt: record.PlayedAt,
+			Plays:    record.Plays,
+		})
+	}
+	if len(records) == limit {
+		last := records[len(records)-1]
+		page.NextCursor = fmt.Sprintf("%d_%d", last.PlayedAt, last.ID)
+	}
+	return page, nil
+}
+
+// Delete 删除一条播放历史
+func (s *HistoryService) Delete(ctx context.Context, userID string, id int64) error {
+	ok, err := s.historyRepo.DeletePlay(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrHistoryNotFound
+	}
+	return nil
+}
+
+// Clear 清空播放历史
+func (s *HistoryService) Clear(ctx context.Context, userID string) error {
+	return s.historyRepo.ClearPlays(ctx, userID)
+}
+
+// Ranking 听歌排行：最近一周或全部时间内播放次数最多的歌曲
+func (s *HistoryService) Ranking(ctx context.Context, userID, rankRange string) ([]*RankedSong, error) {
+	var since int64
+	switch rankRange {
+	case RankRangeWeek:
+		since = time.Now().Add(-7 * 24 * time.Hour).Unix()
+	case RankRangeAll:
+	default:
+		return nil, ErrInvalidRankRange
+	}
+	counts, err := s.historyRepo.TopSongs(ctx, userID, since, maxRankingSongs)
+	if err != nil {
+		return nil, err
+	}
+	songMap, err := songMapOf(ctx, s.songRepo, counts, func(c *repo.SongPlayCount) string { return c.SongID })
+	if err != nil {
+		return nil, err
+	}
+	ranked := make([]*RankedSong, 0, len(counts))
+	for _, count := range counts {
+		if song, ok := songMap[count.SongID]; ok {
+			ranked = append(ranked, &RankedSong{Song: song, Plays: count.Plays})
+		}
+	}
+	return ranked, nil
+}
+
+// songMapOf 批量加载记录对应的歌曲
+func songMapOf[T any](ctx context.Context, songRepo repo.SongRepo, rows []T, songIDOf func(T) string) (map[string]*repo.Song, error) {
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = songIDOf(row)
+	}
+	songs, err := songRepo.GetSongs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	songMap := make(map[string]*repo.Song, len(songs))
+	for _, song := range songs {
+		songMap[song.ID] = song
+	}
+	return songMap, nil
+}
+
+// parseCursor 解析 "playedAt_id" 形式的游标
+func parseCursor(cursor string) (*repo.PlayCursor, error) {
+	playedAt, id, ok := strings.Cut(cursor, "_")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	c := &repo.PlayCursor{}
+	var err1, err2 error
+	c.PlayedAt, err1 = strconv.ParseInt(playedAt, 10, 64)
+	c.ID, err2 = strconv.ParseInt(id, 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
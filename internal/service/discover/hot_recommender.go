@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"wyy/internal/repo/discover"
+)
+
+const SourceHot = "hot"
+
+// HotRecommender 全局热门召回，用于冷启动和兜底
+type HotRecommender struct {
+	cacheRepo repo.CacheRepo
+}
+
+func NewHotRecommender(cacheRepo repo.CacheRepo) *HotRecommender {
+	return &HotRecommender{cacheRepo: cacheRepo}
+}
+
+func (r *HotRecommender) Recommend(ctx context.Context, req *RecommendRequest) ([]*RecommendItem, error) {
+	songs, err := r.cacheRepo.GetGlobalHotSongs(ctx, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*RecommendItem, 0, len(songs))
+	for i, song := range songs {
+		items = append(items, &RecommendItem{
+			SongID: song.ID,
+			Score:  1 / float64(i+1), // 按热度名次给分
+			Source: SourceHot,
+			Reason: &repo.Reason{Type: repo.ReasonHot},
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+)
+
+// LTRRanker 基于特征和排序模型的排序器，用模型得分替换召回得分
+type LTRRanker struct {
+	extractor *FeatureExtractor
+	model     RankModel
+	fallback  Ranker
+}
+
+func NewLTRRanker(extractor *FeatureExtractor, model RankModel) *LTRRanker {
+	return &LTRRanker{
+		extractor: extractor,
+		model:     model,
+		fallback:  NewScoreBasedRanker(),
+	}
+}
+
+func (r *LTRRanker) Rank(ctx context.Context, userID string, items []*RecommendItem) ([]*RecommendItem, error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	// 未加载模型时也要提取特征，随曝光记录下来作为训练第一个模型的样本
+	features, err := r.extractor.Extract(ctx, userID, items)
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range items {
+		item.Features = features[i]
+	}
+	// 未加载模型时退化为按召回得分排序
+	if r.model == nil {
+		return r.fallback.Rank(ctx, userID, items)
+	}
+	for i, item := range items {
+		item.Score = r.model.Score(features[i])
+	}
+	sortByScore(items)
+	return items, nil
+}
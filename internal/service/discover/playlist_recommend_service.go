@@ -0,0 +1,367 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"wyy/internal/cache"
+	"wyy/internal/repo/discover"
+)
+
+// 歌单召回来源
+const (
+	SourcePlaylistTag   = "playlist_tag"
+	SourcePlaylistLiked = "playlist_liked"
+	SourcePlaylistHot   = "playlist_hot"
+)
+
+// playlistCacheSlack 缓存的推荐结果比请求数量多取的歌单数，用户之后收藏或创建的歌单被排除后从中补齐
+const playlistCacheSlack = 10
+
+// PlaylistItem 召回阶段返回的候选歌单，Score 归一化到 0~1
+type PlaylistItem struct {
+	PlaylistID string
+	Score      float64
+	Source     string
+	Reason     *repo.Reason
+}
+
+// PlaylistRecaller 歌单召回器
+type PlaylistRecaller interface {
+	// Recall 返回候选歌单列表
+	Recall(ctx context.Context, req *RecommendRequest) ([]*PlaylistItem, error)
+}
+
+// RecommendedPlaylist 推荐结果：歌单详情及推荐理由
+type RecommendedPlaylist struct {
+	Playlist *repo.Playlist
+	Reason   *repo.Reason
+}
+
+// TagPlaylistRecaller 按用户画像中的偏好标签召回歌单，标签权重越高、歌单越热门得分越高
+type TagPlaylistRecaller struct {
+	profileRepo  repo.UserProfileRepo
+	playlistRepo repo.PlaylistRepo
+	maxTags      int // 参与召回的偏好标签数
+}
+
+func NewTagPlaylistRecaller(profileRepo repo.UserProfileRepo, playlistRepo repo.PlaylistRepo, maxTags int) *TagPlaylistRecaller {
+	return &TagPlaylistRecaller{profileRepo: profileRepo, playlistRepo: playlistRepo, maxTags: maxTags}
+}
+
+func (r *TagPlaylistRecaller) Recall(ctx context.Context, req *RecommendRequest) ([]*PlaylistItem, error) {
+	profile, err := r.profileRepo.GetUserProfile(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil || len(profile.PreferredTags) == 0 {
+		return []*PlaylistItem{}, nil
+	}
+	tags := make([]string, 0, len(profile.PreferredTags))
+	for tag, weight := range profile.PreferredTags {
+		if weight > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	slices.SortFunc(tags, func(a, b string) int {
+		return cmp.Or(cmp.Compare(profile.PreferredTags[b], profile.PreferredTags[a]), cmp.Compare(a, b))
+	})
+	if len(tags) > r.maxTags {
+		tags = tags[:r.maxTags]
+	}
+	var total float64
+	for _, tag := range tags {
+		total += profile.PreferredTags[tag]
+	}
+	if total == 0 {
+		return []*PlaylistItem{}, nil
+	}
+
+	playlists, err := r.playlistRepo.GetPlaylistsByTags(ctx, tags, req.Size*3)
+	if err != nil {
+		return nil, err
+	}
+	maxPopularity := 0.0
+	for _, playlist := range playlists {
+		maxPopularity = max(maxPopularity, popularity(playlist))
+	}
+	items := make([]*PlaylistItem, 0, len(playlists))
+	for _, playlist := range playlists {
+		// 标签重合度为主，热度为辅
+		var match float64
+		best := ""
+		for _, tag := range tags {
+			if slices.Contains(playlist.Tags, tag) {
+				match += profile.PreferredTags[tag]
+				if best == "" {
+					best = tag
+				}
+			}
+		}
+		if best == "" {
+			continue
+		}
+		score := 0.8 * match / total
+		if maxPopularity > 0 {
+			score += 0.2 * popularity(playlist) / maxPopularity
+		}
+		items = append(items, &PlaylistItem{
+			PlaylistID: playlist.ID,
+			Score:      score,
+			Source:     SourcePlaylistTag,
+			Reason:     &repo.Reason{Type: repo.ReasonTag, RefType: repo.FeedbackTargetTag, RefID: best, RefName: best},
+		})
+	}
+	return items, nil
+}
+
+// LikedSongPlaylistRecaller 召回收录了用户最近喜欢的歌曲最多的歌单
+type LikedSongPlaylistRecaller struct {
+	playlistRepo repo.PlaylistRepo
+	songRepo     repo.SongRepo
+	seeds        int // 参与召回的最近喜欢歌曲数
+}
+
+func NewLikedSongPlaylistRecaller(playlistRepo repo.PlaylistRepo, songRepo repo.SongRepo, seeds int) *LikedSongPlaylistRecaller {
+	return &LikedSongPlaylistRecaller{playlistRepo: playlistRepo, songRepo: songRepo, seeds: seeds}
+}
+
+func (r *LikedSongPlaylistRecaller) Recall(ctx context.Context, req *RecommendRequest) ([]*PlaylistItem, error) {
+	liked, err := r.playlistRepo.GetLikedSongIDs(ctx, req.UserID, r.seeds)
+	if err != nil {
+		return nil, err
+	}
+	if len(liked) == 0 {
+		return []*PlaylistItem{}, nil
+	}
+	overlaps, err := r.playlistRepo.GetPlaylistsBySongs(ctx, liked, req.Size*3)
+	if err != nil {
+		return nil, err
+	}
+	if len(overlaps) == 0 {
+		return []*PlaylistItem{}, nil
+	}
+
+	// 推荐理由引用每个歌单中最近喜欢的一首歌
+	refIDs := make([]string, 0, len(overlaps))
+	for _, overlap := range overlaps {
+		if !slices.Contains(refIDs, overlap.SongIDs[0]) {
+			refIDs = append(refIDs, overlap.SongIDs[0])
+		}
+	}
+	songs, err := r.songRepo.GetSongs(ctx, refIDs)
+	if err != nil {
+		return nil, err
+	}
+	songNames := make(map[string]string, len(songs))
+	for _, song := range songs {
+		songNames[song.ID] = song.Name
+	}
+
+	maxHits := float64(overlaps[0].Hits)
+	items := make([]*PlaylistItem, 0, len(overlaps))
+	for _, overlap := range overlaps {
+		ref := overlap.SongIDs[0]
+		items = append(items, &PlaylistItem{
+			PlaylistID: overlap.PlaylistID,
+			Score:      float64(overlap.Hits) / maxHits,
+			Source:     SourcePlaylistLiked,
+			Reason:     &repo.Reason{Type: repo.ReasonLikedSongs, RefType: repo.FeedbackTargetSong, RefID: ref, RefName: songNames[ref]},
+		})
+	}
+	return items, nil
+}
+
+// PopularPlaylistRecaller 热门歌单召回，用于未登录、冷启动和个性化结果不足时兜底
+type PopularPlaylistRecaller struct {
+	playlistRepo repo.PlaylistRepo
+}
+
+func NewPopularPlaylistRecaller(playlistRepo repo.PlaylistRepo) *PopularPlaylistRecaller {
+	return &PopularPlaylistRecaller{playlistRepo: playlistRepo}
+}
+
+func (r *PopularPlaylistRecaller) Recall(ctx context.Context, req *RecommendRequest) ([]*PlaylistItem, error) {
+	playlists, err := r.playlistRepo.GetPopularPlaylists(ctx, req.Size*2)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*PlaylistItem, 0, len(playlists))
+	for i, playlist := range playlists {
+		items = append(items, &PlaylistItem{
+			PlaylistID: playlist.ID,
+			Score:      1 / float64(i+1), // 按热度名次给分
+			Source:     SourcePlaylistHot,
+			Reason:     &repo.Reason{Type: repo.ReasonHotPlaylist},
+		})
+	}
+	return items, nil
+}
+
+// popularity 歌单热度，收藏比播放更能体现认可
+func popularity(playlist *repo.Playlist) float64 {
+	return math.Log1p(float64(playlist.SubscribeCount)*5 + float64(playlist.PlayCount))
+}
+
+// PlaylistRecommendService 推荐歌单：多路召回按来源加权合并，排除用户自己创建和已收藏的歌单，
+// 个性化结果不足时用热门歌单补齐。结果按用户缓存一段时间，避免首页每次刷新都变化；
+// 自己创建和已收藏的歌单在读取缓存后再排除，收藏后立即生效
+type PlaylistRecommendService struct {
+	recallers    []PlaylistRecaller
+	weights      map[string]float64 // 召回来源权重，未配置的来源权重为 1
+	fallback     PlaylistRecaller
+	playlistRepo repo.PlaylistRepo
+	cache        *cache.Memory[[]*RecommendedPlaylist]
+}
+
+func NewPlaylistRecommendService(recallers []PlaylistRecaller, fallback PlaylistRecaller, playlistRepo repo.PlaylistRepo) *PlaylistRecommendService {
+	return &PlaylistRecommendService{
+		recallers: recallers,
+		weights: map[string]float64{
+			SourcePlaylistLiked: 1.2,
+			SourcePlaylistTag:   1,
+		},
+		fallback:     fallback,
+		playlistRepo: playlistRepo,
+		cache:        cache.NewMemory[[]*RecommendedPlaylist](30*time.Minute, 10000),
+	}
+}
+
+// GetRecommendations 为用户推荐歌单，userID 为空时返回热门歌单
+func (s *PlaylistRecommendService) GetRecommendations(ctx context.Context, userID string, size int) ([]*RecommendedPlaylist, error) {
+	exclude := make(map[string]bool)
+	if userID != "" {
+		owned, err := s.playlistRepo.GetUserPlaylistIDs(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range owned {
+			exclude[id] = true
+		}
+	}
+
+	key := userID + ":" + strconv.Itoa(size)
+	cached, ok := s.cache.Get(key)
+	if !ok {
+		var err error
+		cached, err = s.recommend(ctx, userID, size+playlistCacheSlack, exclude)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(key, cached)
+	}
+	result := make([]*RecommendedPlaylist, 0, size)
+	for _, item := range cached {
+		if len(result) == size {
+			break
+		}
+		if !exclude[item.Playlist.ID] {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// recommend 召回、合并并补齐到 size 个歌单，排除 exclude 中的歌单
+func (s *PlaylistRecommendService) recommend(ctx context.Context, userID string, size int, exclude map[string]bool) ([]*RecommendedPlaylist, error) {
+	req := &RecommendRequest{UserID: userID, Size: size}
+	var candidates []*PlaylistItem
+	if userID != "" {
+		var mu sync.Mutex
+		eg, egCtx := errgroup.WithContext(ctx)
+		for _, recaller := range s.recallers {
+			eg.Go(func() error {
+				items, err := recaller.Recall(egCtx, req)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				candidates = append(candidates, items...)
+				mu.Unlock()
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return nil, err
+		}
+	}
+	ranked := s.merge(candidates, exclude)
+
+	if len(ranked) < size {
+		popular, err := s.fallback.Recall(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool, len(ranked))
+		for _, item := range ranked {
+			seen[item.PlaylistID] = true
+		}
+		for _, item := range popular {
+			if !seen[item.PlaylistID] && !exclude[item.PlaylistID] {
+				ranked = append(ranked, item)
+			}
+		}
+	}
+	if len(ranked) > size {
+		ranked = ranked[:size]
+	}
+
+	ids := make([]string, 0, len(ranked))
+	for _, item := range ranked {
+		ids = append(ids, item.PlaylistID)
+	}
+	playlists, err := s.playlistRepo.GetPlaylists(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*repo.Playlist, len(playlists))
+	for _, playlist := range playlists {
+		byID[playlist.ID] = playlist
+	}
+	result := make([]*RecommendedPlaylist, 0, len(ranked))
+	for _, item := range ranked {
+		if playlist, ok := byID[item.PlaylistID]; ok {
+			result = append(result, &RecommendedPlaylist{Playlist: playlist, Reason: item.Reason})
+		}
+	}
+	return result, nil
+}
+
+// merge 同一歌单被多路召回时得分按来源权重累加，推荐理由取贡献最大的一路
+func (s *PlaylistRecommendService) merge(candidates []*PlaylistItem, exclude map[string]bool) []*PlaylistItem {
+	merged := make(map[string]*PlaylistItem)
+	best := make(map[string]float64)
+	result := make([]*PlaylistItem, 0, len(candidates))
+	for _, item := range candidates {
+		if exclude[item.PlaylistID] {
+			continue
+		}
+		weight, ok := s.weights[item.Source]
+		if !ok {
+			weight = 1
+		}
+		score := item.Score * weight
+		existing, ok := merged[item.PlaylistID]
+		if !ok {
+			existing = &PlaylistItem{PlaylistID: item.PlaylistID, Source: item.Source, Reason: item.Reason}
+			merged[item.PlaylistID] = existing
+			result = append(result, existing)
+		}
+		existing.Score += score
+		if score > best[item.PlaylistID] {
+			best[item.PlaylistID] = score
+			existing.Source, existing.Reason = item.Source, item.Reason
+		}
+	}
+	slices.SortStableFunc(result, func(a, b *PlaylistItem) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.PlaylistID, b.PlaylistID))
+	})
+	return result
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+)
+
+// RankModel 排序模型接口，输入特征向量输出点击概率
+type RankModel interface {
+	Score(features []float64) float64
+}
+
+// LogisticRegression 逻辑回归排序模型
+type LogisticRegression struct {
+	FeatureNames []string  `json:"feature_names"`
+	Weights      []float64 `json:"weights"`
+	Bias         float64   `json:"bias"`
+}
+
+func (m *LogisticRegression) Score(features []float64) float64 {
+	z := m.Bias
+	for i, w := range m.Weights {
+		if i < len(features) {
+			z += w * features[i]
+		}
+	}
+	return sigmoid(z)
+}
+
+// LoadLogisticRegression 从 JSON 文件加载模型，并校验特征顺序与当前代码一致
+func LoadLogisticRegression(path string) (*LogisticRegression, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m LogisticRegression
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse rank model: %w", err)
+	}
+	if !slices.Equal(m.FeatureNames, FeatureNames) {
+		return nil, fmt.Errorf("rank model features %v do not match %v", m.FeatureNames, FeatureNames)
+	}
+	if len(m.Weights) != len(m.FeatureNames) {
+		return nil, fmt.Errorf("rank model has %d weights for %d features", len(m.Weights), len(m.FeatureNames))
+	}
+	return &m, nil
+}
+
+// Save 将模型写入 JSON 文件
+func (m *LogisticRegression) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// TrainingSample 一条训练样本
+type TrainingSample struct {
+	Features []float64
+	Label    float64 // 1 表示正样本（播放/收藏），0 表示负样本
+}
+
+// TrainOptions 训练参数
+type TrainOptions struct {
+	Epochs       int
+	LearningRate float64
+	L2           float64
+	Seed         int64
+}
+
+// TrainLogisticRegression 使用 SGD 训练逻辑回归模型
+func TrainLogisticRegression(samples []TrainingSample, opts TrainOptions) (*LogisticRegression, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no training samples")
+	}
+	if opts.Epochs <= 0 {
+		opts.Epochs = 20
+	}
+	if opts.LearningRate <= 0 {
+		opts.LearningRate = 0.05
+	}
+
+	m := &LogisticRegression{
+		FeatureNames: slices.Clone(FeatureNames),
+		Weights:      make([]float64, len(FeatureNames)),
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	order := make([]int, len(samples))
+	for i := range order {
+		order[i] = i
+	}
+
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		for _, idx := range order {
+			sample := samples[idx]
+			if len(sample.Features) != len(m.Weights) {
+				continue
+			}
+			grad := m.Score(sample.Features) - sample.Label
+			for i, x := range sample.Features {
+				m.Weights[i] -= opts.LearningRate * (grad*x + opts.L2*m.Weights[i])
+			}
+			m.Bias -= opts.LearningRate * grad
+		}
+	}
+	return m, nil
+}
+
+// LogLoss 计算模型在样本上的平均对数损失
+func LogLoss(m RankModel, samples []TrainingSample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	const eps = 1e-15
+	total := 0.0
+	for _, sample := range samples {
+		p := math.Min(math.Max(m.Score(sample.Features), eps), 1-eps)
+		total -= sample.Label*math.Log(p) + (1-sample.Label)*math.Log(1-p)
+	}
+	return total / float64(len(samples))
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"log"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -20,9 +22,25 @@ func NewRecommendService(RecommendRepo *repo.RecommendRepo) *RecommendService {
 // 推荐核心模块
 // RecommendItem 召回阶段返回的候选项目
 type RecommendItem struct {
-	SongID string
-	Score  float64 // 算法原始得分
-	Reason string  // 推荐理由（可选）
+	SongID      string
+	Score       float64      // 当前得分，排序后为模型得分
+	RecallScore float64      // 召回阶段原始得分
+	Source      string       // 召回来源
+	Reason      *repo.Reason // 推荐理由（可选）
+	Features    []float64    // 排序特征，记录曝光时使用
+}
+
+// 召回来源
+const (
+	SourceUserCF      = "user_cf"
+	SourceSimilarSong = "similar_song"
+	SourceArtistNew   = "artist_new"
+)
+
+// RecommendedSong 推荐结果：歌曲详情及推荐理由
+type RecommendedSong struct {
+	Song   *repo.Song
+	Reason *repo.Reason
 }
 
 // RecommendRequest 推荐请求参数（可根据需要扩展）
@@ -139,7 +157,8 @@ func (r *UserBasedCFRecommender) aggregateSongsFromUsers(ctx context.Context, si
 		items = append(items, &RecommendItem{
 			SongID: songID,
 			Score:  score / float64(songCount[songID]), // 使用平均分
-			Reason: "协同过滤推荐",
+			Source: SourceUserCF,
+			Reason: &repo.Reason{Type: repo.ReasonSimilarUsers},
 		})
 	}
 
@@ -260,53 +279,13 @@ func (m *WeightedMixer) Mix(ctx context.Context, itemGroups [][]*RecommendItem,
 	return result, nil
 }
 
-// 已听过滤实现
-type ListenedFilter struct {
-	userActionRepo repo.UserActionRepo
-}
-
-func NewListenedFilter(userActionRepo repo.UserActionRepo) *ListenedFilter {
-	return &ListenedFilter{
-		userActionRepo: userActionRepo,
-	}
-}
-
-func (f *ListenedFilter) Filter(ctx context.Context, userID string, items []*RecommendItem) ([]*RecommendItem, error) {
-	// 获取用户已听歌曲ID集合
-	playedSet, err := f.getPlayedSet(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
-	filtered := make([]*RecommendItem, 0, len(items))
-	for _, item := range items {
-		if !playedSet[item.SongID] {
-			filtered = append(filtered, item)
-		}
-	}
-	return filtered, nil
-}
-
-// getPlayedSet 获取用户已听歌曲集合
-func (f *ListenedFilter) getPlayedSet(ctx context.Context, userID string) (map[string]bool, error) {
-	actions, err := f.userActionRepo.GetAllUserActions(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
-
-	playedSet := make(map[string]bool)
-	for _, action := range actions {
-		playedSet[action.SongID] = true
-	}
-
-	return playedSet, nil
-}
-
 type RecommendationService struct {
-	recallers []Recommender // 多路召回器
-	ranker    Ranker
-	filters   []Filter
-	mixer     Mixer
-	songRepo  repo.SongRepo // 用于获取详情
+	recallers      []Recommender // 多路召回器
+	ranker         Ranker
+	filters        []Filter
+	mixer          Mixer
+	songRepo       repo.SongRepo       // 用于获取详情
+	impressionRepo repo.ImpressionRepo // 曝光日志（可选）
 }
 
 func NewRecommendationService(recallers []Recommender, ranker Ranker, filters []Filter, mixer Mixer, songRepo repo.SongRepo) *RecommendationService {
@@ -319,7 +298,12 @@ func NewRecommendationService(recallers []Recommender, ranker Ranker, filters []
 	}
 }
 
-func (s *RecommendationService) GetRecommendations(ctx context.Context, userID string, size int) ([]*repo.Song, error) {
+// SetImpressionRepo 开启曝光日志记录，供排序模型离线训练使用
+func (s *RecommendationService) SetImpressionRepo(impressionRepo repo.ImpressionRepo) {
+	s.impressionRepo = impressionRepo
+}
+
+func (s *RecommendationService) GetRecommendations(ctx context.Context, userID string, size int) ([]*RecommendedSong, error) {
 	// 1. 并发执行多路召回
 	var mu sync.Mutex
 	allCandidates := make([]*RecommendItem, 0)
@@ -331,6 +315,9 @@ func (s *RecommendationService) GetRecommendations(ctx context.Context, userID s
 			if err != nil {
 				return err
 			}
+			for _, item := range items {
+				item.RecallScore = item.Score
+			}
 			mu.Lock()
 			allCandidates = append(allCandidates, items...)
 			mu.Unlock()
@@ -371,18 +358,54 @@ func (s *RecommendationService) GetRecommendations(ctx context.Context, userID s
 		return nil, err
 	}
 
-	// 7. 按 finalItems 顺序返回歌曲
-	return sortSongsByOrder(songs, finalItems), nil
+	// 7. 记录曝光
+	s.logImpressions(ctx, userID, finalItems)
+
+	// 8. 按 finalItems 顺序返回歌曲
+	return withReasons(songs, finalItems), nil
+}
+
+// logImpressions 异步写入曝光日志，失败不影响推荐结果
+func (s *RecommendationService) logImpressions(ctx context.Context, userID string, items []*RecommendItem) {
+	if s.impressionRepo == nil || len(items) == 0 {
+		return
+	}
+	now := time.Now().Unix()
+	impressions := make([]*repo.Impression, 0, len(items))
+	for i, item := range items {
+		impressions = append(impressions, &repo.Impression{
+			UserID:      userID,
+			SongID:      item.SongID,
+			Source:      item.Source,
+			RecallScore: item.RecallScore,
+			RankScore:   item.Score,
+			Position:    i,
+			Features:    item.Features,
+			ShownAt:     now,
+		})
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := s.impressionRepo.LogImpressions(ctx, impressions); err != nil {
+			log.Printf("log impressions for user %s: %v", userID, err)
+		}
+	}()
 }
 
 // 辅助方法
 func (s *RecommendationService) deduplicate(items []*RecommendItem) []*RecommendItem {
-	seen := make(map[string]bool)
+	index := make(map[string]int)
 	result := make([]*RecommendItem, 0, len(items))
 	for _, item := range items {
-		if !seen[item.SongID] {
-			seen[item.SongID] = true
+		i, seen := index[item.SongID]
+		if !seen {
+			index[item.SongID] = len(result)
 			result = append(result, item)
+			continue
+		}
+		// 多路召回到同一首歌时保留得分高的，连同其召回来源和推荐理由
+		if item.Score > result[i].Score {
+			result[i] = item
 		}
 	}
 	return result
@@ -397,22 +420,18 @@ func extractSongIDs(items []*RecommendItem) []string {
 	return songIDs
 }
 
-// sortSongsByOrder 按照推荐项的顺序排序歌曲
-func sortSongsByOrder(songs []*repo.Song, items []*RecommendItem) []*repo.Song {
-	// 构建歌曲ID到歌曲的映射
-	songMap := make(map[string]*repo.Song)
+// withReasons 按照推荐项的顺序返回歌曲，并附带推荐理由
+func withReasons(songs []*repo.Song, items []*RecommendItem) []*RecommendedSong {
+	songMap := make(map[string]*repo.Song, len(songs))
 	for _, song := range songs {
 		songMap[song.ID] = song
 	}
-
-	// 按照推荐项的顺序返回歌曲
-	result := make([]*repo.Song, 0, len(items))
+	result := make([]*RecommendedSong, 0, len(items))
 	for _, item := range items {
 		if song, exists := songMap[item.SongID]; exists {
-			result = append(result, song)
+			result = append(result, &RecommendedSong{Song: song, Reason: item.Reason})
 		}
 	}
-
 	return result
 }
 
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"wyy/internal/domain"
+	"wyy/internal/repo/discover"
+)
+
+var ErrInvalidReleaseFilter = errors.New("invalid release filter")
+
+const (
+	defaultReleaseWeeks = 2
+	maxReleaseWeeks     = 12
+	maxReleaseItems     = 500 // 单次请求最多返回的新歌或新碟数
+)
+
+// ReleaseOptions 新歌速递、新碟上架配置
+type ReleaseOptions struct {
+	Location *time.Location // 按该时区的自然周分组，周一为一周的第一天
+}
+
+// ReleaseFilter 新歌、新碟的筛选条件
+type ReleaseFilter struct {
+	Language string // 语种，见 domain.Languages，为空不限
+	Region   string // 只返回在该地区可播放的作品，为空时使用登录用户设置的地区
+	UserID   string // 登录用户，未登录为空
+	Before   int64  // 只返回该时间之前发布的作品，0 为当前时间，用于向前翻页
+	Weeks    int    // 从 Before 所在周起向前返回的周数
+}
+
+// ReleaseWeek 一周内发布的新歌和新碟，按发布时间倒序
+type ReleaseWeek struct {
+	Week   string // 该周周一的日期 2006-01-02
+	Songs  []*repo.Song
+	Albums []*repo.Album
+}
+
+// ReleasePage 按周分组的新歌、新碟，NextBefore 作为下一页的 Before 继续加载更早的作品
+type ReleasePage struct {
+	Weeks      []*ReleaseWeek
+	NextBefore int64
+}
+
+// ReleaseService 新歌速递与新碟上架：按歌曲发布时间和专辑发行时间倒序、按周分组，
+// 支持语种和地区筛选，登录用户还可以只看关注歌手的新作品
+type ReleaseService struct {
+	releaseRepo  repo.ReleaseRepo
+	settingsRepo repo.UserSettingsRepo
+	opts         ReleaseOptions
+}
+
+func NewReleaseService(releaseRepo repo.ReleaseRepo, settingsRepo repo.UserSettingsRepo, opts ReleaseOptions) *ReleaseService {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	return &ReleaseService{releaseRepo: releaseRepo, settingsRepo: settingsRepo, opts: opts}
+}
+
+// NewSongs 新歌速递
+func (s *ReleaseService) NewSongs(ctx context.Context, filter ReleaseFilter) (*ReleasePage, error) {
+	query, err := s.query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	songs, next, err := s.listSongs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.group(songs, nil, next), nil
+}
+
+// NewAlbums 新碟上架
+func (s *ReleaseService) NewAlbums(ctx context.Context, filter ReleaseFilter) (*ReleasePage, error) {
+	query, err := s.query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	albums, next, err := s.listAlbums(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.group(nil, albums, next), nil
+}
+
+// Following 关注的歌手发布的新歌和新碟，没有关注歌手时返回空列表
+func (s *ReleaseService) Following(ctx context.Context, filter ReleaseFilter) (*ReleasePage, error) {
+	query, err := s.query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	query.ArtistIDs, err = s.releaseRepo.ListFollowedArtists(ctx, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(query.ArtistIDs) == 0 {
+		return &ReleasePage{Weeks: []*ReleaseWeek{}}, nil
+	}
+	songs, songNext, err := s.listSongs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	albums, albumNext, err := s.listAlbums(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// 任一部分被截断时，下一页从较晚的截断点继续，另一部分早于该点的作品留到下一页，
+	// 下一页查询早于该点的作品，两部分都不会遗漏
+	return s.group(songs, albums, max(songNext, albumNext)), nil
+}
+
+// query 校验筛选条件，把 Before 和 Weeks 换算为按周对齐的时间窗口
+func (s *ReleaseService) query(ctx context.Context, filter ReleaseFilter) (repo.ReleaseQuery, error) {
+	if filter.Language != "" && !slices.Contains(domain.Languages, filter.Language) {
+		return repo.ReleaseQuery{}, fmt.Errorf("%w: unknown language %s", ErrInvalidReleaseFilter, filter.Language)
+	}
+	if filter.Weeks == 0 {
+		filter.Weeks = defaultReleaseWeeks
+	}
+	if filter.Weeks < 0 || filter.Weeks > maxReleaseWeeks {
+		return repo.ReleaseQuery{}, fmt.Errorf("%w: weeks must be between 1 and %d", ErrInvalidReleaseFilter, maxReleaseWeeks)
+	}
+	until := time.Now()
+	if filter.Before > 0 {
+		until = time.Unix(filter.Before, 0)
+	}
+	// 窗口起点对齐到周一零点，保证每周的分组完整
+	since := s.weekStart(until.Add(-time.Second)).AddDate(0, 0, -7*(filter.Weeks-1))
+
+	region := filter.Region
+	if region == "" && filter.UserID != "" {
+		settings, err := s.settingsRepo.GetUserSettings(ctx, filter.UserID)
+		if err != nil {
+			return repo.ReleaseQuery{}, err
+		}
+		if settings != nil {
+			region = settings.Region
+		}
+	}
+	return repo.ReleaseQuery{
+		Since:    since.Unix(),
+		Until:    until.Unix(),
+		Language: filter.Language,
+		Region:   region,
+		Limit:    maxReleaseItems,
+	}, nil
+}
+
+func (s *ReleaseService) listSongs(ctx context.Context, query repo.ReleaseQuery) ([]*repo.Song, int64, error) {
+	songs, err := s.releaseRepo.ListNewSongs(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	times := make([]int64, 0, len(songs))
+	for _, song := range songs {
+		times = append(times, song.PublishTime)
+	}
+	n, next := pageCut(times, query)
+	return songs[:n], next, nil
+}
+
+func (s *ReleaseService) listAlbums(ctx context.Context, query repo.ReleaseQuery) ([]*repo.Album, int64, error) {
+	albums, err := s.releaseRepo.ListNewAlbums(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	times := make([]int64, 0, len(albums))
+	for _, album := range albums {
+		times = append(times, album.ReleaseTime)
+	}
+	n, next := pageCut(times, query)
+	return albums[:n], next, nil
+}
+
+// pageCut 结果达到上限时窗口内可能还有更早的作品：去掉与最早一条同一时刻的作品，
+// 下一页从该时刻之后一秒继续（查询条件为早于 Before），同一时刻发布的作品整体留到下一页；
+// 未达到上限时下一页从窗口起点继续
+func pageCut(times []int64, query repo.ReleaseQuery) (int, int64) {
+	if len(times) < query.Limit {
+		return len(times), query.Since
+	}
+	oldest := times[len(times)-1]
+	n := len(times)
+	for n > 0 && times[n-1] == oldest {
+		n--
+	}
+	if n == 0 {
+		// 同一时刻的作品超过上限，只能跳过其余部分
+		return len(times), oldest
+	}
+	return n, oldest + 1
+}
+
+// group 按周分组，早于 next 的作品属于下一页，不在本页返回
+func (s *ReleaseService) group(songs []*repo.Song, albums []*repo.Album, next int64) *ReleasePage {
+	weeks := make(map[string]*ReleaseWeek)
+	page := &ReleasePage{Weeks: []*ReleaseWeek{}, NextBefore: next}
+	week := func(releaseTime int64) *ReleaseWeek {
+		date := s.weekStart(time.Unix(releaseTime, 0)).Format(time.DateOnly)
+		w, ok := weeks[date]
+		if !ok {
+			w = &ReleaseWeek{Week: date, Songs: []*repo.Song{}, Albums: []*repo.Album{}}
+			weeks[date] = w
+			page.Weeks = append(page.Weeks, w)
+		}
+		return w
+	}
+	for _, song := range songs {
+		if song.PublishTime >= next {
+			w := week(song.PublishTime)
+			w.Songs = append(w.Songs, song)
+		}
+	}
+	for _, album := range albums {
+		if album.ReleaseTime >= next {
+			w := week(album.ReleaseTime)
+			w.Albums = append(w.Albums, album)
+		}
+	}
+	slices.SortFunc(page.Weeks, func(a, b *ReleaseWeek) int { return cmp.Compare(b.Week, a.Week) })
+	return page
+}
+
+// weekStart t 所在周的周一零点
+func (s *ReleaseService) weekStart(t time.Time) time.Time {
+	local := t.In(s.opts.Location)
+	offset := (int(local.Weekday()) + 6) % 7
+	return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, s.opts.Location)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"wyy/internal/repo/discover"
+)
+
+// 曝光后多长时间内的正向行为算作点击（秒）
+const defaultLabelWindow = 24 * 3600
+
+// RankTrainer 离线训练：读取曝光日志，结合用户后续行为打标签
+type RankTrainer struct {
+	impressionRepo repo.ImpressionRepo
+	userActionRepo repo.UserActionRepo
+	labelWindow    int64
+}
+
+func NewRankTrainer(impressionRepo repo.ImpressionRepo, userActionRepo repo.UserActionRepo) *RankTrainer {
+	return &RankTrainer{
+		impressionRepo: impressionRepo,
+		userActionRepo: userActionRepo,
+		labelWindow:    defaultLabelWindow,
+	}
+}
+
+// BuildSamples 构造 [since, until) 时间段内曝光的训练样本
+func (t *RankTrainer) BuildSamples(ctx context.Context, since, until int64, limit int) ([]TrainingSample, error) {
+	impressions, err := t.impressionRepo.ListImpressions(ctx, since, until, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, imp := range impressions {
+		if !seen[imp.UserID] {
+			seen[imp.UserID] = true
+			userIDs = append(userIDs, imp.UserID)
+		}
+	}
+	actionsMap, err := t.userActionRepo.BatchGetUserActions(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]TrainingSample, 0, len(impressions))
+	for _, imp := range impressions {
+		// 特征维度与当前代码不一致的旧日志直接跳过
+		if len(imp.Features) != len(FeatureNames) {
+			continue
+		}
+		samples = append(samples, TrainingSample{
+			Features: imp.Features,
+			Label:    t.label(imp, actionsMap[imp.UserID]),
+		})
+	}
+	return samples, nil
+}
+
+// label 曝光后窗口期内有播放或收藏记为正样本，跳过或无行为记为负样本
+func (t *RankTrainer) label(imp *repo.Impression, actions []*repo.UserAction) float64 {
+	label := 0.0
+	for _, action := range actions {
+		if action.SongID != imp.SongID || action.Timestamp < imp.ShownAt || action.Timestamp > imp.ShownAt+t.labelWindow {
+			continue
+		}
+		switch action.Action {
+		case "skip":
+			return 0
+		case "play", "like":
+			label = 1
+		}
+	}
+	return label
+}
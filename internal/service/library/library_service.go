@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wyy/internal/domain"
+	catalog "wyy/internal/repo/catalog"
+	repo "wyy/internal/repo/library"
+	playlist "wyy/internal/repo/playlist"
+)
+
+var (
+	ErrInvalidSubscriptionType = errors.New("invalid subscription type")
+	ErrTargetNotFound          = errors.New("target not found")
+	ErrSubscribeOwnPlaylist    = errors.New("cannot subscribe to own playlist")
+)
+
+// LikedSong 喜欢的歌曲及喜欢时间
+type LikedSong struct {
+	Song    *domain.Song
+	LikedAt int64
+}
+
+// SubscriptionPage 一页收藏，只有与 Type 对应的列表有值；已删除的目标被跳过
+type SubscriptionPage struct {
+	Type      string
+	Total     int64
+	Playlists []*domain.Playlist
+	Albums    []*domain.Album
+	Artists   []*domain.Artist
+}
+
+// LibraryService 我喜欢的音乐与收藏
+type LibraryService struct {
+	libraryRepo  *repo.LibraryRepo
+	songRepo     *catalog.SongRepo
+	albumRepo    *catalog.AlbumRepo
+	artistRepo   *catalog.ArtistRepo
+	playlistRepo *playlist.PlaylistRepo
+	onSongLiked  func(userID, songID int64) // 可选，喜欢歌曲后通知推荐模块
+}
+
+func NewLibraryService(libraryRepo *repo.LibraryRepo, songRepo *catalog.SongRepo, albumRepo *catalog.AlbumRepo, artistRepo *catalog.ArtistRepo, playlistRepo *playlist.PlaylistRepo) *LibraryService {
+	return &LibraryService{
+		libraryRepo:  libraryRepo,
+		songRepo:     songRepo,
+		albumRepo:    albumRepo,
+		artistRepo:   artistRepo,
+		playlistRepo: playlistRepo,
+	}
+}
+
+// LikeSong 喜欢歌曲，重复喜欢不报错
+func (s *LibraryService) LikeSong(ctx context.Context, userID, songID int64) error {
+	song, err := s.songRepo.GetByID(ctx, songID)
+	if err != nil {
+		return err
+	}
+	if song == nil || (song.OwnerID != 0 && song.OwnerID != userID) {
+		return ErrTargetNotFound
+	}
+	_, err = s.libraryRepo.LikeSong(ctx, &domain.SongLike{UserID: userID, SongID: songID, CreatedAt: time.Now().Unix()})
+	if err != nil {
+		return err
+	}
+	if s.onSongLiked != nil {
+		s.onSongLiked(userID, songID)
+	}
+	return nil
+}
+
+// OnSongLiked 设置喜欢歌曲后的回调，推荐模块据此更新已听缓存
+func (s *LibraryService) OnSongLiked(fn func(userID, songID int64)) {
+	s.onSongLiked = fn
+}
+
+// UnlikeSong 取消喜欢，未喜欢时不报错
+func (s *LibraryService) UnlikeSong(ctx context.Context, userID, songID int64) error {
+	_, err := s.libraryRepo.UnlikeSong(ctx, userID, songID)
+	return err
+}
+
+// ListLikedSongs 分页获取喜欢的歌曲，最近喜欢的在前
+func (s *LibraryService) ListLikedSongs(ctx context.Context, userID int64, page, pageSize int) ([]*LikedSong, int64, error) {
+	likes, total, err := s.libraryRepo.ListLikes(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]int64, len(likes))
+	for i, like := range likes {
+		ids[i] = like.SongID
+	}
+	songs, err := s.songRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, songs); err != nil {
+		return nil, 0, err
+	}
+	songMap := make(map[int64]*domain.Song, len(songs))
+	for _, song := range songs {
+		songMap[song.ID] = song
+	}
+	result := make([]*LikedSong, 0, len(likes))
+	for _, like := range likes {
+		if song, ok := songMap[like.SongID]; ok {
+			result = append(result, &LikedSong{Song: song, LikedAt: like.CreatedAt})
+		}
+	}
+	return result, total, nil
+}
+
+// LikedSongIDs 用户喜欢的全部歌曲 ID，供客户端标记红心
+func (s *LibraryService) LikedSongIDs(ctx context.Context, userID int64) ([]int64, error) {
+	return s.libraryRepo.LikedSongIDs(ctx, userID)
+}
+
+// Subscribe 收藏歌单、专辑或关注歌手，重复收藏不报错
+func (s *LibraryService) Subscribe(ctx context.Context, userID int64, targetType string, targetID int64) error {
+	if err := s.checkTarget(ctx, userID, targetType, targetID); err != nil {
+		return err
+	}
+	_, err := s.libraryRepo.Subscribe(ctx, &domain.Subscription{
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		CreatedAt:  time.Now().Unix(),
+	})
+	return err
+}
+
+// Unsubscribe 取消收藏，未收藏时不报错
+func (s *LibraryService) Unsubscribe(ctx context.Context, userID int64, targetType string, targetID int64) error {
+	if !validSubscriptionType(targetType) {
+		return ErrInvalidSubscriptionType
+	}
+	_, err := s.libraryRepo.Unsubscribe(ctx, userID, targetType, targetID)
+	return err
+}
+
+// ListSubscriptions 分页获取某类收藏
+func (s *LibraryService) ListSubscriptions(ctx context.Context, userID int64, targetType string, page, pageSize int) (*SubscriptionPage, error) {
+	if !validSubscriptionType(targetType) {
+		return nil, ErrInvalidSubscriptionType
+	}
+	subs, total, err := s.libraryRepo.ListSubscriptions(ctx, userID, targetType, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(subs))
+	for i, sub := range subs {
+		ids[i] = sub.TargetID
+	}
+
+	result := &SubscriptionPage{Type: targetType, Total: total}
+	switch targetType {
+	case domain.SubscribePlaylist:
+		var playlists []*domain.Playlist
+		if playlists, err = s.playlistRepo.GetByIDs(ctx, ids); err == nil {
+			// 已被创建者设为非公开的歌单不再展示
+			result.Playlists = orderByIDs(ids, playlists, func(p *domain.Playlist) int64 { return p.ID },
+				func(p *domain.Playlist) bool { return p.Public || p.UserID == userID })
+		}
+	case domain.SubscribeAlbum:
+		var albums []*domain.Album
+		if albums, err = s.albumRepo.GetByIDs(ctx, ids); err == nil {
+			result.Albums = orderByIDs(ids, albums, func(a *domain.Album) int64 { return a.ID }, nil)
+		}
+	case domain.SubscribeArtist:
+		var artists []*domain.Artist
+		if artists, err = s.artistRepo.GetByIDs(ctx, ids); err == nil {
+			result.Artists = orderByIDs(ids, artists, func(a *domain.Artist) int64 { return a.ID }, nil)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkTarget 校验收藏目标存在；非公开歌单视为不存在，不能收藏自己的歌单
+func (s *LibraryService) checkTarget(ctx context.Context, userID int64, targetType string, targetID int64) error {
+	switch targetType {
+	case domain.SubscribePlaylist:
+		pl, err := s.playlistRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if pl == nil || (!pl.Public && pl.UserID != userID) {
+			return ErrTargetNotFound
+		}
+		if pl.UserID == userID {
+			return ErrSubscribeOwnPlaylist
+		}
+	case domain.SubscribeAlbum:
+		album, err := s.albumRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if album == nil {
+			return ErrTargetNotFound
+		}
+	case domain.SubscribeArtist:
+		artist, err := s.artistRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if artist == nil {
+			return ErrTargetNotFound
+		}
+	default:
+		return ErrInvalidSubscriptionType
+	}
+	return nil
+}
+
+func validSubscriptionType(targetType string) bool {
+	switch targetType {
+	case domain.SubscribePlaylist, domain.SubscribeAlbum, domain.SubscribeArtist:
+		return true
+	}
+	return false
+}
+
+// orderByIDs 按 ids 顺序排列，缺失或 keep 返回 false 的跳过
+func orderByIDs[T any](ids []int64, rows []*T, idOf func(*T) int64, keep func(*T) bool) []*T {
+	byID := make(map[int64]*T, len(rows))
+	for _, row := range rows {
+		byID[idOf(row)] = row
+	}
+	ordered := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		if row, ok := byID[id]; ok && (keep == nil || keep(row)) {
+			ordered = append(ordered, row)
+		}
+	}
+	return ordered
+}
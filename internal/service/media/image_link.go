@@ -0,0 +1,60 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"time"
+	"wyy/internal/urlsign"
+)
+
+// ImageParam 缩略图规格参数，客户端可自行追加，不参与签名
+const ImageParam = "param"
+
+// ImageLinker 为本站图片地址附加签名防止盗链，其他地址原样返回。
+// 过期时间按有效期取整，同一时段内同一图片的链接不变，客户端缓存可以命中
+type ImageLinker struct {
+	signer *urlsign.Signer
+}
+
+func NewImageLinker(signer *urlsign.Signer) *ImageLinker {
+	return &ImageLinker{signer: signer}
+}
+
+// Link 返回签名后的图片地址；l 为 nil 时原样返回，供管理后台等需要存储地址的场景使用
+func (l *ImageLinker) Link(raw string) string {
+	if l == nil || !strings.HasPrefix(raw, ImageURL("")) {
+		return raw
+	}
+	path, query := splitImageURL(raw)
+	param := query.Get(ImageParam)
+	query.Del(ImageParam)
+	ttl := l.signer.TTL()
+	// 剩余有效期在 ttl 到 2*ttl 之间
+	link, _ := l.signer.Sign(path, query, 0, time.Now().Truncate(ttl).Add(ttl))
+	if param != "" {
+		link += "&" + ImageParam + "=" + url.QueryEscape(param)
+	}
+	return link
+}
+
+// StoredImageURL 去掉本站图片地址上的签名参数，客户端回传签名链接时按原始地址保存
+func StoredImageURL(raw string) string {
+	if !strings.HasPrefix(raw, ImageURL("")) {
+		return raw
+	}
+	path, query := splitImageURL(raw)
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// splitImageURL 拆分路径和查询参数，去掉签名相关参数
+func splitImageURL(raw string) (string, url.Values) {
+	path, rawQuery, _ := strings.Cut(raw, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	for _, key := range []string{urlsign.ParamExpires, urlsign.ParamUserID, urlsign.ParamSig} {
+		query.Del(key)
+	}
+	return path, query
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"wyy/internal/imaging"
+	"wyy/internal/storage"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrInvalidImage  = errors.New("invalid image")
+	ErrImageTooLarge = errors.New("image too large")
+	ErrImageNotFound = errors.New("image not found")
+)
+
+// MaxImageSize 单张图片上限
+const MaxImageSize = 10 << 20
+
+const (
+	imagePrefix   = "images/"         // 图片在存储中的 key 前缀
+	variantPrefix = "image-variants/" // 缩略图和转码结果的 key 前缀，按原图名分目录
+)
+
+// 允许上传的图片类型及扩展名
+var imageExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ImageService 图片上传与读取。图片按内容哈希命名，同一张图只存一份，URL 内容不变可长期缓存；
+// 缩略图在首次请求时生成并写入存储，之后直接读取
+type ImageService struct {
+	storage storage.Storage
+	group   singleflight.Group // 合并同一缩略图的并发生成
+}
+
+func NewImageService(store storage.Storage) *ImageService {
+	return &ImageService{storage: store}
+}
+
+// Upload 校验图片类型并写入存储，返回的对象 Key 不含 images/ 前缀
+func (s *ImageService) Upload(ctx context.Context, r io.Reader) (*storage.Object, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImageSize {
+		return nil, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExts[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported type %s", ErrInvalidImage, contentType)
+	}
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:16]) + ext
+
+	obj, err := s.storage.Put(ctx, imagePrefix+name, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return nil, err
+	}
+	obj.Key = name
+	return obj, nil
+}
+
+// ImageURL 图片的访问地址
+func ImageURL(name string) string {
+	return "/api/images/" + name
+}
+
+// Open 打开图片，name 为 Upload 返回的 Key，调用方负责关闭 Reader
+func (s *ImageService) Open(ctx context.Context, name string) (io.ReadSeekCloser, *storage.Object, error) {
+	name, ok := cleanName(name)
+	if !ok {
+		return nil, nil, ErrImageNotFound
+	}
+	return s.open(ctx, imagePrefix+name)
+}
+
+// OpenVariant 按 spec 缩放裁剪后打开图片。acceptWebP 为 false 时 WebP 原图转为 JPEG/PNG；
+// 无需处理时返回原图。缩略图不透明时输出 JPEG，否则输出 PNG
+func (s *ImageService) OpenVariant(ctx context.Context, name string, spec imaging.Spec, acceptWebP bool) (io.ReadSeekCloser, *storage.Object, error) {
+	name, ok := cleanName(name)
+	if !ok {
+		return nil, nil, ErrImageNotFound
+	}
+	ext := path.Ext(name)
+	if spec.IsZero() && (ext != ".webp" || acceptWebP) {
+		return s.open(ctx, imagePrefix+name)
+	}
+	tag := "orig"
+	if !spec.IsZero() {
+		tag = spec.String()
+	}
+	base := variantPrefix + name + "/" + tag
+	// JPEG 原图的缩略图一定是 JPEG，其他格式先找 PNG
+	candidates := []string{base + ".png", base + ".jpg"}
+	if ext == ".jpg" {
+		candidates = []string{base + ".jpg"}
+	}
+	for _, key := range candidates {
+		reader, obj, err := s.open(ctx, key)
+		if !errors.Is(err, ErrImageNotFound) {
+			return reader, obj, err
+		}
+	}
+
+	// 生成结果由并发请求共享，不随发起请求的取消而中断
+	key, err, _ := s.group.Do(base, func() (any, error) {
+		return s.createVariant(context.WithoutCancel(ctx), name, base, spec)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.open(ctx, key.(string))
+}
+
+// createVariant 生成缩略图写入存储，返回其 key
+func (s *ImageService) createVariant(ctx context.Context, name, base string, spec imaging.Spec) (string, error) {
+	data, err := s.read(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	img = imaging.Resize(img, spec)
+	format, key := imaging.FormatPNG, base+".png"
+	if path.Ext(name) == ".jpg" || imaging.Opaque(img) {
+		format, key = imaging.FormatJPEG, base+".jpg"
+	}
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format); err != nil {
+		return "", err
+	}
+	if _, err := s.storage.Put(ctx, key, &buf, int64(buf.Len()), "image/"+format); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// CoverColor 封面主色（#rrggbb），供客户端做主题色。只处理本站上传的图片，其他地址或无法解码时返回空字符串
+func (s *ImageService) CoverColor(ctx context.Context, url string) (string, error) {
+	name, ok := strings.CutPrefix(url, ImageURL(""))
+	if !ok {
+		return "", nil
+	}
+	name, _, _ = strings.Cut(name, "?")
+	if name, ok = cleanName(name); !ok {
+		return "", nil
+	}
+	data, err := s.read(ctx, name)
+	if errors.Is(err, ErrImageNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return "", nil
+	}
+	c, ok := imaging.DominantColor(img)
+	if !ok {
+		return "", nil
+	}
+	return imaging.HexColor(c), nil
+}
+
+// read 读取原图全部内容
+func (s *ImageService) read(ctx context.Context, name string) ([]byte, error) {
+	reader, _, err := s.open(ctx, imagePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, MaxImageSize+1))
+}
+
+func (s *ImageService) open(ctx context.Context, key string) (io.ReadSeekCloser, *storage.Object, error) {
+	reader, obj, err := s.storage.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, nil, ErrImageNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader, obj, nil
+}
+
+// cleanName 图片名不能包含路径
+func cleanName(name string) (string, bool) {
+	name = strings.TrimPrefix(name, "/")
+	return name, name != "" && !strings.Contains(name, "/")
+}
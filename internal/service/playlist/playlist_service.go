@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"wyy/internal/domain"
+	catalog "wyy/internal/repo/catalog"
+	repo "wyy/internal/repo/playlist"
+	media "wyy/internal/service/media"
+)
+
+var (
+	ErrPlaylistNotFound  = errors.New("playlist not found")
+	ErrPlaylistForbidden = errors.New("playlist belongs to another user")
+	ErrInvalidPlaylist   = errors.New("invalid playlist")
+	ErrSongNotInPlaylist = repo.ErrSongNotInPlaylist
+)
+
+// 歌单限制
+const (
+	MaxPlaylistSongs     = 1000
+	MaxPlaylistBatch     = 100 // 单次增删歌曲数
+	maxPlaylistNameLen   = 64
+	maxPlaylistDescLen   = 1000
+	maxPlaylistTags      = 5
+	maxPlaylistTagLength = 16
+)
+
+// PlaylistDetail 歌单页：歌单信息和按顺序排列的歌曲
+type PlaylistDetail struct {
+	Playlist *domain.Playlist
+	Songs    []*domain.Song
+}
+
+type PlaylistService struct {
+	playlistRepo *repo.PlaylistRepo
+	songRepo     *catalog.SongRepo
+	imageService *media.ImageService
+}
+
+func NewPlaylistService(playlistRepo *repo.PlaylistRepo, songRepo *catalog.SongRepo, imageService *media.ImageService) *PlaylistService {
+	return &PlaylistService{playlistRepo: playlistRepo, songRepo: songRepo, imageService: imageService}
+}
+
+// Create 创建歌单，创建者为 userID
+func (s *PlaylistService) Create(ctx context.Context, userID int64, playlist *domain.Playlist) error {
+	if err := validatePlaylist(playlist); err != nil {
+		return err
+	}
+	playlist.ID = 0
+	playlist.UserID = userID
+	playlist.SongCount = 0
+	playlist.PlayCount = 0
+	color, err := s.imageService.CoverColor(ctx, playlist.CoverURL)
+	if err != nil {
+		return err
+	}
+	playlist.CoverColor = color
+	return s.playlistRepo.Create(ctx, playlist)
+}
+
+// GetDetail 获取歌单详情；非公开歌单对其他用户视为不存在
+func (s *PlaylistService) GetDetail(ctx context.Context, userID, id int64) (*PlaylistDetail, error) {
+	playlist, err := s.playlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil || (!playlist.Public && playlist.UserID != userID) {
+		return nil, ErrPlaylistNotFound
+	}
+	ids, err := s.playlistRepo.ListSongIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	songs, err := s.songRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, songs); err != nil {
+		return nil, err
+	}
+	// 创建者加入的云盘私有歌曲只对创建者本人展示
+	songMap := make(map[int64]*domain.Song, len(songs))
+	for _, song := range songs {
+		if song.OwnerID == 0 || song.OwnerID == userID {
+			songMap[song.ID] = song
+		}
+	}
+	ordered := make([]*domain.Song, 0, len(ids))
+	for _, songID := range ids {
+		if song, ok := songMap[songID]; ok {
+			ordered = append(ordered, song)
+		}
+	}
+	return &PlaylistDetail{Playlist: playlist, Songs: ordered}, nil
+}
+
+// ListByUser 用户创建的歌单，本人可以看到非公开歌单
+func (s *PlaylistService) ListByUser(ctx context.Context, viewerID, ownerID int64, page, pageSize int) ([]*domain.Playlist, int64, error) {
+	return s.playlistRepo.ListByUser(ctx, ownerID, viewerID == ownerID, page, pageSize)
+}
+
+// Update 更新歌单信息，只有创建者可以修改
+func (s *PlaylistService) Update(ctx context.Context, userID int64, playlist *domain.Playlist) error {
+	current, err := s.owned(ctx, userID, playlist.ID)
+	if err != nil {
+		return err
+	}
+	if err := validatePlaylist(playlist); err != nil {
+		return err
+	}
+	// 封面不变时沿用已提取的主色
+	playlist.CoverColor = current.CoverColor
+	if playlist.CoverURL != current.CoverURL {
+		if playlist.CoverColor, err = s.imageService.CoverColor(ctx, playlist.CoverURL); err != nil {
+			return err
+		}
+	}
+	if err := s.playlistRepo.UpdateInfo(ctx, playlist); err != nil {
+		return err
+	}
+	playlist.UserID = current.UserID
+	playlist.SongCount = current.SongCount
+	playlist.PlayCount = current.PlayCount
+	playlist.CreatedAt = current.CreatedAt
+	return nil
+}
+
+// Delete 删除歌单，只有创建者可以删除
+func (s *PlaylistService) Delete(ctx context.Context, userID, id int64) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.playlistRepo.Delete(ctx, id)
+}
+
+// AddSongs 追加歌曲到歌单末尾，不存在的歌曲和已在歌单中的歌曲被忽略，返回实际添加数量
+func (s *PlaylistService) AddSongs(ctx context.Context, userID, id int64, songIDs []int64) (int, error) {
+	playlist, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	if len(songIDs) == 0 || len(songIDs) > MaxPlaylistBatch {
+		return 0, fmt.Errorf("%w: song_ids must contain 1-%d songs", ErrInvalidPlaylist, MaxPlaylistBatch)
+	}
+	songs, err := s.songRepo.GetByIDs(ctx, songIDs)
+	if err != nil {
+		return 0, err
+	}
+	exists := make(map[int64]bool, len(songs))
+	for _, song := range songs {
+		// 云盘私有歌曲只能由上传者加入歌单
+		exists[song.ID] = song.OwnerID == 0 || song.OwnerID == userID
+	}
+	valid := make([]int64, 0, len(songIDs))
+	for _, songID := range songIDs {
+		if exists[songID] {
+			valid = append(valid, songID)
+		}
+	}
+	if len(valid) == 0 {
+		return 0, nil
+	}
+	if playlist.SongCount+len(valid) > MaxPlaylistSongs {
+		return 0, fmt.Errorf("%w: a playlist holds at most %d songs", ErrInvalidPlaylist, MaxPlaylistSongs)
+	}
+	return s.playlistRepo.AddSongs(ctx, id, valid)
+}
+
+// RemoveSongs 从歌单移除歌曲，返回实际移除数量
+func (s *PlaylistService) RemoveSongs(ctx context.Context, userID, id int64, songIDs []int64) (int, error) {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return 0, err
+	}
+	if len(songIDs) == 0 || len(songIDs) > MaxPlaylistBatch {
+		return 0, fmt.Errorf("%w: song_ids must contain 1-%d songs", ErrInvalidPlaylist, MaxPlaylistBatch)
+	}
+	return s.playlistRepo.RemoveSongs(ctx, id, songIDs)
+}
+
+// MoveSong 拖动排序：将歌曲移到 afterSongID 之后，afterSongID 为 0 时移到最前
+func (s *PlaylistService) MoveSong(ctx context.Context, userID, id, songID, afterSongID int64) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	if songID == afterSongID {
+		return fmt.Errorf("%w: cannot move a song after itself", ErrInvalidPlaylist)
+	}
+	return s.playlistRepo.MoveSong(ctx, id, songID, afterSongID)
+}
+
+// owned 查询歌单并校验创建者
+func (s *PlaylistService) owned(ctx context.Context, userID, id int64) (*domain.Playlist, error) {
+	playlist, err := s.playlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil || (!playlist.Public && playlist.UserID != userID) {
+		return nil, ErrPlaylistNotFound
+	}
+	if playlist.UserID != userID {
+		return nil, ErrPlaylistForbidden
+	}
+	return playlist, nil
+}
+
+// validatePlaylist 校验并规整歌单信息
+func validatePlaylist(playlist *domain.Playlist) error {
+	playlist.Name = strings.TrimSpace(playlist.Name)
+	switch {
+	case playlist.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidPlaylist)
+	case utf8.RuneCountInString(playlist.Name) > maxPlaylistNameLen:
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidPlaylist, maxPlaylistNameLen)
+	case utf8.RuneCountInString(playlist.Description) > maxPlaylistDescLen:
+		return fmt.Errorf("%w: description exceeds %d characters", ErrInvalidPlaylist, maxPlaylistDescLen)
+	case len(playlist.Tags) > maxPlaylistTags:
+		return fmt.Errorf("%w: at most %d tags", ErrInvalidPlaylist, maxPlaylistTags)
+	}
+	tags := make([]string, 0, len(playlist.Tags))
+	for _, tag := range playlist.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxPlaylistTagLength {
+			return fmt.Errorf("%w: tag %q exceeds %d characters", ErrInvalidPlaylist, tag, maxPlaylistTagLength)
+		}
+		tags = append(tags, tag)
+	}
+	playlist.Tags = tags
+	return nil
+}
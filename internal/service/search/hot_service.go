@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"wyy/internal/cache"
+	repo "wyy/internal/repo/search"
+	"wyy/internal/search"
+)
+
+var (
+	ErrBlockedTermNotFound = errors.New("blocked term not found")
+	ErrInvalidBlockedTerm  = errors.New("invalid blocked term")
+)
+
+const (
+	maxQueryLength = 64 // 与 search_logs.query 列长度一致
+	hotCacheKey    = "hot"
+)
+
+// HotSearchOptions 热搜计算参数
+type HotSearchOptions struct {
+	Window   time.Duration // 统计窗口
+	HalfLife time.Duration // 热度半衰期，越早的搜索权重越低
+	Size     int           // 热搜榜长度
+}
+
+// HotKeyword 热搜词
+type HotKeyword struct {
+	Query string
+	Score float64
+}
+
+// HotSearchService 记录搜索词并按时间衰减计算热搜榜，结果缓存几分钟
+type HotSearchService struct {
+	hotRepo *repo.HotSearchRepo
+	opts    HotSearchOptions
+	cache   *cache.Memory[[]*HotKeyword]
+}
+
+func NewHotSearchService(hotRepo *repo.HotSearchRepo, opts HotSearchOptions) *HotSearchService {
+	if opts.Window <= 0 {
+		opts.Window = 7 * 24 * time.Hour
+	}
+	if opts.HalfLife <= 0 {
+		opts.HalfLife = 24 * time.Hour
+	}
+	if opts.Size <= 0 {
+		opts.Size = 20
+	}
+	return &HotSearchService{
+		hotRepo: hotRepo,
+		opts:    opts,
+		cache:   cache.NewMemory[[]*HotKeyword](5*time.Minute, 1),
+	}
+}
+
+// Log 异步记录一次搜索，失败只打日志
+func (s *HotSearchService) Log(ctx context.Context, userID int64, query string) {
+	query = normalizeQuery(query)
+	if query == "" {
+		return
+	}
+	entry := &repo.SearchLog{UserID: userID, Query: query, CreatedAt: time.Now().Unix()}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := s.hotRepo.LogSearch(ctx, entry); err != nil {
+			log.Printf("log search %q: %v", query, err)
+		}
+	}()
+}
+
+// Hot 返回热搜榜：窗口内每次搜索按距今时间指数衰减后求和，过滤包含屏蔽词的搜索词
+func (s *HotSearchService) Hot(ctx context.Context) ([]*HotKeyword, error) {
+	if hot, ok := s.cache.Get(hotCacheKey); ok {
+		return hot, nil
+	}
+
+	now := time.Now()
+	counts, err := s.hotRepo.CountByHour(ctx, now.Add(-s.opts.Window).Unix())
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := s.hotRepo.ListBlockedTerms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]float64)
+	halfLifeHours := s.opts.HalfLife.Hours()
+	for _, c := range counts {
+		// 以小时中点近似该小时内的搜索时间
+		age := float64(now.Unix())/3600 - (float64(c.Hour) + 0.5)
+		scores[c.Query] += float64(c.Count) * math.Exp2(-math.Max(age, 0)/halfLifeHours)
+	}
+
+	hot := make([]*HotKeyword, 0, len(scores))
+	for query, score := range scores {
+		if isBlocked(query, blocked) {
+			continue
+		}
+		hot = append(hot, &HotKeyword{Query: query, Score: score})
+	}
+	sort.Slice(hot, func(i, j int) bool {
+		if hot[i].Score != hot[j].Score {
+			return hot[i].Score > hot[j].Score
+		}
+		return hot[i].Query < hot[j].Query
+	})
+	if len(hot) > s.opts.Size {
+		hot = hot[:s.opts.Size]
+	}
+	s.cache.Set(hotCacheKey, hot)
+	return hot, nil
+}
+
+// ListBlockedTerms 屏蔽词列表（管理后台）
+func (s *HotSearchService) ListBlockedTerms(ctx context.Context) ([]*repo.BlockedTerm, error) {
+	return s.hotRepo.ListBlockedTerms(ctx)
+}
+
+// BlockTerm 添加屏蔽词，热搜榜立即刷新
+func (s *HotSearchService) BlockTerm(ctx context.Context, term string) (*repo.BlockedTerm, error) {
+	term = normalizeQuery(term)
+	if term == "" {
+		return nil, ErrInvalidBlockedTerm
+	}
+	blocked, err := s.hotRepo.AddBlockedTerm(ctx, &repo.BlockedTerm{Term: term, CreatedAt: time.Now().Unix()})
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Clear()
+	return blocked, nil
+}
+
+// UnblockTerm 删除屏蔽词
+func (s *HotSearchService) UnblockTerm(ctx context.Context, id int64) error {
+	ok, err := s.hotRepo.DeleteBlockedTerm(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrBlockedTermNotFound
+	}
+	s.cache.Clear()
+	return nil
+}
+
+// normalizeQuery 统一大小写、全角和空白，超长时截断
+func normalizeQuery(query string) string {
+	query = strings.Join(strings.Fields(search.Normalize(query)), " ")
+	if runes := []rune(query); len(runes) > maxQueryLength {
+		query = string(runes[:maxQueryLength])
+	}
+	return strings.TrimSpace(query)
+}
+
+func isBlocked(query string, blocked []*repo.BlockedTerm) bool {
+	for _, term := range blocked {
+		if strings.Contains(query, term.Term) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+	"wyy/internal/domain"
+	catalog "wyy/internal/repo/catalog"
+	playlist "wyy/internal/repo/playlist"
+	repo "wyy/internal/repo/search"
+	"wyy/internal/search"
+)
+
+var ErrInvalidSearchType = errors.New("invalid search type")
+
+// 字段权重：名称 > 歌手 > 专辑
+const (
+	weightName   = 3.0
+	weightAlias  = 2.0
+	weightArtist = 1.5
+	weightAlbum  = 1.0
+	weightTag    = 1.0
+)
+
+const (
+	suggestTopK   = 10 // 前缀树每个节点保留的联想词条数
+	syncBatchSize = 500
+	syncOverlap   = 2 // 增量同步回看的秒数，避免同一秒内的更新被漏掉
+)
+
+// SearchOptions 索引同步参数
+type SearchOptions struct {
+	SyncInterval    time.Duration // 增量同步间隔
+	RebuildInterval time.Duration // 全量重建间隔，刷新热度并清理已删除数据
+}
+
+// SearchResult 一页搜索结果，只有与 Type 对应的列表有值
+type SearchResult struct {
+	Type      string
+	Total     int
+	Songs     []*domain.Song
+	Artists   []*domain.Artist
+	Albums    []*domain.Album
+	Playlists []*domain.Playlist
+}
+
+// SearchService 基于内存倒排索引的全文搜索
+type SearchService struct {
+	searchRepo   *repo.SearchRepo
+	songRepo     *catalog.SongRepo
+	artistRepo   *catalog.ArtistRepo
+	albumRepo    *catalog.AlbumRepo
+	playlistRepo *playlist.PlaylistRepo
+	opts         SearchOptions
+
+	state    atomic.Pointer[searchState]
+	mu       sync.Mutex // 串行化同步与重建
+	lastSync int64
+}
+
+func NewSearchService(searchRepo *repo.SearchRepo, songRepo *catalog.SongRepo, artistRepo *catalog.ArtistRepo, albumRepo *catalog.AlbumRepo, playlistRepo *playlist.PlaylistRepo, opts SearchOptions) *SearchService {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = 30 * time.Second
+	}
+	if opts.RebuildInterval <= 0 {
+		opts.RebuildInterval = time.Hour
+	}
+	s := &SearchService{
+		searchRepo:   searchRepo,
+		songRepo:     songRepo,
+		artistRepo:   artistRepo,
+		albumRepo:    albumRepo,
+		playlistRepo: playlistRepo,
+		opts:         opts,
+	}
+	s.state.Store(newSearchState())
+	return s
+}
+
+// searchState 倒排索引与联想前缀树，全量重建时整体替换
+type searchState struct {
+	index *search.Index
+	trie  *search.Trie
+}
+
+func newSearchState() *searchState {
+	return &searchState{index: search.NewIndex(), trie: search.NewTrie(suggestTopK)}
+}
+
+// upsert 写入索引并加入联想词条
+func (st *searchState) upsert(doc search.Doc) {
+	st.index.Upsert(doc)
+	st.trie.Insert(search.Suggestion{
+		Type:   doc.Type,
+		ID:     doc.ID,
+		Text:   doc.Fields[0].Text,
+		Weight: math.Log1p(doc.Popularity),
+	})
+}
+
+// Suggest 输入联想，返回名称以 prefix 开头（含拼音、首字母）的歌曲、歌手、专辑
+func (s *SearchService) Suggest(prefix string, limit int) []search.Suggestion {
+	state := s.state.Load()
+	// 前缀树不删除词条，已下架的歌曲按索引过滤
+	candidates := state.trie.Suggest(prefix, suggestTopK)
+	result := make([]search.Suggestion, 0, limit)
+	for _, sug := range candidates {
+		if len(result) == limit {
+			break
+		}
+		if state.index.Has(sug.Type, sug.ID) {
+			result = append(result, sug)
+		}
+	}
+	return result
+}
+
+// Search 在指定类型中检索，page 从 1 开始
+func (s *SearchService) Search(ctx context.Context, docType, query string, page, pageSize int) (*SearchResult, error) {
+	switch docType {
+	case search.TypeSong, search.TypeArtist, search.TypeAlbum, search.TypePlaylist:
+	default:
+		return nil, ErrInvalidSearchType
+	}
+	result := &SearchResult{Type: docType}
+	hits, total := s.state.Load().index.Search(docType, query, (page-1)*pageSize, pageSize)
+	result.Total = total
+	if len(hits) == 0 {
+		return result, nil
+	}
+	ids := make([]int64, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+
+	var err error
+	switch docType {
+	case search.TypeSong:
+		result.Songs, err = s.loadSongs(ctx, ids)
+	case search.TypeArtist:
+		var artists []*domain.Artist
+		if artists, err = s.artistRepo.GetByIDs(ctx, ids); err == nil {
+			result.Artists = orderByIDs(ids, artists, func(a *domain.Artist) int64 { return a.ID })
+		}
+	case search.TypeAlbum:
+		var albums []*domain.Album
+		if albums, err = s.albumRepo.GetByIDs(ctx, ids); err == nil {
+			result.Albums = orderByIDs(ids, albums, func(a *domain.Album) int64 { return a.ID })
+		}
+	case search.TypePlaylist:
+		var playlists []*domain.Playlist
+		if playlists, err = s.playlistRepo.GetByIDs(ctx, ids); err == nil {
+			result.Playlists = orderByIDs(ids, playlists, func(p *domain.Playlist) int64 { return p.ID })
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *SearchService) loadSongs(ctx context.Context, ids []int64) ([]*domain.Song, error) {
+	songs, err := s.songRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, songs); err != nil {
+		return nil, err
+	}
+	return orderByIDs(ids, songs, func(song *domain.Song) int64 { return song.ID }), nil
+}
+
+// Start 全量构建索引后定期增量同步，并按 RebuildInterval 全量重建
+func (s *SearchService) Start(ctx context.Context) {
+	if err := s.Rebuild(ctx); err != nil {
+		log.Printf("search index rebuild: %v", err)
+	}
+	syncTicker := time.NewTicker(s.opts.SyncInterval)
+	defer syncTicker.Stop()
+	rebuildTicker := time.NewTicker(s.opts.RebuildInterval)
+	defer rebuildTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-syncTicker.C:
+			if err := s.Sync(ctx); err != nil {
+				log.Printf("search index sync: %v", err)
+			}
+		case <-rebuildTicker.C:
+			if err := s.Rebuild(ctx); err != nil {
+				log.Printf("search index rebuild: %v", err)
+			}
+		}
+	}
+}
+
+// Rebuild 全量构建新索引后整体替换，构建期间旧索引继续提供服务
+func (s *SearchService) Rebuild(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	startedAt := time.Now().Unix()
+	state := newSearchState()
+	if err := s.load(ctx, state, 0); err != nil {
+		return err
+	}
+	s.state.Store(state)
+	s.lastSync = startedAt
+	index := state.index
+	log.Printf("search index rebuilt: %d songs, %d artists, %d albums, %d playlists",
+		index.Len(search.TypeSong), index.Len(search.TypeArtist), index.Len(search.TypeAlbum), index.Len(search.TypePlaylist))
+	return nil
+}
+
+// Sync 将上次同步后变更的曲库数据写入当前索引
+func (s *SearchService) Sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	startedAt := time.Now().Unix()
+	if err := s.load(ctx, s.state.Load(), s.lastSync-syncOverlap); err != nil {
+		return err
+	}
+	s.lastSync = startedAt
+	return nil
+}
+
+// load 读取 updated_at >= since 的歌曲、歌手、专辑、歌单写入索引
+func (s *SearchService) load(ctx context.Context, state *searchState, since int64) error {
+	artistPop, err := s.searchRepo.ArtistPopularity(ctx)
+	if err != nil {
+		return err
+	}
+	albumPop, err := s.searchRepo.AlbumPopularity(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := forEachBatch(ctx, since, s.searchRepo.ListSongsUpdatedSince, func(song *domain.Song) int64 { return song.ID },
+		func(songs []*domain.Song) error {
+			for _, song := range songs {
+				if song.Unavailable || song.OwnerID != 0 {
+					state.index.Delete(search.TypeSong, song.ID)
+					continue
+				}
+				state.upsert(songDoc(song))
+			}
+			return nil
+		}); err != nil {
+		return err
+	}
+
+	if err := forEachBatch(ctx, since, s.searchRepo.ListArtistsUpdatedSince, func(a *domain.Artist) int64 { return a.ID },
+		func(artists []*domain.Artist) error {
+			for _, artist := range artists {
+				state.upsert(search.Doc{
+					Type: search.TypeArtist,
+					ID:   artist.ID,
+					Fields: []search.Field{
+						{Text: artist.Name, Weight: weightName},
+						{Text: artist.Alias, Weight: weightAlias},
+					},
+					Popularity: artistPop[artist.ID],
+				})
+			}
+			return nil
+		}); err != nil {
+		return err
+	}
+
+	if err := forEachBatch(ctx, since, s.searchRepo.ListAlbumsUpdatedSince, func(a *domain.Album) int64 { return a.ID },
+		func(albums []*domain.Album) error {
+			artistIDs := make([]int64, 0, len(albums))
+			for _, album := range albums {
+				artistIDs = append(artistIDs, album.ArtistID)
+			}
+			names, err := s.searchRepo.ArtistNames(ctx, artistIDs)
+			if err != nil {
+				return err
+			}
+			for _, album := range albums {
+				state.upsert(search.Doc{
+					Type: search.TypeAlbum,
+					ID:   album.ID,
+					Fields: []search.Field{
+						{Text: album.Name, Weight: weightName},
+						{Text: names[album.ArtistID], Weight: weightArtist},
+					},
+					Popularity: albumPop[album.ID],
+				})
+			}
+			return nil
+		}); err != nil {
+		return err
+	}
+
+	// 非公开歌单不进入索引
+	return forEachBatch(ctx, since, s.searchRepo.ListPlaylistsUpdatedSince, func(p *domain.Playlist) int64 { return p.ID },
+		func(playlists []*domain.Playlist) error {
+			for _, pl := range playlists {
+				if !pl.Public {
+					state.index.Delete(search.TypePlaylist, pl.ID)
+					continue
+				}
+				fields := []search.Field{{Text: pl.Name, Weight: weightName}}
+				for _, tag := range pl.Tags {
+					fields = append(fields, search.Field{Text: tag, Weight: weightTag})
+				}
+				state.upsert(search.Doc{
+					Type:       search.TypePlaylist,
+					ID:         pl.ID,
+					Fields:     fields,
+					Popularity: float64(pl.PlayCount),
+				})
+			}
+			return nil
+		})
+}
+
+func songDoc(song *domain.Song) search.Doc {
+	return search.Doc{
+		Type: search.TypeSong,
+		ID:   song.ID,
+		Fields: []search.Field{
+			{Text: song.Name, Weight: weightName},
+			{Text: song.Artist, Weight: weightArtist},
+			{Text: song.Album, Weight: weightAlbum},
+		},
+		Popularity: float64(song.PlayCount),
+	}
+}
+
+// forEachBatch 按 ID 游标分批读取并处理
+func forEachBatch[T any](ctx context.Context, since int64,
+	list func(ctx context.Context, since, afterID int64, limit int) ([]*T, error),
+	idOf func(*T) int64, handle func([]*T) error) error {
+	var afterID int64 = math.MinInt64
+	for {
+		rows, err := list(ctx, since, afterID, syncBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := handle(rows); err != nil {
+			return err
+		}
+		if len(rows) < syncBatchSize {
+			return nil
+		}
+		afterID = idOf(rows[len(rows)-1])
+	}
+}
+
+// orderByIDs 按检索结果顺序排列，已被删除的数据跳过
+func orderByIDs[T any](ids []int64, rows []*T, idOf func(*T) int64) []*T {
+	byID := make(map[int64]*T, len(rows))
+	for _, row := range rows {
+		byID[idOf(row)] = row
+	}
+	ordered := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		if row, ok := byID[id]; ok {
+			ordered = append(ordered, row)
+		}
+	}
+	return ordered
+}
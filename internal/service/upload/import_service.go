@@ -0,0 +1,324 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"wyy/internal/audiotag"
+	"wyy/internal/domain"
+	"wyy/internal/repo/catalog"
+	media "wyy/internal/service/media"
+	"wyy/internal/storage"
+)
+
+var (
+	ErrInvalidAudio    = errors.New("invalid audio file")
+	ErrInvalidSongInfo = errors.New("invalid song info")
+	ErrSongNotFound    = errors.New("song not found")
+)
+
+// higherBitrate 有损格式达到该码率（kbps）视为较高音质
+const higherBitrate = 256
+
+// 音频格式对应的文件扩展名
+var audioExts = map[string]string{
+	audiotag.FormatMP3:  ".mp3",
+	audiotag.FormatFLAC: ".flac",
+	audiotag.FormatOgg:  ".ogg",
+	audiotag.FormatOpus: ".opus",
+	audiotag.FormatM4A:  ".m4a",
+}
+
+// SongInfo 上传时手动指定的歌曲信息，非零字段覆盖从标签解析出的值
+type SongInfo struct {
+	SongID   int64 // 为已有歌曲补充一个音质，不新建歌曲
+	Name     string
+	Artists  []string
+	Album    string
+	Language string // 新建歌曲的语种，见 domain.Languages
+	Quality  string // 为空时按格式和码率判断
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Song     *domain.Song
+	File     *domain.SongFile
+	Metadata *audiotag.Metadata
+}
+
+// ImportService 解析上传的音频并写入曲库：按标签创建歌曲、歌手和专辑，保存封面和音频文件
+type ImportService struct {
+	songRepo     *repo.SongRepo
+	artistRepo   *repo.ArtistRepo
+	albumRepo    *repo.AlbumRepo
+	songFileRepo *repo.SongFileRepo
+	imageService *media.ImageService
+	storage      storage.Storage
+}
+
+func NewImportService(songRepo *repo.SongRepo, artistRepo *repo.ArtistRepo, albumRepo *repo.AlbumRepo, songFileRepo *repo.SongFileRepo, imageService *media.ImageService, store storage.Storage) *ImportService {
+	return &ImportService{
+		songRepo:     songRepo,
+		artistRepo:   artistRepo,
+		albumRepo:    albumRepo,
+		songFileRepo: songFileRepo,
+		imageService: imageService,
+		storage:      store,
+	}
+}
+
+// Import 导入一个音频文件，fileName 用于标签中没有歌名时兜底
+func (s *ImportService) Import(ctx context.Context, r io.ReadSeeker, size int64, fileName string, info SongInfo) (*ImportResult, error) {
+	meta, err := ReadAudio(r)
+	if err != nil {
+		return nil, err
+	}
+	quality := info.Quality
+	if quality == "" {
+		quality = QualityOf(meta)
+	}
+	if !slices.Contains(domain.Qualities, quality) {
+		return nil, fmt.Errorf("%w: unknown quality %s", ErrInvalidSongInfo, quality)
+	}
+	if info.Language != "" && !slices.Contains(domain.Languages, info.Language) {
+		return nil, fmt.Errorf("%w: unknown language %s", ErrInvalidSongInfo, info.Language)
+	}
+
+	var song *domain.Song
+	if info.SongID > 0 {
+		if song, err = s.songRepo.GetByID(ctx, info.SongID); err != nil {
+			return nil, err
+		}
+		if song == nil {
+			return nil, ErrSongNotFound
+		}
+	}
+
+	obj, err := StoreAudio(ctx, s.storage, "audio/", r, size, meta)
+	if err != nil {
+		return nil, err
+	}
+	if song == nil {
+		if song, err = s.createSong(ctx, meta, info, fileName); err != nil {
+			return nil, err
+		}
+	}
+	file := &domain.SongFile{
+		SongID:   song.ID,
+		Quality:  quality,
+		Key:      obj.Key,
+		Format:   meta.Format,
+		MimeType: obj.ContentType,
+		Bitrate:  meta.Bitrate,
+		Size:     size,
+	}
+	if err := s.songFileRepo.Save(ctx, file); err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.LoadArtists(ctx, []*domain.Song{song}); err != nil {
+		return nil, err
+	}
+	return &ImportResult{Song: song, File: file, Metadata: meta}, nil
+}
+
+// createSong 按标签新建歌曲，歌手和专辑按名称复用已有记录
+func (s *ImportService) createSong(ctx context.Context, meta *audiotag.Metadata, info SongInfo, fileName string) (*domain.Song, error) {
+	name := firstNonEmpty(info.Name, meta.Title, strings.TrimSuffix(fileName, path.Ext(fileName)))
+	artistNames := info.Artists
+	if len(artistNames) == 0 {
+		artistNames = SplitArtists(firstNonEmpty(meta.Artist, meta.AlbumArtist))
+	}
+	artistIDs := make([]int64, 0, len(artistNames))
+	for _, artistName := range artistNames {
+		artist, err := s.findOrCreateArtist(ctx, artistName)
+		if err != nil {
+			return nil, err
+		}
+		artistIDs = append(artistIDs, artist.ID)
+	}
+
+	coverURL, err := s.saveCover(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+	coverColor, err := s.imageService.CoverColor(ctx, coverURL)
+	if err != nil {
+		return nil, err
+	}
+	var publishTime int64
+	if meta.Year > 0 {
+		publishTime = time.Date(meta.Year, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	}
+
+	song := &domain.Song{
+		Name:        name,
+		Album:       firstNonEmpty(info.Album, meta.Album),
+		DiscNo:      max(meta.DiscNo, 1),
+		TrackNo:     meta.TrackNo,
+		Duration:    int(meta.Duration.Round(time.Second) / time.Second),
+		PublishTime: publishTime,
+		CoverURL:    coverURL,
+		CoverColor:  coverColor,
+		Language:    info.Language,
+	}
+	if meta.Genre != "" {
+		song.Tags = []string{meta.Genre}
+	}
+	if song.Album != "" {
+		albumArtistID := int64(0)
+		if albumArtist := SplitArtists(meta.AlbumArtist); len(albumArtist) > 0 && len(info.Artists) == 0 {
+			artist, err := s.findOrCreateArtist(ctx, albumArtist[0])
+			if err != nil {
+				return nil, err
+			}
+			albumArtistID = artist.ID
+		} else if len(artistIDs) > 0 {
+			albumArtistID = artistIDs[0]
+		}
+		album, err := s.findOrCreateAlbum(ctx, albumArtistID, song.Album, coverURL, publishTime, song.Language)
+		if err != nil {
+			return nil, err
+		}
+		song.AlbumID = album.ID
+	}
+
+	if err := s.songRepo.Create(ctx, song); err != nil {
+		return nil, err
+	}
+	if len(artistIDs) > 0 {
+		if err := s.songRepo.SetArtists(ctx, song.ID, artistIDs); err != nil {
+			return nil, err
+		}
+		song.Artist = strings.Join(artistNames, " / ")
+	}
+	return song, nil
+}
+
+func (s *ImportService) findOrCreateArtist(ctx context.Context, name string) (*domain.Artist, error) {
+	artist, err := s.artistRepo.GetByName(ctx, name)
+	if err != nil || artist != nil {
+		return artist, err
+	}
+	artist = &domain.Artist{Name: name}
+	if err := s.artistRepo.Create(ctx, artist); err != nil {
+		return nil, err
+	}
+	return artist, nil
+}
+
+// findOrCreateAlbum 已有专辑没有封面或语种时补上本次上传的值
+func (s *ImportService) findOrCreateAlbum(ctx context.Context, artistID int64, name, coverURL string, releaseTime int64, language string) (*domain.Album, error) {
+	album, err := s.albumRepo.GetByArtistAndName(ctx, artistID, name)
+	if err != nil {
+		return nil, err
+	}
+	if album == nil {
+		album = &domain.Album{Name: name, ArtistID: artistID, CoverURL: coverURL, ReleaseTime: releaseTime, Language: language}
+		if err := s.albumRepo.Create(ctx, album); err != nil {
+			return nil, err
+		}
+		return album, nil
+	}
+	changed := false
+	if album.CoverURL == "" && coverURL != "" {
+		album.CoverURL, changed = coverURL, true
+	}
+	if album.Language == "" && language != "" {
+		album.Language, changed = language, true
+	}
+	if changed {
+		if err := s.albumRepo.Update(ctx, album); err != nil {
+			return nil, err
+		}
+	}
+	return album, nil
+}
+
+// saveCover 保存内嵌封面，图片无法识别时忽略
+func (s *ImportService) saveCover(ctx context.Context, meta *audiotag.Metadata) (string, error) {
+	if meta.Picture == nil {
+		return "", nil
+	}
+	obj, err := s.imageService.Upload(ctx, bytes.NewReader(meta.Picture.Data))
+	if errors.Is(err, media.ErrInvalidImage) || errors.Is(err, media.ErrImageTooLarge) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return media.ImageURL(obj.Key), nil
+}
+
+// ReadAudio 解析音频标签，格式不支持或无法解析出时长时返回 ErrInvalidAudio
+func ReadAudio(r io.ReadSeeker) (*audiotag.Metadata, error) {
+	meta, err := audiotag.Read(r)
+	if errors.Is(err, audiotag.ErrUnsupported) || errors.Is(err, audiotag.ErrMalformed) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAudio, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if meta.Duration <= 0 {
+		return nil, fmt.Errorf("%w: unknown duration", ErrInvalidAudio)
+	}
+	return meta, nil
+}
+
+// StoreAudio 在 prefix 下按内容哈希命名保存音频，同一文件只存一份
+func StoreAudio(ctx context.Context, store storage.Storage, prefix string, r io.ReadSeeker, size int64, meta *audiotag.Metadata) (*storage.Object, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	key := prefix + hex.EncodeToString(h.Sum(nil)[:16]) + audioExts[meta.Format]
+	return store.Put(ctx, key, r, size, storage.ContentTypeByKey(key))
+}
+
+// QualityOf 按格式和码率判断音质
+func QualityOf(meta *audiotag.Metadata) string {
+	switch {
+	case meta.Lossless:
+		return domain.QualityLossless
+	case meta.Bitrate >= higherBitrate:
+		return domain.QualityHigher
+	default:
+		return domain.QualityStandard
+	}
+}
+
+// SplitArtists 拆分标签中以 "/"、";"、"、" 分隔的多位歌手，去重并保持顺序
+func SplitArtists(s string) []string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == ';' || r == '、'
+	})
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" && !slices.Contains(names, part) {
+			names = append(names, part)
+		}
+	}
+	return names
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
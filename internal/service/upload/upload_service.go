@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"wyy/internal/domain"
+	"wyy/internal/repo/upload"
+	"wyy/internal/storage"
+)
+
+var (
+	ErrInvalidUpload    = errors.New("invalid upload")
+	ErrFileTooLarge     = errors.New("file too large")
+	ErrUploadNotFound   = errors.New("upload session not found")
+	ErrInvalidChunk     = errors.New("invalid chunk")
+	ErrUploadIncomplete = errors.New("upload incomplete")
+)
+
+// cleanupInterval 清理过期会话的间隔
+const cleanupInterval = time.Hour
+
+// UploadOptions 分片上传参数
+type UploadOptions struct {
+	MaxSize    int64         // 单个文件上限
+	ChunkSize  int64         // 分片大小
+	SessionTTL time.Duration // 会话有效期，过期后分片被清理
+}
+
+// UploadService 断点续传：文件按固定大小分片上传，分片暂存在存储中，可在任意时刻查询已收到的分片后继续上传
+type UploadService struct {
+	uploadRepo *repo.UploadRepo
+	storage    storage.Storage
+	opts       UploadOptions
+}
+
+func NewUploadService(uploadRepo *repo.UploadRepo, store storage.Storage, opts UploadOptions) *UploadService {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 500 << 20
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 8 << 20
+	}
+	if opts.SessionTTL <= 0 {
+		opts.SessionTTL = 24 * time.Hour
+	}
+	return &UploadService{uploadRepo: uploadRepo, storage: store, opts: opts}
+}
+
+// MaxSize 单个文件上限
+func (s *UploadService) MaxSize() int64 {
+	return s.opts.MaxSize
+}
+
+// Create 创建上传会话，userID 为上传者
+func (s *UploadService) Create(ctx context.Context, userID int64, fileName string, size int64) (*domain.UploadSession, error) {
+	fileName = path.Base(strings.ReplaceAll(strings.TrimSpace(fileName), "\\", "/"))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return nil, fmt.Errorf("%w: file_name is required", ErrInvalidUpload)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: size must be positive", ErrInvalidUpload)
+	}
+	if size > s.opts.MaxSize {
+		return nil, ErrFileTooLarge
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := &domain.UploadSession{
+		ID:         hex.EncodeToString(id),
+		UserID:     userID,
+		FileName:   fileName,
+		Size:       size,
+		ChunkSize:  s.opts.ChunkSize,
+		ChunkCount: int((size + s.opts.ChunkSize - 1) / s.opts.ChunkSize),
+		ExpiresAt:  now.Add(s.opts.SessionTTL).Unix(),
+		CreatedAt:  now.Unix(),
+	}
+	if err := s.uploadRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Get 返回会话及已收到的分片序号
+func (s *UploadService) Get(ctx context.Context, userID int64, id string) (*domain.UploadSession, []int, error) {
+	session, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	chunks, err := s.uploadRepo.ListChunks(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	received := make([]int, 0, len(chunks))
+	for _, chunk := range chunks {
+		received = append(received, chunk.ChunkIndex)
+	}
+	return session, received, nil
+}
+
+// PutChunk 保存一个分片，size 必须与该分片应有的大小一致，重传时覆盖
+func (s *UploadService) PutChunk(ctx context.Context, userID int64, id string, index int, r io.Reader, size int64) error {
+	session, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= session.ChunkCount {
+		return fmt.Errorf("%w: index out of range", ErrInvalidChunk)
+	}
+	want := session.ChunkSize
+	if index == session.ChunkCount-1 {
+		want = session.Size - session.ChunkSize*int64(index)
+	}
+	if size != want {
+		return fmt.Errorf("%w: chunk %d must be %d bytes", ErrInvalidChunk, index, want)
+	}
+	if _, err := s.storage.Put(ctx, chunkKey(id, index), r, size, "application/octet-stream"); err != nil {
+		return err
+	}
+	return s.uploadRepo.SaveChunk(ctx, &domain.UploadChunk{
+		SessionID:  id,
+		ChunkIndex: index,
+		Size:       size,
+		CreatedAt:  time.Now().Unix(),
+	})
+}
+
+// AssembledFile 合并后的临时文件，Close 时删除
+type AssembledFile struct {
+	*os.File
+	Session *domain.UploadSession
+}
+
+func (f *AssembledFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.File.Name())
+	return err
+}
+
+// Assemble 按顺序合并全部分片到临时文件，有分片缺失时返回 ErrUploadIncomplete
+func (s *UploadService) Assemble(ctx context.Context, userID int64, id string) (*AssembledFile, error) {
+	session, received, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(received) != session.ChunkCount {
+		return nil, fmt.Errorf("%w: received %d of %d chunks", ErrUploadIncomplete, len(received), session.ChunkCount)
+	}
+
+	tmp, err := os.CreateTemp("", "upload-*"+path.Ext(session.FileName))
+	if err != nil {
+		return nil, err
+	}
+	file := &AssembledFile{File: tmp, Session: session}
+	for i := 0; i < session.ChunkCount; i++ {
+		if err := s.appendChunk(ctx, tmp, id, i); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	if n, err := tmp.Seek(0, io.SeekCurrent); err != nil || n != session.Size {
+		file.Close()
+		return nil, fmt.Errorf("%w: assembled size mismatch", ErrUploadIncomplete)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *UploadService) appendChunk(ctx context.Context, w io.Writer, id string, index int) error {
+	reader, _, err := s.storage.Open(ctx, chunkKey(id, index))
+	if errors.Is(err, storage.ErrNotExist) {
+		return fmt.Errorf("%w: chunk %d missing", ErrUploadIncomplete, index)
+	}
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+// Finish 上传结束后删除分片和会话
+func (s *UploadService) Finish(ctx context.Context, id string) error {
+	session, err := s.uploadRepo.GetSession(ctx, id)
+	if err != nil || session == nil {
+		return err
+	}
+	return s.remove(ctx, session)
+}
+
+// Abort 取消上传
+func (s *UploadService) Abort(ctx context.Context, userID int64, id string) error {
+	session, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return s.remove(ctx, session)
+}
+
+// Start 定期清理过期未完成的上传，随服务启动
+func (s *UploadService) Start(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.cleanup(ctx); err != nil {
+				log.Printf("upload cleanup: %v", err)
+			}
+		}
+	}
+}
+
+func (s *UploadService) cleanup(ctx context.Context) error {
+	for {
+		sessions, err := s.uploadRepo.ListExpired(ctx, time.Now().Unix(), 100)
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if err := s.remove(ctx, session); err != nil {
+				return err
+			}
+		}
+		if len(sessions) < 100 {
+			return nil
+		}
+	}
+}
+
+func (s *UploadService) remove(ctx context.Context, session *domain.UploadSession) error {
+	for i := 0; i < session.ChunkCount; i++ {
+		if err := s.storage.Delete(ctx, chunkKey(session.ID, i)); err != nil {
+			return err
+		}
+	}
+	return s.uploadRepo.DeleteSession(ctx, session.ID)
+}
+
+// owned 会话存在、未过期且属于 userID
+func (s *UploadService) owned(ctx context.Context, userID int64, id string) (*domain.UploadSession, error) {
+	session, err := s.uploadRepo.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID || session.ExpiresAt <= time.Now().Unix() {
+		return nil, ErrUploadNotFound
+	}
+	return session, nil
+}
+
+func chunkKey(id string, index int) string {
+	return fmt.Sprintf("uploads/%s/%05d", id, index)
+}
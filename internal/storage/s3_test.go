@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"wyy/internal/config"
+)
+
+// fakeS3 内存中的 S3 替身，只实现驱动用到的对象接口，并记录收到的请求
+type fakeS3 struct {
+	t       *testing.T
+	bucket  string
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	ranges  []string
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+	etag        string
+}
+
+var authPattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=test-key/\d{8}/us-east-1/s3/aws4_request, SignedHeaders=([a-z0-9;-]+), Signature=[0-9a-f]{64}$`)
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	m := authPattern.FindStringSubmatch(auth)
+	if m == nil {
+		f.t.Errorf("%s %s: unexpected Authorization %q", r.Method, r.URL.Path, auth)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	for _, name := range []string{"host", "x-amz-content-sha256", "x-amz-date"} {
+		if !strings.Contains(";"+m[1]+";", ";"+name+";") {
+			f.t.Errorf("%s %s: %s not in SignedHeaders %q", r.Method, r.URL.Path, name, m[1])
+		}
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("X-Amz-Content-Sha256") != unsignedPayload {
+			f.t.Errorf("PUT %s: payload hash %q", key, r.Header.Get("X-Amz-Content-Sha256"))
+		}
+		data, _ := io.ReadAll(r.Body)
+		if int64(len(data)) != r.ContentLength {
+			f.t.Errorf("PUT %s: Content-Length %d, body %d bytes", key, r.ContentLength, len(data))
+		}
+		etag := strconv.Itoa(len(f.objects) + 1)
+		f.objects[key] = fakeObject{data: data, contentType: r.Header.Get("Content-Type"), etag: etag}
+		w.Header().Set("ETag", strconv.Quote(etag))
+	case http.MethodHead, http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && match != strconv.Quote(obj.etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set("ETag", strconv.Quote(obj.etag))
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Last-Modified", "Mon, 19 Oct 2026 08:00:00 GMT")
+		data := obj.data
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); rng != "" {
+			f.ranges = append(f.ranges, rng)
+			start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if err != nil || start >= len(data) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			data, status = data[start:], http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3(t *testing.T) (*S3, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{t: t, bucket: "music", objects: map[string]fakeObject{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	s, err := NewS3(config.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "music",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, fake
+}
+
+func TestS3PutStatDelete(t *testing.T) {
+	s, fake := newTestS3(t)
+	ctx := context.Background()
+
+	obj, err := s.Put(ctx, "covers/a b.jpg", strings.NewReader("jpeg data"), 9, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Key != "covers/a b.jpg" || obj.Size != 9 || obj.ETag != "1" || obj.ContentType != "image/jpeg" {
+		t.Fatalf("Put returned %+v", obj)
+	}
+	if got := string(fake.objects["covers/a b.jpg"].data); got != "jpeg data" {
+		t.Fatalf("stored %q", got)
+	}
+
+	// 大小未知时先落临时文件再上传
+	if _, err := s.Put(ctx, "audio/x.mp3", strings.NewReader("mp3 data"), -1, "audio/mpeg"); err != nil {
+		t.Fatal(err)
+	}
+	stat, err := s.Stat(ctx, "audio/x.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Size != 8 || stat.ETag != "2" || stat.ContentType != "audio/mpeg" || stat.ModTime.IsZero() {
+		t.Fatalf("Stat returned %+v", stat)
+	}
+
+	if err := s.Delete(ctx, "audio/x.mp3"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Stat(ctx, "audio/x.mp3"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("Stat after Delete: %v", err)
+	}
+}
+
+func TestS3OpenRange(t *testing.T) {
+	s, fake := newTestS3(t)
+	ctx := context.Background()
+	if _, err := s.Put(ctx, "audio/song.flac", strings.NewReader("hello world"), 11, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, obj, err := s.Open(ctx, "audio/song.flac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if obj.Size != 11 {
+		t.Fatalf("Open size %d", obj.Size)
+	}
+	if len(fake.ranges) != 0 {
+		t.Fatalf("Open sent GET before Read: %v", fake.ranges)
+	}
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(reader, head); err != nil || string(head) != "hello" {
+		t.Fatalf("read %q, %v", head, err)
+	}
+	if _, err := reader.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(reader)
+	if err != nil || string(rest) != "world" {
+		t.Fatalf("read after Seek %q, %v", rest, err)
+	}
+	if want := []string{"bytes=0-", "bytes=6-"}; strings.Join(fake.ranges, ",") != strings.Join(want, ",") {
+		t.Fatalf("ranges %v, want %v", fake.ranges, want)
+	}
+
+	// 对象在读取期间被覆盖时 If-Match 失败，不会拼接两个版本的内容
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Put(ctx, "audio/song.flac", bytes.NewReader([]byte("HELLO WORLD")), 11, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.Read(head); err == nil {
+		t.Fatal("read of replaced object succeeded")
+	}
+}
+
+func TestS3NotFound(t *testing.T) {
+	s, _ := newTestS3(t)
+	ctx := context.Background()
+	if _, err := s.Stat(ctx, "covers/missing.jpg"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("Stat: %v", err)
+	}
+	if _, _, err := s.Open(ctx, "covers/missing.jpg"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := s.Delete(ctx, "covers/missing.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
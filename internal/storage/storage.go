@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+	"wyy/internal/config"
+)
+
+var ErrNotExist = errors.New("object not found")
+
+// 存储驱动
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// New 按配置创建存储，driver 为空时使用本地存储
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocal(cfg.LocalRoot)
+	case DriverS3:
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// Object 存储对象的元信息
+type Object struct {
+	Key         string
+	Size        int64
+	ModTime     time.Time
+	ETag        string // 不含引号
+	ContentType string // 可能为空，由调用方按扩展名或内容判断
+}
+
+// Storage 对象存储，音频、图片等文件均通过它读写
+type Storage interface {
+	// Open 打开对象用于读取，返回的 Reader 支持 Seek 以便按 Range 读取
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, *Object, error)
+
+	// Stat 获取对象元信息，不存在时返回 ErrNotExist
+	Stat(ctx context.Context, key string) (*Object, error)
+
+	// Put 写入对象，已存在时覆盖；size 为内容字节数，contentType 为空时按扩展名推断
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*Object, error)
+
+	// Delete 删除对象，对象不存在时不报错
+	Delete(ctx context.Context, key string) error
+}
+
+// 常见音频格式，部分系统的 mime 库缺少这些类型
+var audioTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+	".mp4":  "audio/mp4",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".wav":  "audio/wav",
+}
+
+// ContentTypeByKey 按扩展名推断内容类型，无法判断时返回空字符串
+func ContentTypeByKey(key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	if t, ok := audioTypes[ext]; ok {
+		return t
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// CleanKey 规整对象 key，拒绝跳出根目录的路径
+func CleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." {
+		return "", errors.New("empty object key")
+	}
+	return cleaned, nil
+}